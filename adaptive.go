@@ -0,0 +1,123 @@
+package httpExporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// AdaptiveBatchingConfig defines the bounds of adaptive batch sizing.
+type AdaptiveBatchingConfig struct {
+	// MinSpans is the smallest number of spans per request. Defaults to 16.
+	MinSpans int
+	// MaxSpans is the largest number of spans per request, and the initial
+	// one. Defaults to 2048.
+	MaxSpans int
+	// TargetLatency is the response time above which the collector is
+	// considered under pressure. Defaults to 1 second.
+	TargetLatency time.Duration
+}
+
+// WithAdaptiveBatching configures the exporter to adjust the number of spans
+// per request from the feedback of the primary collector: the limit is
+// halved when a request is rejected as too large (413), the collector is
+// unavailable or throttling, responds slower than the target latency or
+// rejects part of a batch, and grows by a quarter after each healthy request
+// of a full sized batch. Batches larger than the limit are split.
+func WithAdaptiveBatching(ac AdaptiveBatchingConfig) Option {
+	return optionFunc(func(cfg config) config {
+		if ac.MinSpans <= 0 {
+			ac.MinSpans = 16
+		}
+		if ac.MaxSpans <= 0 {
+			ac.MaxSpans = 2048
+		}
+		if ac.MaxSpans < ac.MinSpans {
+			ac.MaxSpans = ac.MinSpans
+		}
+		if ac.TargetLatency <= 0 {
+			ac.TargetLatency = time.Second
+		}
+		cfg.adaptiveBatching = &ac
+		return cfg
+	})
+}
+
+// adaptiveBatcher tracks the number of spans per request.
+type adaptiveBatcher struct {
+	AdaptiveBatchingConfig
+
+	mu    sync.Mutex
+	limit int
+}
+
+func newAdaptiveBatcher(ac AdaptiveBatchingConfig) *adaptiveBatcher {
+	return &adaptiveBatcher{AdaptiveBatchingConfig: ac, limit: ac.MaxSpans}
+}
+
+// current returns the current number of spans per request.
+func (a *adaptiveBatcher) current() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.limit
+}
+
+// record adjusts the limit from the outcome of a request of n spans, and
+// returns the new limit and whether it changed.
+func (a *adaptiveBatcher) record(n int, d time.Duration, err error, rejected int) (int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	old := a.limit
+	var rerr *ErrPayloadRejected
+	switch {
+	case errors.As(err, &rerr) && rerr.Status == http.StatusRequestEntityTooLarge:
+		if n/2 < a.limit {
+			a.limit = n / 2
+		}
+	case errors.Is(err, errCircuitOpen):
+		// The collector was not contacted.
+	case unavailable(err) || d > a.TargetLatency || rejected > 0:
+		a.limit /= 2
+	case err == nil && n >= a.limit:
+		a.limit += a.limit/4 + 1
+	}
+	if a.limit < a.MinSpans {
+		a.limit = a.MinSpans
+	}
+	if a.limit > a.MaxSpans {
+		a.limit = a.MaxSpans
+	}
+	return a.limit, a.limit != old
+}
+
+// exportChunks exports spans in chunks of at most limit spans with
+// exportBatch, returning the first error.
+func (e *Exporter) exportChunks(ctx context.Context, spans []sdktrace.ReadOnlySpan, limit int) error {
+	var firstErr error
+	for len(spans) > 0 {
+		n := limit
+		if n > len(spans) {
+			n = len(spans)
+		}
+		if err := e.exportBatch(ctx, spans[:n]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		spans = spans[n:]
+	}
+	return firstErr
+}
+
+// adapt feeds the outcome of a request to the primary collector to adaptive
+// batch sizing.
+func (e *Exporter) adapt(n int, d time.Duration, err error, rejected int) {
+	if e.adaptive == nil {
+		return
+	}
+	if limit, changed := e.adaptive.record(n, d, err, rejected); changed {
+		e.logf("adaptive batching: sending up to %d spans per request", limit)
+	}
+}
@@ -0,0 +1,82 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// sizes returns the number of spans of each request received by c.
+func (c *collector) sizes(t *testing.T) []int {
+	t.Helper()
+	var sizes []int
+	for _, r := range c.received() {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(r.Body, &batch); err != nil {
+			t.Fatalf("invalid batch %s: %v", r.Body, err)
+		}
+		sizes = append(sizes, len(batch))
+	}
+	return sizes
+}
+
+// equalInts reports whether got and want hold the same ints in the same
+// order.
+func equalInts(got []int, want ...int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAdaptiveBatching(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithAdaptiveBatching(httpExporter.AdaptiveBatchingConfig{MinSpans: 2, MaxSpans: 8}))
+	ctx := context.Background()
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	// An unavailable collector halves the number of spans per request.
+	c.respond(http.StatusServiceUnavailable)
+	if err := e.ExportSpans(ctx, newSpans(names...)); err == nil {
+		t.Fatal("ExportSpans succeeded with a 503 response")
+	}
+	if err := e.ExportSpans(ctx, newSpans(names...)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// A healthy request of a full sized batch grows it again, by a quarter.
+	if err := e.ExportSpans(ctx, newSpans(names...)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.sizes(t); !equalInts(got, 8, 4, 4, 6, 2) {
+		t.Errorf("requests of %v spans, want [8 4 4 6 2]", got)
+	}
+}
+
+func TestAdaptiveBatchingLatency(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithAdaptiveBatching(httpExporter.AdaptiveBatchingConfig{
+		MinSpans:      2,
+		MaxSpans:      4,
+		TargetLatency: 20 * time.Millisecond,
+	}))
+	ctx := context.Background()
+	c.setLatency(50 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if err := e.ExportSpans(ctx, newSpans("a", "b", "c", "d")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	// Slow responses halve the limit down to MinSpans.
+	if got := c.sizes(t); !equalInts(got, 4, 2, 2, 2, 2) {
+		t.Errorf("requests of %v spans, want [4 2 2 2 2]", got)
+	}
+}
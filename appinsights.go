@@ -0,0 +1,261 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// appInsightsTrackPath is the Application Insights track API path.
+const appInsightsTrackPath = "/v2/track"
+
+// appInsightsEncoder encodes spans as an Application Insights track request:
+// server and consumer spans become requests, other spans dependencies and
+// span events messages.
+type appInsightsEncoder struct {
+	iKey string
+}
+
+func (appInsightsEncoder) contentType() string { return "application/json" }
+
+func (appInsightsEncoder) defaultPath() string { return appInsightsTrackPath }
+
+func (enc appInsightsEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	envelopes := make([]appInsightsEnvelope, 0, len(spans))
+	for _, span := range spans {
+		envelopes = enc.appendEnvelopes(envelopes, span)
+	}
+	return json.Marshal(envelopes)
+}
+
+type appInsightsEnvelope struct {
+	Name string            `json:"name"`
+	Time string            `json:"time"`
+	IKey string            `json:"iKey"`
+	Tags map[string]string `json:"tags"`
+	Data appInsightsData   `json:"data"`
+}
+
+type appInsightsData struct {
+	BaseType string      `json:"baseType"`
+	BaseData interface{} `json:"baseData"`
+}
+
+type appInsightsRequest struct {
+	Ver          int               `json:"ver"`
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Duration     string            `json:"duration"`
+	ResponseCode string            `json:"responseCode"`
+	Success      bool              `json:"success"`
+	URL          string            `json:"url,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+type appInsightsDependency struct {
+	Ver        int               `json:"ver"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Duration   string            `json:"duration"`
+	ResultCode string            `json:"resultCode,omitempty"`
+	Success    bool              `json:"success"`
+	Data       string            `json:"data,omitempty"`
+	Target     string            `json:"target,omitempty"`
+	Type       string            `json:"type"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type appInsightsMessage struct {
+	Ver        int               `json:"ver"`
+	Message    string            `json:"message"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// appendEnvelopes appends the envelopes of a span and of its events.
+func (enc appInsightsEncoder) appendEnvelopes(envelopes []appInsightsEnvelope, span sdktrace.ReadOnlySpan) []appInsightsEnvelope {
+	sc := span.SpanContext()
+	attrs := attribute.NewSet(span.Attributes()...)
+	tags := appInsightsTags(span)
+	tags["ai.operation.name"] = span.Name()
+	if psid := span.Parent().SpanID(); psid.IsValid() {
+		tags["ai.operation.parentId"] = psid.String()
+	}
+
+	success := span.Status().Code != codes.Error
+	duration := appInsightsDuration(span.EndTime().Sub(span.StartTime()))
+	statusCode := ""
+	if v, ok := attrs.Value(semconv.HTTPStatusCodeKey); ok {
+		statusCode = v.Emit()
+	}
+	env := appInsightsEnvelope{
+		Time: appInsightsTime(span.StartTime()),
+		IKey: enc.iKey,
+		Tags: tags,
+	}
+	switch span.SpanKind() {
+	case trace.SpanKindServer, trace.SpanKindConsumer:
+		if statusCode == "" {
+			statusCode = "0"
+		}
+		env.Name = "Microsoft.ApplicationInsights.Request"
+		env.Data = appInsightsData{BaseType: "RequestData", BaseData: appInsightsRequest{
+			Ver:          2,
+			ID:           sc.SpanID().String(),
+			Name:         span.Name(),
+			Duration:     duration,
+			ResponseCode: statusCode,
+			Success:      success,
+			URL:          attributeString(attrs, semconv.HTTPURLKey),
+			Properties:   appInsightsProperties(span.Attributes()),
+		}}
+	default:
+		depType, target, data := appInsightsDependencyDetails(span.SpanKind(), attrs)
+		env.Name = "Microsoft.ApplicationInsights.RemoteDependency"
+		env.Data = appInsightsData{BaseType: "RemoteDependencyData", BaseData: appInsightsDependency{
+			Ver:        2,
+			ID:         sc.SpanID().String(),
+			Name:       span.Name(),
+			Duration:   duration,
+			ResultCode: statusCode,
+			Success:    success,
+			Data:       data,
+			Target:     target,
+			Type:       depType,
+			Properties: appInsightsProperties(span.Attributes()),
+		}}
+	}
+	envelopes = append(envelopes, env)
+
+	for _, ev := range span.Events() {
+		evTags := appInsightsTags(span)
+		evTags["ai.operation.name"] = span.Name()
+		evTags["ai.operation.parentId"] = sc.SpanID().String()
+		envelopes = append(envelopes, appInsightsEnvelope{
+			Name: "Microsoft.ApplicationInsights.Message",
+			Time: appInsightsTime(ev.Time),
+			IKey: enc.iKey,
+			Tags: evTags,
+			Data: appInsightsData{BaseType: "MessageData", BaseData: appInsightsMessage{
+				Ver:        2,
+				Message:    ev.Name,
+				Properties: appInsightsProperties(ev.Attributes),
+			}},
+		})
+	}
+	return envelopes
+}
+
+// appInsightsTags returns the context tags shared by the envelopes of a
+// span: its trace and the role of its service.
+func appInsightsTags(span sdktrace.ReadOnlySpan) map[string]string {
+	tags := map[string]string{
+		"ai.operation.id": span.SpanContext().TraceID().String(),
+	}
+	res := span.Resource().Set()
+	if name := serviceName(span.Resource()); name != "" {
+		if ns, ok := res.Value(semconv.ServiceNamespaceKey); ok && ns.AsString() != "" {
+			name = "[" + ns.AsString() + "]/" + name
+		}
+		tags["ai.cloud.role"] = name
+	}
+	if v, ok := res.Value(semconv.ServiceInstanceIDKey); ok {
+		tags["ai.cloud.roleInstance"] = v.Emit()
+	} else if v, ok := res.Value(semconv.HostNameKey); ok {
+		tags["ai.cloud.roleInstance"] = v.Emit()
+	}
+	return tags
+}
+
+// appInsightsDependencyDetails returns the type, target and command of a
+// dependency from the semantic convention attributes of its span.
+func appInsightsDependencyDetails(kind trace.SpanKind, attrs attribute.Set) (depType, target, data string) {
+	target = attributeString(attrs, semconv.NetPeerNameKey)
+	if port, ok := attrs.Value(semconv.NetPeerPortKey); ok && target != "" {
+		target += ":" + port.Emit()
+	}
+	switch {
+	case attrs.HasValue(semconv.HTTPMethodKey):
+		depType = "HTTP"
+		data = attributeString(attrs, semconv.HTTPURLKey)
+		if u, err := url.Parse(data); err == nil && u.Host != "" && target == "" {
+			target = u.Host
+		}
+	case attrs.HasValue(semconv.DBSystemKey):
+		depType = attributeString(attrs, semconv.DBSystemKey)
+		data = attributeString(attrs, semconv.DBStatementKey)
+		if name := attributeString(attrs, semconv.DBNameKey); name != "" {
+			if target != "" {
+				target += " | "
+			}
+			target += name
+		}
+	case attrs.HasValue(semconv.MessagingSystemKey):
+		depType = "Queue Message | " + attributeString(attrs, semconv.MessagingSystemKey)
+		if dest := attributeString(attrs, semconv.MessagingDestinationKey); dest != "" {
+			target = dest
+		}
+	case attrs.HasValue(semconv.RPCSystemKey):
+		depType = attributeString(attrs, semconv.RPCSystemKey)
+	case kind == trace.SpanKindInternal:
+		depType = "InProc"
+	default:
+		depType = "Dependency"
+	}
+	return depType, target, data
+}
+
+// appInsightsProperties converts attributes to the string properties of
+// Application Insights.
+func appInsightsProperties(attrs []attribute.KeyValue) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	props := make(map[string]string, len(attrs))
+	for _, kv := range attrs {
+		props[string(kv.Key)] = attributeValueString(kv.Value)
+	}
+	return props
+}
+
+// attributeString returns the value of an attribute as a string, or an empty
+// string if it is not set.
+func attributeString(attrs attribute.Set, key attribute.Key) string {
+	if v, ok := attrs.Value(key); ok {
+		return attributeValueString(v)
+	}
+	return ""
+}
+
+func appInsightsTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// appInsightsDuration formats d as a .NET TimeSpan: d.hh:mm:ss.fffffff.
+func appInsightsDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	ticks := d / 100 // 100ns units
+	return fmt.Sprintf("%d.%02d:%02d:%02d.%07d",
+		d/(24*time.Hour),
+		d/time.Hour%24,
+		d/time.Minute%60,
+		d/time.Second%60,
+		ticks%10000000)
+}
+
+// WithInstrumentationKey configures the Application Insights instrumentation
+// key set on the envelopes of the ApplicationInsights format.
+func WithInstrumentationKey(key string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.instrumentationKey = key
+		return cfg
+	})
+}
@@ -0,0 +1,25 @@
+package httpExporter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplicationInsights(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(ApplicationInsights), WithInstrumentationKey("ikey"))
+	checkEncoder(t, enc, "application/json", "/v2/track")
+	checkGoldenJSON(t, "appinsights.json", body)
+}
+
+func TestAppInsightsDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                       "0.00:00:00.0000000",
+		1500 * time.Microsecond: "0.00:00:00.0015000",
+		26*time.Hour + 3*time.Minute + 4*time.Second: "1.02:03:04.0000000",
+		-time.Second: "0.00:00:00.0000000",
+	} {
+		if got := appInsightsDuration(d); got != want {
+			t.Errorf("appInsightsDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package httpExporter
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithAttributeAllowlist configures the span, event, link and resource
+// attributes that are exported; all others are removed before encoding. Keys
+// ending with "*" match every key with the preceding prefix, others match
+// exactly, for instance "http.*" and "service.name". Note that resource
+// attributes such as service.name must be allowed too. When used multiple
+// times, attributes must be allowed by every list.
+func WithAttributeAllowlist(keys ...string) Option {
+	return optionFunc(func(cfg config) config {
+		m := newKeyMatcher(keys)
+		cfg.attributeTransforms = append(append([]attributeTransform{}, cfg.attributeTransforms...),
+			func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+				return kv, m.match(string(kv.Key))
+			})
+		return cfg
+	})
+}
+
+// WithAttributeDenylist configures span, event, link and resource attributes
+// that are removed before encoding. Keys ending with "*" match every key with
+// the preceding prefix, others match exactly, for instance
+// "http.request.header.*" and "enduser.id".
+func WithAttributeDenylist(keys ...string) Option {
+	return optionFunc(func(cfg config) config {
+		m := newKeyMatcher(keys)
+		cfg.attributeTransforms = append(append([]attributeTransform{}, cfg.attributeTransforms...),
+			func(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+				return kv, !m.match(string(kv.Key))
+			})
+		return cfg
+	})
+}
+
+// keyMatcher matches attribute keys against exact keys and key prefixes.
+type keyMatcher struct {
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+func newKeyMatcher(keys []string) keyMatcher {
+	m := keyMatcher{exact: make(map[string]struct{}, len(keys))}
+	for _, k := range keys {
+		if strings.HasSuffix(k, "*") {
+			m.prefixes = append(m.prefixes, strings.TrimSuffix(k, "*"))
+			continue
+		}
+		m.exact[k] = struct{}{}
+	}
+	return m
+}
+
+func (m keyMatcher) match(key string) bool {
+	if _, ok := m.exact[key]; ok {
+		return true
+	}
+	for _, p := range m.prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
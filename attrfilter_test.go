@@ -0,0 +1,50 @@
+package httpExporter_test
+
+import (
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestAttributeAllowlist(t *testing.T) {
+	res := resource.NewSchemaless(attribute.String("service.name", "checkout"), attribute.String("host.name", "web-1"))
+	attrs, resAttrs := exportAttributes(t, res, []attribute.KeyValue{
+		attribute.String("http.method", "GET"),
+		attribute.Int("http.status_code", 200),
+		attribute.String("user.id", "42"),
+	}, httpExporter.WithAttributeAllowlist("http.*", "service.name"))
+
+	if len(attrs) != 2 || attrs["http.method"] != "GET" || attrs["http.status_code"] != 200.0 {
+		t.Errorf("attributes = %v, want the http ones", attrs)
+	}
+	if len(resAttrs) != 1 || resAttrs["service.name"] != "checkout" {
+		t.Errorf("resource = %v, want service.name only", resAttrs)
+	}
+}
+
+func TestAttributeAllowlists(t *testing.T) {
+	attrs, _ := exportAttributes(t, nil, []attribute.KeyValue{
+		attribute.String("http.method", "GET"),
+		attribute.String("http.url", "/cart"),
+	},
+		httpExporter.WithAttributeAllowlist("http.*"),
+		httpExporter.WithAttributeAllowlist("http.method"),
+	)
+	if len(attrs) != 1 || attrs["http.method"] != "GET" {
+		t.Errorf("attributes = %v, want those allowed by both lists", attrs)
+	}
+}
+
+func TestAttributeDenylist(t *testing.T) {
+	attrs, _ := exportAttributes(t, nil, []attribute.KeyValue{
+		attribute.String("http.request.header.cookie", "session=1"),
+		attribute.String("enduser.id", "42"),
+		attribute.String("enduser.idp", "sso"),
+		attribute.String("http.method", "GET"),
+	}, httpExporter.WithAttributeDenylist("http.request.header.*", "enduser.id"))
+	if len(attrs) != 2 || attrs["enduser.idp"] != "sso" || attrs["http.method"] != "GET" {
+		t.Errorf("attributes = %v, want enduser.idp and http.method", attrs)
+	}
+}
@@ -0,0 +1,454 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+)
+
+// Attributes is an ordered list of key-values, encoded in JSON as an object
+// mapping each key to its value, in order. Converting a span shares its
+// attributes rather than copying them into a map, and values are encoded
+// from their type without being boxed in interfaces.
+type Attributes []attribute.KeyValue
+
+// Value returns the value of the last attribute with key, and whether there
+// is one.
+func (a Attributes) Value(key attribute.Key) (attribute.Value, bool) {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].Key == key {
+			return a[i].Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// MarshalJSON encodes the attributes as a JSON object. Attributes without a
+// value are encoded as null.
+func (a Attributes) MarshalJSON() ([]byte, error) {
+	return a.appendJSON(make([]byte, 0, 2+32*len(a)))
+}
+
+func (a Attributes) appendJSON(b []byte) ([]byte, error) {
+	b = append(b, '{')
+	for i, kv := range a {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, string(kv.Key))
+		b = append(b, ':')
+		var err error
+		if b, err = appendJSONValue(b, kv.Value); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendJSONValue appends the JSON encoding of an attribute value.
+func appendJSONValue(b []byte, v attribute.Value) ([]byte, error) {
+	var err error
+	switch v.Type() {
+	case attribute.BOOL:
+		b = strconv.AppendBool(b, v.AsBool())
+	case attribute.INT64:
+		b = strconv.AppendInt(b, v.AsInt64(), 10)
+	case attribute.FLOAT64:
+		b, err = appendJSONFloat(b, v.AsFloat64())
+	case attribute.STRING:
+		b = appendJSONString(b, v.AsString())
+	case attribute.BOOLSLICE:
+		b = append(b, '[')
+		for i, x := range v.AsBoolSlice() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = strconv.AppendBool(b, x)
+		}
+		b = append(b, ']')
+	case attribute.INT64SLICE:
+		b = append(b, '[')
+		for i, x := range v.AsInt64Slice() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = strconv.AppendInt(b, x, 10)
+		}
+		b = append(b, ']')
+	case attribute.FLOAT64SLICE:
+		b = append(b, '[')
+		for i, x := range v.AsFloat64Slice() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = appendJSONFloat(b, x); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	case attribute.STRINGSLICE:
+		b = append(b, '[')
+		for i, x := range v.AsStringSlice() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, x)
+		}
+		b = append(b, ']')
+	default:
+		b = append(b, "null"...)
+	}
+	return b, err
+}
+
+// appendJSONFloat appends a float as encoding/json does, failing for NaN and
+// infinities.
+func appendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, &json.UnsupportedValueError{Value: reflect.ValueOf(f), Str: strconv.FormatFloat(f, 'g', -1, 64)}
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string. Invalid UTF-8 is replaced
+// with U+FFFD; HTML characters are left to the escaping of encoding/json.
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			// Line separators are valid JSON but not JavaScript.
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// UnmarshalJSON decodes attributes from a JSON object, in order. Numbers
+// become integers when they are integral, arrays typed slices when their
+// elements share a type, and other values their JSON encoding as a string.
+func (a *Attributes) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		*a = nil
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return errors.New("attributes must be a JSON object")
+	}
+	attrs := Attributes{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		kv, err := attributeFromJSON(attribute.Key(key), raw)
+		if err != nil {
+			return err
+		}
+		attrs = append(attrs, kv)
+	}
+	*a = attrs
+	return nil
+}
+
+// attributeFromJSON decodes an attribute from its JSON value.
+func attributeFromJSON(key attribute.Key, raw json.RawMessage) (attribute.KeyValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return attribute.KeyValue{}, err
+	}
+	switch v := v.(type) {
+	case nil:
+		return attribute.KeyValue{Key: key}, nil
+	case bool:
+		return key.Bool(v), nil
+	case string:
+		return key.String(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return key.Int64(i), nil
+		}
+		f, err := v.Float64()
+		return key.Float64(f), err
+	case []interface{}:
+		if kv, ok := sliceAttribute(key, v); ok {
+			return kv, nil
+		}
+	}
+	return key.String(string(raw)), nil
+}
+
+// sliceAttribute returns the typed slice attribute of JSON array elements,
+// if they share a type.
+func sliceAttribute(key attribute.Key, elems []interface{}) (attribute.KeyValue, bool) {
+	if len(elems) == 0 {
+		return key.StringSlice([]string{}), true
+	}
+	switch elems[0].(type) {
+	case bool:
+		s := make([]bool, 0, len(elems))
+		for _, e := range elems {
+			x, ok := e.(bool)
+			if !ok {
+				return attribute.KeyValue{}, false
+			}
+			s = append(s, x)
+		}
+		return key.BoolSlice(s), true
+	case string:
+		s := make([]string, 0, len(elems))
+		for _, e := range elems {
+			x, ok := e.(string)
+			if !ok {
+				return attribute.KeyValue{}, false
+			}
+			s = append(s, x)
+		}
+		return key.StringSlice(s), true
+	case json.Number:
+		ints := make([]int64, 0, len(elems))
+		floats := make([]float64, 0, len(elems))
+		integral := true
+		for _, e := range elems {
+			n, ok := e.(json.Number)
+			if !ok {
+				return attribute.KeyValue{}, false
+			}
+			f, err := n.Float64()
+			if err != nil {
+				return attribute.KeyValue{}, false
+			}
+			floats = append(floats, f)
+			if i, err := n.Int64(); err == nil && integral {
+				ints = append(ints, i)
+			} else {
+				integral = false
+			}
+		}
+		if integral {
+			return key.Int64Slice(ints), true
+		}
+		return key.Float64Slice(floats), true
+	}
+	return attribute.KeyValue{}, false
+}
+
+// LogAttributes is an ordered list of log record attributes, encoded in JSON
+// as an object mapping each key to its value, in order. Unlike Attributes,
+// values may be maps and heterogeneous slices, encoded as nested objects and
+// arrays. Bytes are encoded as base64 strings, as encoding/json does.
+type LogAttributes []log.KeyValue
+
+// Value returns the value of the last attribute with key, and whether there
+// is one.
+func (a LogAttributes) Value(key string) (log.Value, bool) {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].Key == key {
+			return a[i].Value, true
+		}
+	}
+	return log.Value{}, false
+}
+
+// MarshalJSON encodes the attributes as a JSON object. Attributes without a
+// value are encoded as null.
+func (a LogAttributes) MarshalJSON() ([]byte, error) {
+	return appendLogJSONMap(make([]byte, 0, 2+32*len(a)), a)
+}
+
+// appendLogJSONMap appends key-values as a JSON object, in order.
+func appendLogJSONMap(b []byte, kvs []log.KeyValue) ([]byte, error) {
+	b = append(b, '{')
+	for i, kv := range kvs {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, kv.Key)
+		b = append(b, ':')
+		var err error
+		if b, err = appendLogJSONValue(b, kv.Value); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendLogJSONValue appends the JSON encoding of a log value.
+func appendLogJSONValue(b []byte, v log.Value) ([]byte, error) {
+	var err error
+	switch v.Kind() {
+	case log.KindBool:
+		b = strconv.AppendBool(b, v.AsBool())
+	case log.KindInt64:
+		b = strconv.AppendInt(b, v.AsInt64(), 10)
+	case log.KindFloat64:
+		b, err = appendJSONFloat(b, v.AsFloat64())
+	case log.KindString:
+		b = appendJSONString(b, v.AsString())
+	case log.KindBytes:
+		b = append(b, '"')
+		b = base64.StdEncoding.AppendEncode(b, v.AsBytes())
+		b = append(b, '"')
+	case log.KindSlice:
+		b = append(b, '[')
+		for i, item := range v.AsSlice() {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = appendLogJSONValue(b, item); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	case log.KindMap:
+		b, err = appendLogJSONMap(b, v.AsMap())
+	default:
+		b = append(b, "null"...)
+	}
+	return b, err
+}
+
+// UnmarshalJSON decodes attributes from a JSON object, in order. Numbers
+// become integers when they are integral, objects maps and arrays slices.
+// Bytes, encoded as strings, are decoded as strings.
+func (a *LogAttributes) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := logValueFromJSON(dec)
+	if err != nil {
+		return err
+	}
+	switch v.Kind() {
+	case log.KindEmpty:
+		*a = nil
+	case log.KindMap:
+		*a = append(LogAttributes{}, v.AsMap()...)
+	default:
+		return errors.New("attributes must be a JSON object")
+	}
+	return nil
+}
+
+// logValueFromJSON decodes the next JSON value of dec as a log value,
+// keeping the order of object fields.
+func logValueFromJSON(dec *json.Decoder) (log.Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return log.Value{}, err
+	}
+	switch tok := tok.(type) {
+	case nil:
+		return log.Value{}, nil
+	case bool:
+		return log.BoolValue(tok), nil
+	case string:
+		return log.StringValue(tok), nil
+	case json.Number:
+		if i, err := tok.Int64(); err == nil {
+			return log.Int64Value(i), nil
+		}
+		f, err := tok.Float64()
+		return log.Float64Value(f), err
+	case json.Delim:
+		if tok == '[' {
+			values := []log.Value{}
+			for dec.More() {
+				v, err := logValueFromJSON(dec)
+				if err != nil {
+					return log.Value{}, err
+				}
+				values = append(values, v)
+			}
+			_, err := dec.Token()
+			return log.SliceValue(values...), err
+		}
+		kvs := []log.KeyValue{}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return log.Value{}, err
+			}
+			key, _ := tok.(string)
+			v, err := logValueFromJSON(dec)
+			if err != nil {
+				return log.Value{}, err
+			}
+			kvs = append(kvs, log.KeyValue{Key: key, Value: v})
+		}
+		_, err := dec.Token()
+		return log.MapValue(kvs...), err
+	}
+	return log.Value{}, fmt.Errorf("unexpected JSON token %v", tok)
+}
@@ -0,0 +1,199 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+)
+
+// benchmarkAttributes returns n attributes of mixed types, as found on
+// instrumented spans.
+func benchmarkAttributes(n int) Attributes {
+	attrs := make(Attributes, 0, n)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("attribute.key.%d", i)
+		switch i % 5 {
+		case 0:
+			attrs = append(attrs, attribute.String(key, "a typical string value"))
+		case 1:
+			attrs = append(attrs, attribute.Int64(key, int64(i)*1000))
+		case 2:
+			attrs = append(attrs, attribute.Float64(key, float64(i)+0.25))
+		case 3:
+			attrs = append(attrs, attribute.Bool(key, i%2 == 0))
+		default:
+			attrs = append(attrs, attribute.StringSlice(key, []string{"first", "second"}))
+		}
+	}
+	return attrs
+}
+
+// attributesToMap converts attributes to the map of boxed values SpanData
+// held before Attributes.
+func attributesToMap(attrs []attribute.KeyValue) map[attribute.Key]interface{} {
+	m := make(map[attribute.Key]interface{}, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+func TestAttributesMarshalJSON(t *testing.T) {
+	for _, kv := range []attribute.KeyValue{
+		attribute.String("s", "plain"),
+		attribute.String("s", "quotes \" and \\ slashes"),
+		attribute.String("s", "controls \n\r\t\x00\x1f"),
+		attribute.String("s", "html <&> and separators \u2028\u2029"),
+		attribute.String("s", "invalid \xff utf-8"),
+		attribute.String("s", "unicode \u00e9\U0001F600"),
+		attribute.Int64("i", -42),
+		attribute.Float64("f", 0.1),
+		attribute.Float64("f", 1e21),
+		attribute.Float64("f", 1e-7),
+		attribute.Float64("f", -0.0),
+		attribute.Bool("b", true),
+		attribute.BoolSlice("bs", []bool{true, false}),
+		attribute.Int64Slice("is", []int64{1, -2}),
+		attribute.Float64Slice("fs", []float64{1.5, 2e-9}),
+		attribute.StringSlice("ss", []string{"a", "\"b\""}),
+	} {
+		// json.Marshal escapes HTML characters in the output of MarshalJSON.
+		got, err := json.Marshal(Attributes{kv})
+		if err != nil {
+			t.Fatalf("MarshalJSON(%v): %v", kv, err)
+		}
+		want, err := json.Marshal(attributesToMap([]attribute.KeyValue{kv}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("MarshalJSON(%v) = %s, want %s", kv, got, want)
+		}
+	}
+}
+
+func TestAttributesMarshalJSONOrder(t *testing.T) {
+	attrs := Attributes{attribute.Int("b", 1), attribute.Int("a", 2), {Key: "c"}}
+	got, err := json.Marshal(attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"b":1,"a":2,"c":null}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestAttributesUnmarshalJSON(t *testing.T) {
+	want := benchmarkAttributes(10)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Attributes
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d attributes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("attribute %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLogAttributesMarshalJSON(t *testing.T) {
+	for _, kv := range []log.KeyValue{
+		log.String("s", "quotes \" and \\ slashes"),
+		log.Int("i", -42),
+		log.Float64("f", 1e-7),
+		log.Bool("b", true),
+		log.Bytes("bytes", []byte("raw\x00")),
+		log.Slice("slice", log.IntValue(1), log.StringValue("two"), log.BoolValue(false)),
+		log.Map("map", log.String("brand", "visa"), log.Map("card", log.Bool("expired", true))),
+		{Key: "empty"},
+	} {
+		got, err := json.Marshal(LogAttributes{kv})
+		if err != nil {
+			t.Fatalf("MarshalJSON(%v): %v", kv, err)
+		}
+		want, err := json.Marshal(map[string]interface{}{kv.Key: logValueToInterface(kv.Value)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("MarshalJSON(%v) = %s, want %s", kv, got, want)
+		}
+	}
+}
+
+func TestLogAttributesUnmarshalJSON(t *testing.T) {
+	want := LogAttributes{
+		log.String("z", "first"),
+		log.Int("attempt", 2),
+		log.Float64("ratio", 0.5),
+		log.Slice("mixed", log.IntValue(1), log.StringValue("two")),
+		log.Map("card", log.String("brand", "visa"), log.Bool("expired", true)),
+		{Key: "empty"},
+	}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got LogAttributes
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d attributes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("attribute %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if v, ok := got.Value("attempt"); !ok || v.AsInt64() != 2 {
+		t.Errorf("Value(attempt) = %v, %v", v, ok)
+	}
+}
+
+// BenchmarkAttributesJSON compares encoding the attributes of a span as the
+// map SpanData held before, converted from the span and encoded by
+// encoding/json, with encoding them as Attributes.
+func BenchmarkAttributesJSON(b *testing.B) {
+	for _, n := range []int{5, 20, 100} {
+		attrs := benchmarkAttributes(n)
+		b.Run(fmt.Sprintf("map/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := json.Marshal(attributesToMap(attrs)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("slice/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := json.Marshal(attrs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkAttributesMarshalJSON measures Attributes.MarshalJSON alone,
+// without the validation and copy of its output by json.Marshal.
+func BenchmarkAttributesMarshalJSON(b *testing.B) {
+	attrs := benchmarkAttributes(20)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := attrs.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
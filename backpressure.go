@@ -0,0 +1,109 @@
+package httpExporter
+
+import (
+	"context"
+	"time"
+)
+
+// BackpressurePolicy defines what ExportSpans does when the exporter is
+// saturated: its queue is full, the circuit breaker is open or the maximum
+// number of concurrent exports is reached.
+type BackpressurePolicy int
+
+const (
+	// BackpressureError keeps the default behavior of each mechanism: the
+	// queue applies its QueuePolicy, exports wait for a concurrent export
+	// slot and batches short-circuited by the circuit breaker fail with an
+	// error matching ErrCollectorUnavailable.
+	BackpressureError BackpressurePolicy = iota
+	// BackpressureBlock waits for capacity, up to the export context being
+	// done: for room in the queue, for a concurrent export slot or for the
+	// circuit breaker to let a batch through.
+	BackpressureBlock
+	// BackpressureDrop drops the batch without failing the export and counts
+	// its spans in Stats.SpansDropped: a queue configured to Block drops the
+	// oldest batch instead, and exports neither wait for a concurrent export
+	// slot nor fail while the circuit is open.
+	BackpressureDrop
+)
+
+// String returns the name of the backpressure policy.
+func (p BackpressurePolicy) String() string {
+	switch p {
+	case BackpressureError:
+		return "error"
+	case BackpressureBlock:
+		return "block"
+	case BackpressureDrop:
+		return "drop"
+	}
+	return "unknown"
+}
+
+// WithBackpressure configures what ExportSpans does when the exporter is
+// saturated. It takes precedence over the policy passed to WithQueue.
+func WithBackpressure(policy BackpressurePolicy) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.backpressure = policy
+		return cfg
+	})
+}
+
+// queuePolicy returns the policy of the queue under the backpressure policy.
+func (p BackpressurePolicy) queuePolicy(policy QueuePolicy) QueuePolicy {
+	switch {
+	case p == BackpressureBlock:
+		return Block
+	case p == BackpressureDrop && policy == Block:
+		return DropOldest
+	}
+	return policy
+}
+
+// acquireSlot takes a concurrent export slot, reporting false if the batch
+// is to be dropped instead.
+func (e *Exporter) acquireSlot(ctx context.Context) (bool, error) {
+	if e.backpressure == BackpressureDrop {
+		select {
+		case e.exportSlots <- struct{}{}:
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
+	select {
+	case e.exportSlots <- struct{}{}:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// wait waits until the circuit lets a batch through or ctx is done.
+func (b *breaker) wait(ctx context.Context) error {
+	for !b.allow() {
+		timer := time.NewTimer(b.retryIn())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// retryIn returns how long to wait before the circuit may let a batch
+// through: the rest of the cooldown while open, or a tenth of it while a
+// probe is in flight.
+func (b *breaker) retryIn() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if d := b.cooldown - time.Since(b.openedAt); b.state == breakerOpen && d > 0 {
+		return d
+	}
+	if d := b.cooldown / 10; d > 10*time.Millisecond {
+		return d
+	}
+	return 10 * time.Millisecond
+}
@@ -0,0 +1,109 @@
+package httpExporter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestBackpressureDropOpenCircuit(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(1, time.Hour), httpExporter.WithBackpressure(httpExporter.BackpressureDrop))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 503 response")
+	}
+	if err := e.ExportSpans(ctx, newSpans("b", "c")); err != nil {
+		t.Errorf("ExportSpans with an open circuit = %v, want the batch dropped", err)
+	}
+	if got := c.count(); got != 1 {
+		t.Errorf("collector received %d requests, want none while the circuit is open", got)
+	}
+	if got := e.Stats().SpansDropped; got != 2 {
+		t.Errorf("SpansDropped = %d, want 2", got)
+	}
+}
+
+func TestBackpressureDropConcurrentExports(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(100 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithMaxConcurrentExports(1), httpExporter.WithBackpressure(httpExporter.BackpressureDrop))
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() { done <- e.ExportSpans(ctx, newSpans("a")) }()
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	if err := e.ExportSpans(ctx, newSpans("b")); err != nil {
+		t.Errorf("ExportSpans without a free slot = %v, want the batch dropped", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+	if got := e.Stats().SpansDropped; got != 1 {
+		t.Errorf("SpansDropped = %d, want 1", got)
+	}
+}
+
+func TestBackpressureBlockOpenCircuit(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(1, 50*time.Millisecond), httpExporter.WithBackpressure(httpExporter.BackpressureBlock))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 503 response")
+	}
+	start := time.Now()
+	if err := e.ExportSpans(ctx, newSpans("b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if d := time.Since(start); d < 25*time.Millisecond {
+		t.Errorf("ExportSpans returned after %v, want it to wait for the circuit", d)
+	}
+	if got := c.names(t); !equal(got, "b") {
+		t.Errorf("collector received %v, want [b]", got)
+	}
+}
+
+func TestBackpressureBlockCanceled(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(1, time.Hour), httpExporter.WithBackpressure(httpExporter.BackpressureBlock))
+
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 503 response")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := e.ExportSpans(ctx, newSpans("b")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ExportSpans = %v, want the context error", err)
+	}
+}
+
+func TestBackpressureBlockQueue(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(50 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(1, httpExporter.DropNewest), httpExporter.WithBackpressure(httpExporter.BackpressureBlock))
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c") {
+		t.Errorf("collector received %v, want no batch dropped", got)
+	}
+}
@@ -0,0 +1,111 @@
+package httpExporter
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// unhealthyThreshold is the number of consecutive delivery failures after
+	// which a pool endpoint is considered unhealthy.
+	unhealthyThreshold = 3
+	// unhealthyCooldown is how long an unhealthy pool endpoint is skipped
+	// before it is tried again.
+	unhealthyCooldown = 30 * time.Second
+)
+
+// BalancingPolicy selects the endpoint of a pool each batch is sent to.
+type BalancingPolicy int
+
+const (
+	// RoundRobin cycles through the endpoints of the pool.
+	RoundRobin BalancingPolicy = iota
+	// LeastPending picks the endpoint with the fewest in-flight requests.
+	LeastPending
+)
+
+// String returns the name of the balancing policy.
+func (p BalancingPolicy) String() string {
+	switch p {
+	case RoundRobin:
+		return "round-robin"
+	case LeastPending:
+		return "least-pending"
+	}
+	return "unknown"
+}
+
+// WithEndpointPool configures replicas of the primary collector. Batches for
+// the primary collector are spread across it and the replicas according to
+// policy. Endpoints failing three times in a row are skipped for 30 seconds,
+// unless every endpoint of the pool is unhealthy, and a batch an endpoint is
+// unavailable for is sent to another one.
+func WithEndpointPool(policy BalancingPolicy, urls ...string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.balancingPolicy = policy
+		cfg.poolEndpoints = append(append([]string{}, cfg.poolEndpoints...), urls...)
+		return cfg
+	})
+}
+
+// pool balances batches across replicas of a collector.
+type pool struct {
+	endpoints []*endpoint
+	policy    BalancingPolicy
+	next      uint64 // Round robin counter
+}
+
+// pick selects a healthy endpoint not in tried, or an unhealthy one when
+// there is no healthy one left. It returns nil once every endpoint was tried.
+func (p *pool) pick(tried map[*endpoint]bool) *endpoint {
+	now := time.Now()
+	var best, fallback *endpoint
+	start := int(atomic.AddUint64(&p.next, 1) - 1)
+	for n := range p.endpoints {
+		ep := p.endpoints[(start+n)%len(p.endpoints)]
+		if tried[ep] {
+			continue
+		}
+		if !ep.healthy(now) {
+			if fallback == nil {
+				fallback = ep
+			}
+			continue
+		}
+		if p.policy == RoundRobin {
+			return ep
+		}
+		if best == nil || atomic.LoadInt64(&ep.pending) < atomic.LoadInt64(&best.pending) {
+			best = ep
+		}
+	}
+	if best != nil {
+		return best
+	}
+	return fallback
+}
+
+// deliverReplica sends a request body to the primary collector, or to one of
+// its replicas when a pool is configured.
+func (e *Exporter) deliverReplica(ctx context.Context, contentType string, body []byte) error {
+	if e.pool == nil {
+		return e.deliver(ctx, e.endpoints[0], contentType, body)
+	}
+	tried := make(map[*endpoint]bool, len(e.pool.endpoints))
+	var err error
+	for ep := e.pool.pick(tried); ep != nil; ep = e.pool.pick(tried) {
+		tried[ep] = true
+		if err = e.deliver(ctx, ep, contentType, body); err == nil || !unavailable(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// healthy reports whether an endpoint should receive batches of its pool.
+func (ep *endpoint) healthy(now time.Time) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.consecutiveFailures < unhealthyThreshold || now.Sub(ep.lastFailure) >= unhealthyCooldown
+}
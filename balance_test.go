@@ -0,0 +1,89 @@
+package httpExporter_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestEndpointPoolRoundRobin(t *testing.T) {
+	primary, first, second := newCollector(t), newCollector(t), newCollector(t)
+	e := newExporter(t, primary.URL(), httpExporter.WithEndpointPool(httpExporter.RoundRobin, first.URL(), second.URL()))
+
+	for i := 0; i < 6; i++ {
+		if err := e.ExportSpans(context.Background(), newSpans(fmt.Sprint("batch ", i))); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	for c, want := range map[*collector][]string{
+		primary: {"batch 0", "batch 3"},
+		first:   {"batch 1", "batch 4"},
+		second:  {"batch 2", "batch 5"},
+	} {
+		if got := c.names(t); !equal(got, want...) {
+			t.Errorf("collector received %v, want %v", got, want)
+		}
+	}
+}
+
+func TestEndpointPoolUnavailableReplica(t *testing.T) {
+	primary, replica := newCollector(t), newCollector(t)
+	replica.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, primary.URL(), httpExporter.WithEndpointPool(httpExporter.RoundRobin, replica.URL()))
+
+	for i := 0; i < 6; i++ {
+		if err := e.ExportSpans(context.Background(), newSpans(fmt.Sprint("batch ", i))); err != nil {
+			t.Fatalf("ExportSpans(batch %d): %v", i, err)
+		}
+	}
+	// The batches of the replica are sent to the primary collector instead,
+	// and it is skipped once it failed three times in a row.
+	if got := primary.names(t); len(got) != 6 {
+		t.Errorf("primary collector received %v, want all 6 batches", got)
+	}
+	if got := replica.count(); got != 3 {
+		t.Errorf("replica received %d requests, want 3 before it is skipped", got)
+	}
+}
+
+func TestEndpointPoolRejectedBatch(t *testing.T) {
+	primary, replica := newCollector(t), newCollector(t)
+	primary.setStatus(http.StatusBadRequest)
+	e := newExporter(t, primary.URL(), httpExporter.WithEndpointPool(httpExporter.RoundRobin, replica.URL()))
+
+	if err := e.ExportSpans(context.Background(), newSpans("rejected")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 400 response")
+	}
+	if got := replica.count(); got != 0 {
+		t.Errorf("replica received %d requests for a rejected batch, want none", got)
+	}
+}
+
+func TestEndpointPoolLeastPending(t *testing.T) {
+	primary, replica := newCollector(t), newCollector(t)
+	primary.setLatency(300 * time.Millisecond)
+	e := newExporter(t, primary.URL(), httpExporter.WithEndpointPool(httpExporter.LeastPending, replica.URL()))
+
+	ctx := context.Background()
+	slow := make(chan error, 1)
+	go func() { slow <- e.ExportSpans(ctx, newSpans("slow")) }()
+	waitFor(t, "the first request", func() bool { return primary.count() == 1 })
+	for _, name := range []string{"a", "b"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if err := <-slow; err != nil {
+		t.Fatalf("ExportSpans(slow): %v", err)
+	}
+	if got := primary.names(t); !equal(got, "slow") {
+		t.Errorf("primary collector received %v, want [slow]", got)
+	}
+	if got := replica.names(t); !equal(got, "a", "b") {
+		t.Errorf("replica received %v while the primary collector was busy, want [a b]", got)
+	}
+}
@@ -0,0 +1,83 @@
+package httpExporter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// errCircuitOpen is returned for batches short-circuited by the circuit
+// breaker.
+var errCircuitOpen = fmt.Errorf("circuit breaker open: %w", ErrCollectorUnavailable)
+
+// WithCircuitBreaker configures a circuit breaker around the collector. After
+// failures consecutive deliveries failed because the collector is unavailable
+// the circuit opens: batches are dropped without being sent for cooldown.
+// Then a single batch is sent as a probe; the circuit closes if it is
+// delivered and opens again otherwise.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		if failures < 1 {
+			failures = 1
+		}
+		cfg.breakerFailures = failures
+		cfg.breakerCooldown = cooldown
+		return cfg
+	})
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a circuit breaker.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow reports whether a batch may be sent, moving an open circuit whose
+// cooldown elapsed to half-open. Only one probe is sent while half-open.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) >= b.cooldown {
+			b.state = breakerHalfOpen
+			return true
+		}
+	case breakerClosed:
+		return true
+	}
+	return false
+}
+
+// record accounts for the outcome of an allowed delivery and reports
+// whether it changed the state of the circuit.
+func (b *breaker) record(err error) (opened, closed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil || !unavailable(err) {
+		closed = b.state != breakerClosed
+		b.state = breakerClosed
+		b.failures = 0
+		return false, closed
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		opened = b.state != breakerOpen
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+	return opened, false
+}
@@ -0,0 +1,91 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(2, time.Hour))
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := e.ExportSpans(ctx, newSpans("span")); err == nil {
+			t.Fatalf("export %d succeeded while the collector is unavailable", i)
+		}
+	}
+	if got := c.count(); got != 2 {
+		t.Errorf("collector received %d requests, want 2 before the circuit opened", got)
+	}
+}
+
+func TestCircuitBreakerIgnoresRejections(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusBadRequest)
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(2, time.Hour))
+
+	for i := 0; i < 5; i++ {
+		if err := e.ExportSpans(context.Background(), newSpans("span")); err == nil {
+			t.Fatal("ExportSpans succeeded with a 400 response")
+		}
+	}
+	// The collector is up, it rejects the batches.
+	if got := c.count(); got != 5 {
+		t.Errorf("collector received %d requests, want all 5", got)
+	}
+}
+
+func TestCircuitBreakerProbes(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	const cooldown = 50 * time.Millisecond
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(1, cooldown))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("failed")); err == nil {
+		t.Fatal("ExportSpans succeeded while the collector is unavailable")
+	}
+	c.setStatus(http.StatusOK)
+	if err := e.ExportSpans(ctx, newSpans("dropped")); err == nil {
+		t.Error("ExportSpans succeeded while the circuit is open")
+	}
+
+	time.Sleep(cooldown)
+	for _, name := range []string{"probe", "closed"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if got := c.names(t); !equal(got, "probe", "closed") {
+		t.Errorf("collector received %v, want [probe closed]", got)
+	}
+}
+
+func TestCircuitBreakerFailedProbe(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	const cooldown = 50 * time.Millisecond
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(2, cooldown))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_ = e.ExportSpans(ctx, newSpans("failed"))
+	}
+	time.Sleep(cooldown)
+	if err := e.ExportSpans(ctx, newSpans("probe")); err == nil {
+		t.Fatal("probe succeeded while the collector is unavailable")
+	}
+	// A single failed probe opens the circuit again.
+	if err := e.ExportSpans(ctx, newSpans("dropped")); err == nil {
+		t.Error("ExportSpans succeeded while the circuit is open")
+	}
+	if got := c.count(); got != 3 {
+		t.Errorf("collector received %d requests, want 3", got)
+	}
+}
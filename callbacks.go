@@ -0,0 +1,74 @@
+package httpExporter
+
+import (
+	"context"
+	"time"
+)
+
+// ExportInfo describes the delivery of a batch to the primary collector.
+type ExportInfo struct {
+	Spans      int           // Number of spans in the batch
+	Bytes      int           // Size of the request body
+	Duration   time.Duration // Time spent delivering the batch, including retries
+	StatusCode int           // Status of the last response, 0 if none was received
+	Rejected   int           // Spans rejected by a partial success response
+	Message    string        // Message of a partial success response
+	Err        error         // Delivery error, nil on success
+}
+
+// WithOnExportSuccess configures a function called after each batch is
+// accepted by the primary collector. It is called synchronously, from the
+// goroutine exporting the batch.
+func WithOnExportSuccess(fn func(ExportInfo)) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.onExportSuccess = fn
+		return cfg
+	})
+}
+
+// WithOnExportError configures a function called after each batch the
+// primary collector did not accept. It is called synchronously, from the
+// goroutine exporting the batch.
+func WithOnExportError(fn func(ExportInfo)) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.onExportError = fn
+		return cfg
+	})
+}
+
+type exchangeKey struct{}
+
+// exchange records the response of the last request sent with a context
+// returned by withExchange.
+type exchange struct {
+	statusCode  int
+	contentType string
+	body        []byte // Truncated to maxResponseBodySize
+}
+
+func withExchange(ctx context.Context) (context.Context, *exchange) {
+	x := &exchange{}
+	return context.WithValue(ctx, exchangeKey{}, x), x
+}
+
+// recordResponse records a response in the exchange of ctx, if any.
+func recordResponse(ctx context.Context, statusCode int, contentType string, body []byte) {
+	if x, ok := ctx.Value(exchangeKey{}).(*exchange); ok {
+		x.statusCode = statusCode
+		x.contentType = contentType
+		x.body = body
+	}
+}
+
+// notify calls the export callback matching the outcome of a delivery.
+func (e *Exporter) notify(info ExportInfo) {
+	if info.Err == nil {
+		if e.onExportSuccess != nil {
+			e.onExportSuccess(info)
+		}
+		return
+	}
+	if e.onExportError != nil {
+		e.onExportError(info)
+	}
+}
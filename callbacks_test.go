@@ -0,0 +1,53 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestExportCallbacks(t *testing.T) {
+	c := newCollector(t)
+	var succeeded, failed []httpExporter.ExportInfo
+	e := newExporter(t, c.URL(),
+		httpExporter.WithOnExportSuccess(func(info httpExporter.ExportInfo) { succeeded = append(succeeded, info) }),
+		httpExporter.WithOnExportError(func(info httpExporter.ExportInfo) { failed = append(failed, info) }),
+	)
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	c.setStatus(http.StatusBadRequest)
+	if err := e.ExportSpans(ctx, newSpans("c")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 400 response")
+	}
+
+	if len(succeeded) != 1 || len(failed) != 1 {
+		t.Fatalf("got %d success and %d error callbacks, want 1 each", len(succeeded), len(failed))
+	}
+	reqs := c.received()
+	if s := succeeded[0]; s.Spans != 2 || s.Bytes != len(reqs[0].Body) || s.StatusCode != http.StatusOK || s.Err != nil || s.Duration <= 0 {
+		t.Errorf("success callback got %+v", s)
+	}
+	if f := failed[0]; f.Spans != 1 || f.Bytes != len(reqs[1].Body) || f.StatusCode != http.StatusBadRequest || f.Err == nil {
+		t.Errorf("error callback got %+v", f)
+	}
+}
+
+func TestExportCallbacksUnreachable(t *testing.T) {
+	c := newCollector(t)
+	url := c.URL()
+	c.server.Close()
+	var failed []httpExporter.ExportInfo
+	e := newExporter(t, url, httpExporter.WithOnExportError(func(info httpExporter.ExportInfo) { failed = append(failed, info) }))
+
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded with an unreachable collector")
+	}
+	if len(failed) != 1 || failed[0].StatusCode != 0 || failed[0].Err == nil {
+		t.Errorf("error callbacks got %+v, want one without a status code", failed)
+	}
+}
@@ -0,0 +1,354 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// cborEncoder encodes spans as a CBOR array of SpanData using the core
+// deterministic encoding of RFC 8949 section 4.2: shortest integer, length
+// and float forms, definite lengths and map keys sorted by their encoding.
+// Field names are the ones of the JSON format.
+type cborEncoder struct {
+	conv converter
+}
+
+func (cborEncoder) contentType() string { return "application/cbor" }
+
+func (cborEncoder) defaultPath() string { return "" }
+
+func (enc cborEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	return encodeBuffer(enc, spans)
+}
+
+func (enc cborEncoder) encodeTo(buf *bytes.Buffer, spans []sdktrace.ReadOnlySpan) error {
+	return withSpanData(enc.conv, spans, func(httpSpans []SpanData) error {
+		return cborEncodeValue(buf, reflect.ValueOf(httpSpans))
+	})
+}
+
+// CBOR major types.
+const (
+	cborUnsigned byte = 0 << 5
+	cborNegative byte = 1 << 5
+	cborBytes    byte = 2 << 5
+	cborText     byte = 3 << 5
+	cborArray    byte = 4 << 5
+	cborMap      byte = 5 << 5
+	cborSimple   byte = 7 << 5
+)
+
+// CBOR simple values and float headers.
+const (
+	cborFalse   byte = cborSimple | 20
+	cborTrue    byte = cborSimple | 21
+	cborNull    byte = cborSimple | 22
+	cborFloat16 byte = cborSimple | 25
+	cborFloat32 byte = cborSimple | 26
+	cborFloat64 byte = cborSimple | 27
+)
+
+// cborMarshal returns the deterministic CBOR encoding of v. Structs are
+// encoded as maps keyed by their JSON field names, honoring omitempty.
+func cborMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cborEncodeValue(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func cborEncodeValue(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteByte(cborNull)
+		return nil
+	}
+	if v.Type() == attributesType {
+		return cborEncodeAttributes(buf, v.Interface().(Attributes))
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteByte(cborNull)
+			return nil
+		}
+		return cborEncodeValue(buf, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			buf.WriteByte(cborTrue)
+		} else {
+			buf.WriteByte(cborFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i < 0 {
+			cborHead(buf, cborNegative, uint64(-(i + 1)))
+		} else {
+			cborHead(buf, cborUnsigned, uint64(i))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		cborHead(buf, cborUnsigned, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		cborFloat(buf, v.Float())
+	case reflect.String:
+		cborHead(buf, cborText, uint64(v.Len()))
+		buf.WriteString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteByte(cborNull)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			cborHead(buf, cborBytes, uint64(v.Len()))
+			buf.Write(v.Bytes())
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		cborHead(buf, cborArray, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := cborEncodeValue(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteByte(cborNull)
+			return nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cbor: unsupported map key type %s", v.Type().Key())
+		}
+		entries := make([]cborEntry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var val bytes.Buffer
+			if err := cborEncodeValue(&val, iter.Value()); err != nil {
+				return err
+			}
+			entries = append(entries, cborEntry{key: cborKey(iter.Key().String()), value: val.Bytes()})
+		}
+		cborWriteMap(buf, entries)
+	case reflect.Struct:
+		return cborEncodeStruct(buf, v)
+	default:
+		return fmt.Errorf("cbor: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+var attributesType = reflect.TypeOf(Attributes(nil))
+
+// cborEncodeAttributes encodes attributes as a map of their keys to their
+// values, as in the JSON format. The last of duplicate keys is kept.
+func cborEncodeAttributes(buf *bytes.Buffer, attrs Attributes) error {
+	entries := make([]cborEntry, 0, len(attrs))
+	index := make(map[attribute.Key]int, len(attrs))
+	for _, kv := range attrs {
+		var val bytes.Buffer
+		if err := cborEncodeValue(&val, reflect.ValueOf(kv.Value.AsInterface())); err != nil {
+			return err
+		}
+		entry := cborEntry{key: cborKey(string(kv.Key)), value: val.Bytes()}
+		if i, ok := index[kv.Key]; ok {
+			entries[i] = entry
+			continue
+		}
+		index[kv.Key] = len(entries)
+		entries = append(entries, entry)
+	}
+	cborWriteMap(buf, entries)
+	return nil
+}
+
+// cborEncodeStruct encodes a struct as a map of its exported fields, named
+// and omitted following their json tags. Embedded structs are flattened.
+func cborEncodeStruct(buf *bytes.Buffer, v reflect.Value) error {
+	var entries []cborEntry
+	if err := cborStructEntries(v, &entries); err != nil {
+		return err
+	}
+	cborWriteMap(buf, entries)
+	return nil
+}
+
+func cborStructEntries(v reflect.Value, entries *[]cborEntry) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.IndexByte(tag, ','); idx >= 0 {
+				opts = tag[idx:]
+				tag = tag[:idx]
+			}
+			if tag != "" {
+				name = tag
+			} else if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				if err := cborStructEntries(v.Field(i), entries); err != nil {
+					return err
+				}
+				continue
+			}
+		} else if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if err := cborStructEntries(v.Field(i), entries); err != nil {
+				return err
+			}
+			continue
+		}
+		fv := v.Field(i)
+		if strings.Contains(opts, ",omitempty") && cborIsEmpty(fv) {
+			continue
+		}
+		var val bytes.Buffer
+		if err := cborEncodeValue(&val, fv); err != nil {
+			return err
+		}
+		*entries = append(*entries, cborEntry{key: cborKey(name), value: val.Bytes()})
+	}
+	return nil
+}
+
+// cborIsEmpty reports whether v is empty as defined by encoding/json's
+// omitempty.
+func cborIsEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// cborEntry is an encoded map entry.
+type cborEntry struct {
+	key, value []byte
+}
+
+func cborKey(s string) []byte {
+	var buf bytes.Buffer
+	cborHead(&buf, cborText, uint64(len(s)))
+	buf.WriteString(s)
+	return buf.Bytes()
+}
+
+// cborWriteMap writes a map with its entries sorted by the bytewise
+// lexicographic order of their encoded keys.
+func cborWriteMap(buf *bytes.Buffer, entries []cborEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	cborHead(buf, cborMap, uint64(len(entries)))
+	for _, e := range entries {
+		buf.Write(e.key)
+		buf.Write(e.value)
+	}
+}
+
+// cborHead writes a major type with its argument in the shortest form.
+func cborHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major | 24)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		buf.Write(b[:])
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		buf.Write(b[:])
+	default:
+		buf.WriteByte(major | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], n)
+		buf.Write(b[:])
+	}
+}
+
+// cborFloat writes f in the shortest of the half, single and double
+// precision forms that represents it exactly.
+func cborFloat(buf *bytes.Buffer, f float64) {
+	if math.IsNaN(f) {
+		// Canonical NaN.
+		buf.Write([]byte{cborFloat16, 0x7e, 0x00})
+		return
+	}
+	if f32 := float32(f); float64(f32) == f {
+		if h, ok := float16Bits(f32); ok {
+			buf.WriteByte(cborFloat16)
+			var b [2]byte
+			binary.BigEndian.PutUint16(b[:], h)
+			buf.Write(b[:])
+			return
+		}
+		buf.WriteByte(cborFloat32)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], math.Float32bits(f32))
+		buf.Write(b[:])
+		return
+	}
+	buf.WriteByte(cborFloat64)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(f))
+	buf.Write(b[:])
+}
+
+// float16Bits returns the IEEE 754 half precision encoding of f if it can
+// be represented exactly.
+func float16Bits(f float32) (uint16, bool) {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23&0xff) - 127
+	mant := bits & 0x7fffff
+
+	switch {
+	case exp == 128:
+		// Infinity; NaN is handled by the caller.
+		return sign | 0x7c00, true
+	case f == 0:
+		return sign, true
+	case exp >= -14 && exp <= 15:
+		// Normal half precision number: the low 13 mantissa bits must be zero.
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exp+15)<<10 | uint16(mant>>13), true
+	case exp >= -24 && exp < -14:
+		// Subnormal half precision number.
+		shift := uint32(-exp - 14 + 13)
+		full := mant | 0x800000
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	}
+	return 0, false
+}
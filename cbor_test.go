@@ -0,0 +1,106 @@
+package httpExporter
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCBORVectors checks the encoding of the examples of RFC 8949 appendix
+// A that have a representation in Go, in their deterministic form.
+func TestCBORVectors(t *testing.T) {
+	array25 := make([]int, 25)
+	for i := range array25 {
+		array25[i] = i + 1
+	}
+	for _, test := range []struct {
+		value interface{}
+		want  string
+	}{
+		{0, "00"},
+		{1, "01"},
+		{10, "0a"},
+		{23, "17"},
+		{24, "1818"},
+		{25, "1819"},
+		{100, "1864"},
+		{1000, "1903e8"},
+		{1000000, "1a000f4240"},
+		{int64(1000000000000), "1b000000e8d4a51000"},
+		{uint64(18446744073709551615), "1bffffffffffffffff"},
+		{-1, "20"},
+		{-10, "29"},
+		{-100, "3863"},
+		{-1000, "3903e7"},
+		{int64(math.MinInt64), "3b7fffffffffffffff"},
+		{0.0, "f90000"},
+		{math.Copysign(0, -1), "f98000"},
+		{1.0, "f93c00"},
+		{1.1, "fb3ff199999999999a"},
+		{1.5, "f93e00"},
+		{65504.0, "f97bff"},
+		{100000.0, "fa47c35000"},
+		{3.4028234663852886e+38, "fa7f7fffff"},
+		{1.0e+300, "fb7e37e43c8800759c"},
+		{5.960464477539063e-8, "f90001"},
+		{0.00006103515625, "f90400"},
+		{-4.0, "f9c400"},
+		{-4.1, "fbc010666666666666"},
+		{float32(100000.0), "fa47c35000"},
+		{math.Inf(1), "f97c00"},
+		{math.NaN(), "f97e00"},
+		{math.Inf(-1), "f9fc00"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{[]byte{}, "40"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"a", "6161"},
+		{"IETF", "6449455446"},
+		{"\"\\", "62225c"},
+		{"ü", "62c3bc"},
+		{"水", "63e6b0b4"},
+		{[]int{}, "80"},
+		{[]int{1, 2, 3}, "83010203"},
+		{[]interface{}{1, []int{2, 3}, []int{4, 5}}, "8301820203820405"},
+		{array25, "98190102030405060708090a0b0c0d0e0f101112131415161718181819"},
+		{map[string]int{}, "a0"},
+		{map[string]interface{}{"a": 1, "b": []int{2, 3}}, "a26161016162820203"},
+		{[]interface{}{"a", map[string]string{"b": "c"}}, "826161a161626163"},
+		{map[string]string{"a": "A", "b": "B", "c": "C", "d": "D", "e": "E"}, "a56161614161626142616361436164614461656145"},
+	} {
+		got, err := cborMarshal(test.value)
+		if err != nil {
+			t.Errorf("cborMarshal(%#v): %v", test.value, err)
+			continue
+		}
+		if hex.EncodeToString(got) != test.want {
+			t.Errorf("cborMarshal(%#v) = %x, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+// TestCBORMapKeyOrder checks that map keys are sorted by their encoding,
+// shorter keys first, as required by the deterministic encoding.
+func TestCBORMapKeyOrder(t *testing.T) {
+	got, err := cborMarshal(map[string]int{"aa": 1, "b": 2, "a": 3, "ab": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a4" + "616103" + "616202" + "62616101" + "62616204"; hex.EncodeToString(got) != want {
+		t.Errorf("got %x, want %s", got, want)
+	}
+}
+
+func TestCBOR(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(CBOR))
+	checkEncoder(t, enc, "application/cbor", "")
+	checkGolden(t, "cbor.hex", []byte(strings.TrimSpace(hex.Dump(body))+"\n"))
+
+	_, again := encodeTestSpans(t, WithFormat(CBOR))
+	if string(again) != string(body) {
+		t.Error("encoding the same spans twice produced different payloads")
+	}
+}
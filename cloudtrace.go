@@ -0,0 +1,356 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// envGoogleCloudProject is the environment variable holding the Google
+	// Cloud project used when none is configured.
+	envGoogleCloudProject = "GOOGLE_CLOUD_PROJECT"
+	// envGCEMetadataHost overrides the host of the metadata server, as for
+	// the Google Cloud client libraries.
+	envGCEMetadataHost = "GCE_METADATA_HOST"
+	// defaultMetadataHost is the host of the GCE and GKE metadata server.
+	defaultMetadataHost = "metadata.google.internal"
+	// metadataTokenPath returns access tokens of the default service
+	// account of the instance or, with workload identity, of the pod.
+	metadataTokenPath = "/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// Cloud Trace limits, beyond which strings are truncated and attributes,
+// annotations and links dropped.
+const (
+	cloudTraceMaxNameBytes       = 128
+	cloudTraceMaxKeyBytes        = 128
+	cloudTraceMaxValueBytes      = 256
+	cloudTraceMaxAttributes      = 32
+	cloudTraceMaxAnnotations     = 32
+	cloudTraceMaxAnnotationBytes = 256
+	cloudTraceMaxLinks           = 128
+	cloudTraceStatusUnknown      = 2 // google.rpc.Code UNKNOWN
+)
+
+// cloudTraceEncoder encodes spans as a Cloud Trace v2 BatchWriteSpans
+// request.
+type cloudTraceEncoder struct {
+	project string
+}
+
+func (cloudTraceEncoder) contentType() string { return "application/json" }
+
+func (enc cloudTraceEncoder) defaultPath() string {
+	return "/v2/projects/" + enc.project + "/traces:batchWrite"
+}
+
+func (enc cloudTraceEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	req := cloudTraceRequest{Spans: make([]cloudTraceSpan, 0, len(spans))}
+	for _, span := range spans {
+		req.Spans = append(req.Spans, enc.span(span))
+	}
+	return json.Marshal(req)
+}
+
+type cloudTraceRequest struct {
+	Spans []cloudTraceSpan `json:"spans"`
+}
+
+type cloudTraceSpan struct {
+	Name                    string                `json:"name"`
+	SpanID                  string                `json:"spanId"`
+	ParentSpanID            string                `json:"parentSpanId,omitempty"`
+	DisplayName             cloudTraceString      `json:"displayName"`
+	StartTime               string                `json:"startTime"`
+	EndTime                 string                `json:"endTime"`
+	Attributes              *cloudTraceAttributes `json:"attributes,omitempty"`
+	TimeEvents              *cloudTraceTimeEvents `json:"timeEvents,omitempty"`
+	Links                   *cloudTraceLinks      `json:"links,omitempty"`
+	Status                  *cloudTraceStatus     `json:"status,omitempty"`
+	SameProcessAsParentSpan *bool                 `json:"sameProcessAsParentSpan,omitempty"`
+	ChildSpanCount          int                   `json:"childSpanCount,omitempty"`
+	SpanKind                string                `json:"spanKind"`
+}
+
+// cloudTraceString is a TruncatableString.
+type cloudTraceString struct {
+	Value              string `json:"value"`
+	TruncatedByteCount int    `json:"truncatedByteCount,omitempty"`
+}
+
+type cloudTraceAttributes struct {
+	AttributeMap           map[string]cloudTraceValue `json:"attributeMap"`
+	DroppedAttributesCount int                        `json:"droppedAttributesCount,omitempty"`
+}
+
+// cloudTraceValue is an AttributeValue, holding one of its fields.
+type cloudTraceValue struct {
+	StringValue *cloudTraceString `json:"stringValue,omitempty"`
+	IntValue    string            `json:"intValue,omitempty"` // int64 are strings in proto3 JSON
+	BoolValue   *bool             `json:"boolValue,omitempty"`
+}
+
+type cloudTraceTimeEvents struct {
+	TimeEvent               []cloudTraceTimeEvent `json:"timeEvent"`
+	DroppedAnnotationsCount int                   `json:"droppedAnnotationsCount,omitempty"`
+}
+
+type cloudTraceTimeEvent struct {
+	Time       string               `json:"time"`
+	Annotation cloudTraceAnnotation `json:"annotation"`
+}
+
+type cloudTraceAnnotation struct {
+	Description cloudTraceString      `json:"description"`
+	Attributes  *cloudTraceAttributes `json:"attributes,omitempty"`
+}
+
+type cloudTraceLinks struct {
+	Link              []cloudTraceLink `json:"link"`
+	DroppedLinksCount int              `json:"droppedLinksCount,omitempty"`
+}
+
+type cloudTraceLink struct {
+	TraceID    string                `json:"traceId"`
+	SpanID     string                `json:"spanId"`
+	Type       string                `json:"type"`
+	Attributes *cloudTraceAttributes `json:"attributes,omitempty"`
+}
+
+type cloudTraceStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// span converts a span to a Cloud Trace span. Resource attributes are added
+// to the span attributes, which take precedence.
+func (enc cloudTraceEncoder) span(span sdktrace.ReadOnlySpan) cloudTraceSpan {
+	sc := span.SpanContext()
+	cs := cloudTraceSpan{
+		Name:        fmt.Sprintf("projects/%s/traces/%s/spans/%s", enc.project, sc.TraceID(), sc.SpanID()),
+		SpanID:      sc.SpanID().String(),
+		DisplayName: truncatable(span.Name(), cloudTraceMaxNameBytes),
+		StartTime:   cloudTraceTime(span.StartTime()),
+		EndTime:     cloudTraceTime(span.EndTime()),
+		SpanKind:    cloudTraceKind(span.SpanKind()),
+
+		ChildSpanCount: span.ChildSpanCount(),
+	}
+	if parent := span.Parent(); parent.SpanID().IsValid() {
+		cs.ParentSpanID = parent.SpanID().String()
+		sameProcess := !parent.IsRemote()
+		cs.SameProcessAsParentSpan = &sameProcess
+	}
+	attrs := append(append([]attribute.KeyValue(nil), span.Attributes()...), span.Resource().Attributes()...)
+	cs.Attributes = cloudTraceAttrs(attrs, span.DroppedAttributes())
+
+	if events := span.Events(); len(events) > 0 || span.DroppedEvents() > 0 {
+		te := &cloudTraceTimeEvents{DroppedAnnotationsCount: span.DroppedEvents()}
+		if len(events) > cloudTraceMaxAnnotations {
+			// Keep the most recent events, like the SDK.
+			te.DroppedAnnotationsCount += len(events) - cloudTraceMaxAnnotations
+			events = events[len(events)-cloudTraceMaxAnnotations:]
+		}
+		for _, ev := range events {
+			te.TimeEvent = append(te.TimeEvent, cloudTraceTimeEvent{
+				Time: cloudTraceTime(ev.Time),
+				Annotation: cloudTraceAnnotation{
+					Description: truncatable(ev.Name, cloudTraceMaxAnnotationBytes),
+					Attributes:  cloudTraceAttrs(ev.Attributes, ev.DroppedAttributeCount),
+				},
+			})
+		}
+		cs.TimeEvents = te
+	}
+	if links := span.Links(); len(links) > 0 || span.DroppedLinks() > 0 {
+		cl := &cloudTraceLinks{DroppedLinksCount: span.DroppedLinks()}
+		if len(links) > cloudTraceMaxLinks {
+			cl.DroppedLinksCount += len(links) - cloudTraceMaxLinks
+			links = links[:cloudTraceMaxLinks]
+		}
+		for _, l := range links {
+			cl.Link = append(cl.Link, cloudTraceLink{
+				TraceID:    l.SpanContext.TraceID().String(),
+				SpanID:     l.SpanContext.SpanID().String(),
+				Type:       "TYPE_UNSPECIFIED",
+				Attributes: cloudTraceAttrs(l.Attributes, l.DroppedAttributeCount),
+			})
+		}
+		cs.Links = cl
+	}
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		cs.Status = &cloudTraceStatus{Message: status.Description}
+	case codes.Error:
+		cs.Status = &cloudTraceStatus{Code: cloudTraceStatusUnknown, Message: status.Description}
+	}
+	return cs
+}
+
+// cloudTraceAttrs converts attributes, keeping the first of duplicate keys
+// and dropping those beyond the Cloud Trace limit.
+func cloudTraceAttrs(attrs []attribute.KeyValue, dropped int) *cloudTraceAttributes {
+	if len(attrs) == 0 && dropped == 0 {
+		return nil
+	}
+	ca := &cloudTraceAttributes{
+		AttributeMap:           make(map[string]cloudTraceValue, len(attrs)),
+		DroppedAttributesCount: dropped,
+	}
+	for _, kv := range attrs {
+		key := string(kv.Key)
+		if len(key) > cloudTraceMaxKeyBytes {
+			key = truncateString(key, cloudTraceMaxKeyBytes)
+		}
+		if _, ok := ca.AttributeMap[key]; ok {
+			continue
+		}
+		if len(ca.AttributeMap) == cloudTraceMaxAttributes {
+			ca.DroppedAttributesCount++
+			continue
+		}
+		ca.AttributeMap[key] = cloudTraceAttrValue(kv.Value)
+	}
+	return ca
+}
+
+// cloudTraceAttrValue converts an attribute value. Cloud Trace has no
+// floating point or array values, so they are encoded as strings.
+func cloudTraceAttrValue(v attribute.Value) cloudTraceValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return cloudTraceValue{BoolValue: &b}
+	case attribute.INT64:
+		return cloudTraceValue{IntValue: strconv.FormatInt(v.AsInt64(), 10)}
+	}
+	s := truncatable(attributeValueString(v), cloudTraceMaxValueBytes)
+	return cloudTraceValue{StringValue: &s}
+}
+
+// truncatable returns s as a TruncatableString of at most limit bytes.
+func truncatable(s string, limit int) cloudTraceString {
+	if len(s) <= limit {
+		return cloudTraceString{Value: s}
+	}
+	t := truncateString(s, limit)
+	return cloudTraceString{Value: t, TruncatedByteCount: len(s) - len(t)}
+}
+
+func cloudTraceTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// cloudTraceKind maps a span kind to a Cloud Trace span kind.
+func cloudTraceKind(kind trace.SpanKind) string {
+	switch kind {
+	case trace.SpanKindInternal:
+		return "INTERNAL"
+	case trace.SpanKindServer:
+		return "SERVER"
+	case trace.SpanKindClient:
+		return "CLIENT"
+	case trace.SpanKindProducer:
+		return "PRODUCER"
+	case trace.SpanKindConsumer:
+		return "CONSUMER"
+	}
+	return "SPAN_KIND_UNSPECIFIED"
+}
+
+// WithCloudTraceProject configures the Google Cloud project the CloudTrace
+// format writes spans to. It defaults to the GOOGLE_CLOUD_PROJECT
+// environment variable.
+func WithCloudTraceProject(project string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.cloudTraceProject = project
+		return cfg
+	})
+}
+
+// WithGoogleMetadataToken configures the exporter to authenticate requests
+// with an OAuth 2.0 access token of the service account of the instance, or
+// of the pod with GKE workload identity, obtained from the metadata server
+// and refreshed before it expires, as needed to send spans to Cloud Trace.
+func WithGoogleMetadataToken() Option {
+	return WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		host := os.Getenv(envGCEMetadataHost)
+		if host == "" {
+			host = defaultMetadataHost
+		}
+		return &metadataTokenTransport{
+			next:     next,
+			tokenURL: "http://" + host + metadataTokenPath,
+			client:   &http.Client{Timeout: 10 * time.Second},
+		}
+	})
+}
+
+// metadataTokenTransport adds an access token from the metadata server to
+// requests.
+type metadataTokenTransport struct {
+	next     http.RoundTripper
+	tokenURL string
+	client   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (t *metadataTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.accessToken(req)
+	if err != nil {
+		return nil, err
+	}
+	// RoundTrippers must not modify the request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
+
+// accessToken returns the cached token, fetching a new one when it expires
+// within a minute.
+func (t *metadataTokenTransport) accessToken(req *http.Request) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && time.Until(t.expires) > time.Minute {
+		return t.token, nil
+	}
+	treq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, t.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	treq.Header.Set("Metadata-Flavor", "Google")
+	resp, err := t.client.Do(treq)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token from metadata server: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get access token from metadata server: status %d", resp.StatusCode)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("invalid access token from metadata server: %v", err)
+	}
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("no access token from metadata server")
+	}
+	t.token = tok.AccessToken
+	t.expires = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return t.token, nil
+}
@@ -0,0 +1,59 @@
+package httpExporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCloudTrace(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(CloudTrace), WithCloudTraceProject("shop"))
+	checkEncoder(t, enc, "application/json", "/v2/projects/shop/traces:batchWrite")
+	checkGoldenJSON(t, "cloudtrace.json", body)
+}
+
+func TestCloudTraceProjectFromEnvironment(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "from-env")
+	enc := newEncoder(newConfig(WithFormat(CloudTrace)))
+	if got, want := enc.defaultPath(), "/v2/projects/from-env/traces:batchWrite"; got != want {
+		t.Errorf("default path = %q, want %q", got, want)
+	}
+}
+
+func TestGoogleMetadataToken(t *testing.T) {
+	var fetches int
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor", http.StatusForbidden)
+			return
+		}
+		fetches++
+		w.Write([]byte(`{"access_token":"token-1","expires_in":3600}`))
+	}))
+	defer metadata.Close()
+	t.Setenv("GCE_METADATA_HOST", metadata.Listener.Addr().String())
+
+	var auth []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+	}))
+	defer collector.Close()
+
+	e, err := New(collector.URL, WithFormat(CloudTrace), WithCloudTraceProject("shop"), WithGoogleMetadataToken())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Shutdown(context.Background())
+	for i := 0; i < 2; i++ {
+		if err := e.ExportSpans(context.Background(), testSpans()); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if len(auth) != 2 || auth[0] != "Bearer token-1" || auth[1] != "Bearer token-1" {
+		t.Errorf("Authorization headers = %q, want the metadata server token", auth)
+	}
+	if fetches != 1 {
+		t.Errorf("token fetched %d times, want it cached", fetches)
+	}
+}
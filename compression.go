@@ -0,0 +1,60 @@
+package httpExporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+)
+
+// Compression selects how request bodies are compressed.
+type Compression int
+
+const (
+	// NoCompression sends request bodies uncompressed. This is the default.
+	NoCompression Compression = iota
+	// GzipCompression compresses request bodies with gzip.
+	GzipCompression
+)
+
+// String returns the name of the compression, as used by the
+// OTEL_EXPORTER_HTTP_COMPRESSION environment variable.
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case GzipCompression:
+		return "gzip"
+	}
+	return "unknown"
+}
+
+// WithCompression configures the compression of request bodies. Compressed
+// requests carry a Content-Encoding header. Streamed batches are never
+// compressed: gzip compression conflicts with WithStreaming, unless it comes
+// from OTEL_EXPORTER_HTTP_COMPRESSION, which streaming ignores.
+func WithCompression(c Compression) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.compression = c
+		cfg.envCompressed = false
+		return cfg
+	})
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} { return gzip.NewWriter(nil) },
+}
+
+// gzipBody returns body compressed with gzip.
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzipWriterPool.Get().(*gzip.Writer)
+	defer gzipWriterPool.Put(zw)
+	zw.Reset(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,98 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// concurrencyCollector is a slow collector recording the highest number of
+// requests it handled concurrently.
+type concurrencyCollector struct {
+	*httptest.Server
+	current, max, total int32
+}
+
+func newConcurrencyCollector(t *testing.T) *concurrencyCollector {
+	t.Helper()
+	c := &concurrencyCollector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&c.current, 1)
+		defer atomic.AddInt32(&c.current, -1)
+		for {
+			max := atomic.LoadInt32(&c.max)
+			if n <= max || atomic.CompareAndSwapInt32(&c.max, max, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&c.total, 1)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestMaxConcurrentExports(t *testing.T) {
+	c := newConcurrencyCollector(t)
+	e := newExporter(t, c.URL, httpExporter.WithMaxConcurrentExports(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+				t.Errorf("ExportSpans: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(&c.max); got != 2 {
+		t.Errorf("collector handled up to %d concurrent requests, want 2", got)
+	}
+	if got := atomic.LoadInt32(&c.total); got != 6 {
+		t.Errorf("collector handled %d requests, want 6", got)
+	}
+}
+
+func TestMaxConcurrentExportsCanceled(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(300 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithMaxConcurrentExports(1))
+
+	ctx := context.Background()
+	go e.ExportSpans(ctx, newSpans("slow"))
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	wctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := e.ExportSpans(wctx, newSpans("waiting")); err != context.DeadlineExceeded {
+		t.Errorf("ExportSpans waiting for a slot = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestMaxConcurrentExportsQueueSenders(t *testing.T) {
+	c := newConcurrencyCollector(t)
+	e := newExporter(t, c.URL, httpExporter.WithQueue(10, httpExporter.Block), httpExporter.WithMaxConcurrentExports(3))
+
+	ctx := context.Background()
+	for i := 0; i < 6; i++ {
+		if err := e.ExportSpans(ctx, newSpans("a")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := atomic.LoadInt32(&c.max); got != 3 {
+		t.Errorf("collector handled up to %d concurrent requests, want 3 senders", got)
+	}
+	if got := atomic.LoadInt32(&c.total); got != 6 {
+		t.Errorf("collector handled %d requests, want 6", got)
+	}
+}
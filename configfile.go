@@ -0,0 +1,221 @@
+package httpExporter
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig is the configuration file read by NewFromConfig, in YAML or
+// JSON. Durations are strings such as "5s". For example:
+//
+//	endpoint: https://collector:4318/v1/traces
+//	format: otlp_proto
+//	headers:
+//	  x-api-key: secret
+//	timeout: 5s
+//	compression: gzip
+//	tls:
+//	  caFile: /etc/certs/ca.pem
+//	retry:
+//	  enabled: true
+//	  initialInterval: 1s
+//	queue:
+//	  capacity: 100
+//	  policy: drop-oldest
+type FileConfig struct {
+	// Endpoint is the collector URL. When empty, the environment or the
+	// default is used, as for New.
+	Endpoint string `yaml:"endpoint"`
+	// Format is the name of the wire format, as returned by Format.String.
+	Format string `yaml:"format"`
+	// Headers are sent with every request.
+	Headers map[string]string `yaml:"headers"`
+	// Timeout bounds each request.
+	Timeout time.Duration `yaml:"timeout"`
+	// Compression is gzip or none.
+	Compression string `yaml:"compression"`
+	// Insecure contacts collectors without a scheme over plain http.
+	Insecure bool `yaml:"insecure"`
+	// UserAgent overrides the User-Agent header.
+	UserAgent string `yaml:"userAgent"`
+	// Proxy is the URL of an HTTP proxy to send requests through.
+	Proxy string `yaml:"proxy"`
+	// ServiceName is set as with WithServiceName.
+	ServiceName string `yaml:"serviceName"`
+
+	TLS   FileTLSConfig   `yaml:"tls"`
+	Retry FileRetryConfig `yaml:"retry"`
+	Queue FileQueueConfig `yaml:"queue"`
+}
+
+// FileTLSConfig is the TLS section of a configuration file.
+type FileTLSConfig struct {
+	// CAFile is a PEM file of certificate authorities to trust.
+	CAFile string `yaml:"caFile"`
+	// CertFile and KeyFile are the PEM files of a client certificate.
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// ServerName overrides the name used to verify the collector
+	// certificate.
+	ServerName string `yaml:"serverName"`
+	// InsecureSkipVerify disables the verification of the collector
+	// certificate.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+}
+
+// FileRetryConfig is the retry section of a configuration file. Unset
+// intervals default to the ones of DefaultRetryConfig.
+type FileRetryConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	InitialInterval time.Duration `yaml:"initialInterval"`
+	MaxInterval     time.Duration `yaml:"maxInterval"`
+	MaxElapsedTime  time.Duration `yaml:"maxElapsedTime"`
+}
+
+// FileQueueConfig is the queue section of a configuration file. A zero
+// capacity disables the queue.
+type FileQueueConfig struct {
+	Capacity int `yaml:"capacity"`
+	// Policy is the name of the queue policy, as returned by
+	// QueuePolicy.String. Defaults to drop-oldest.
+	Policy string `yaml:"policy"`
+}
+
+// NewFromConfig creates an exporter configured by the YAML or JSON file at
+// path, see FileConfig. opts are applied after the file configuration and
+// take precedence over it, which in turn takes precedence over environment
+// variables. Unknown fields are rejected.
+func NewFromConfig(path string, opts ...Option) (*Exporter, error) {
+	fc, err := readFileConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	fileOpts, err := fc.options()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return New(fc.Endpoint, append(fileOpts, opts...)...)
+}
+
+func readFileConfig(path string) (FileConfig, error) {
+	var fc FileConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return fc, fmt.Errorf("failed to open config file: %v", err)
+	}
+	defer f.Close()
+	// YAML is a superset of JSON, so JSON files are decoded as well.
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&fc); err != nil {
+		return fc, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return fc, nil
+}
+
+// options returns the options equivalent to the file configuration.
+func (fc FileConfig) options() ([]Option, error) {
+	var opts []Option
+	if fc.Format != "" {
+		format, ok := parseFormat(fc.Format)
+		if !ok {
+			return nil, fmt.Errorf("unknown format %q", fc.Format)
+		}
+		opts = append(opts, WithFormat(format))
+	}
+	if len(fc.Headers) > 0 {
+		opts = append(opts, WithHeaders(fc.Headers))
+	}
+	if fc.Timeout > 0 {
+		opts = append(opts, WithTimeout(fc.Timeout))
+	}
+	switch fc.Compression {
+	case "":
+	case GzipCompression.String():
+		opts = append(opts, WithCompression(GzipCompression))
+	case NoCompression.String():
+		opts = append(opts, WithCompression(NoCompression))
+	default:
+		return nil, fmt.Errorf("unknown compression %q", fc.Compression)
+	}
+	if fc.Insecure {
+		opts = append(opts, WithInsecure())
+	}
+	if fc.UserAgent != "" {
+		opts = append(opts, WithUserAgent(fc.UserAgent))
+	}
+	if fc.Proxy != "" {
+		opts = append(opts, WithProxy(fc.Proxy))
+	}
+	if fc.ServiceName != "" {
+		opts = append(opts, WithServiceName(fc.ServiceName))
+	}
+	if fc.TLS != (FileTLSConfig{}) {
+		tlsConfig, err := fc.TLS.load()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithTLSClientConfig(tlsConfig))
+	}
+	if fc.Retry.Enabled {
+		opts = append(opts, WithRetry(RetryConfig(fc.Retry)))
+	}
+	if fc.Queue.Capacity > 0 {
+		policy := DropOldest
+		if fc.Queue.Policy != "" {
+			var ok bool
+			if policy, ok = parseQueuePolicy(fc.Queue.Policy); !ok {
+				return nil, fmt.Errorf("unknown queue policy %q", fc.Queue.Policy)
+			}
+		}
+		opts = append(opts, WithQueue(fc.Queue.Capacity, policy))
+	}
+	return opts, nil
+}
+
+// load builds the TLS settings of the TLS section.
+func (tc FileTLSConfig) load() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         tc.ServerName,
+		InsecureSkipVerify: tc.InsecureSkipVerify,
+	}
+	if tc.CAFile != "" {
+		pool, err := loadCertPool(tc.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if tc.CertFile != "" || tc.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// parseFormat returns the format with the given name.
+func parseFormat(name string) (Format, bool) {
+	for f := JSON; f <= KafkaREST; f++ {
+		if f.String() == name {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// parseQueuePolicy returns the queue policy with the given name.
+func parseQueuePolicy(name string) (QueuePolicy, bool) {
+	for p := DropOldest; p <= Block; p++ {
+		if p.String() == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package httpExporter_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// writeConfig writes a configuration file named name and returns its path.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newExporterFromConfig creates an exporter from a configuration file,
+// shut down at the end of the test.
+func newExporterFromConfig(t *testing.T, path string, opts ...httpExporter.Option) *httpExporter.Exporter {
+	t.Helper()
+	e, err := httpExporter.NewFromConfig(path, opts...)
+	if err != nil {
+		t.Fatalf("NewFromConfig: %v", err)
+	}
+	t.Cleanup(func() { _ = e.Shutdown(context.Background()) })
+	return e
+}
+
+func TestNewFromConfigYAML(t *testing.T) {
+	c := newCollector(t)
+	path := writeConfig(t, "exporter.yaml", `
+endpoint: `+c.URL()+`/v1/traces
+headers:
+  x-api-key: secret
+  x-tenant: shop
+timeout: 5s
+compression: gzip
+queue:
+  capacity: 10
+  policy: block
+`)
+	e := newExporterFromConfig(t, path, httpExporter.WithHeaders(map[string]string{"x-tenant": "override"}))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(reqs))
+	}
+	r := reqs[0]
+	if r.Path != "/v1/traces" || r.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("request to %s with headers %v", r.Path, r.Header)
+	}
+	if r.Header.Get("X-Api-Key") != "secret" || r.Header.Get("X-Tenant") != "override" {
+		t.Errorf("headers %v, want those of the file overridden by options", r.Header)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestNewFromConfigJSON(t *testing.T) {
+	c := newCollector(t)
+	path := writeConfig(t, "exporter.json", `{"endpoint": "`+c.URL()+`", "format": "zipkin"}`)
+	e := newExporterFromConfig(t, path)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if reqs := c.received(); len(reqs) != 1 || reqs[0].Path != "/api/v2/spans" {
+		t.Errorf("collector received %+v, want a Zipkin request", reqs)
+	}
+}
+
+func TestNewFromConfigInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown field":       "endpoint: http://localhost:4318\nendpiont: typo\n",
+		"unknown format":      "format: msgpack\n",
+		"unknown compression": "compression: brotli\n",
+		"unknown policy":      "queue:\n  capacity: 1\n  policy: drop-all\n",
+		"invalid duration":    "timeout: soon\n",
+	} {
+		if _, err := httpExporter.NewFromConfig(writeConfig(t, "exporter.yaml", content)); err == nil {
+			t.Errorf("NewFromConfig succeeded with %s", name)
+		}
+	}
+	if _, err := httpExporter.NewFromConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("NewFromConfig succeeded without a file")
+	}
+}
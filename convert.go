@@ -1,7 +1,12 @@
 package httpExporter
 
 import (
+	"encoding/base64"
+	"encoding/json"
+
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
@@ -10,97 +15,227 @@ import (
 type SpanData struct {
 	TraceID                       string                    `json:"traceId"` // A unique identifier for the trace
 	SpanID                        string                    `json:"spanId"`  // A unique identifier for a span within a trace
-	ParentSpanID                  string                    `json:"parentSpanId"`
+	ParentSpanID                  string                    `json:"parentSpanId,omitempty"` // Empty for root spans with WithOmitEmptyParent
+	TraceState                    string                    `json:"traceState,omitempty"` // W3C tracestate of the span context
+	Flags                         uint8                     `json:"flags"`                // W3C trace flags of the span context, such as sampled
 	Name                          string                    `json:"name"`                   // A description of the spans operation
 	StartTime                     int64                     `json:"startTime"`              // Start time of the span
 	EndTime                       int64                     `json:"endTime"`                // End time of the span
-	Attrs                         map[attribute.Key]interface{} `json:"attrs"`                  // A collection of key-value pairs
+	Attrs                         Attributes                `json:"attrs"`                  // A collection of key-value pairs
 	DroppedAttributeCount         int                       `json:"droppedAttributesCount"` // Number of attributes that were dropped due to reasons like too many attributes
 	Links                         []Link                    `json:"links,omitempty"`
 	DroppedLinkCount              int                       `json:"droppedLinkCount"`
 	StatusCode                    string                    `json:"statusCode"` // Status code of the span. Defaults to unset
+	StatusCodeNumber              *int                      `json:"statusCodeNumber,omitempty"` // Numeric OTLP status code, with PayloadV2
+	HTTPStatusCode                *int                      `json:"httpStatusCode,omitempty"`   // HTTP response status code, with PayloadV2
+	RPCStatusCode                 *int                      `json:"rpcStatusCode,omitempty"`    // gRPC status code, with PayloadV2
 	MessageEvents                 []Event                   `json:"messageEvents,omitempty"`
 	DroppedMessageEventCount      int                       `json:"droppedMessageEventCount"`
 	SpanKind                      trace.SpanKind            `json:"spanKind"`                   // Type of span
 	StatusMessage                 string                    `json:"statusMessage"`              // Human readable error message
 	InstrumentationLibraryName    string                    `json:"instrumentationLibraryName"` // Instrumentation library used to provide instrumentation
 	InstrumentationLibraryVersion string                    `json:"instrumentationLibraryVersion"`
-	Resource                      map[attribute.Key]interface{} `json:"resource,omitempty"` // Contains attributes representing an entity that produced this span
+	Resource                      Attributes                `json:"resource,omitempty"` // Contains attributes representing an entity that produced this span
+	ResourceRef                   string                    `json:"resourceRef,omitempty"` // ID of the resource, with WithResourceReferences
+	ServiceName                   string                    `json:"serviceName,omitempty"` // Service name configured with WithServiceName
 }
 
 // An event is a time-stamped annotation of the span that has user supplied text description and key-value pairs
 type Event struct {
 	Ts    int64                     `json:"ts"`    // The time at which the event occurred
 	Name  string                    `json:"name"`  // Event name
-	Attrs map[attribute.Key]interface{} `json:"attrs"` // collection of key-value pairs on the event
+	Attrs Attributes                `json:"attrs"` // collection of key-value pairs on the event
 }
 
 // A link contains references from this span to a span in the same or different trace
 type Link struct {
 	TraceID string                    `json:"traceId"`
 	SpanID  string                    `json:"spanId"`
-	Attrs   map[attribute.Key]interface{} `json:"attrs"`
-}
-
-func convertSpansToHttp(spans []sdktrace.ReadOnlySpan) []SpanData{
-	httpSpans := []SpanData{}
-	for _, span := range spans{
-		httpSpan := SpanData{}
-		httpSpan.TraceID = span.SpanContext().TraceID().String()
-		httpSpan.SpanID = span.SpanContext().SpanID().String()
-		httpSpan.ParentSpanID = span.Parent().SpanID().String()
-		httpSpan.SpanKind = span.SpanKind()
-		httpSpan.Name = span.Name()
-		httpSpan.StatusMessage = span.Status().Description
-		httpSpan.StatusCode = span.Status().Code.String()
-		httpSpan.StartTime = span.StartTime().UnixNano()
-		httpSpan.EndTime = span.EndTime().UnixNano()
-		httpSpan.InstrumentationLibraryName = span.InstrumentationLibrary().Name
-		httpSpan.InstrumentationLibraryVersion = span.InstrumentationLibrary().Version
-		httpSpan.Resource = attributesToMap(span.Resource().Attributes())
-
-		httpSpan.MessageEvents = eventsToSlice(span.Events())
-		httpSpan.Attrs = attributesToMap(span.Attributes())
-		httpSpan.Links = linksToSlice(span.Links())
-		httpSpans = append(httpSpans, httpSpan)
+	TraceState string                 `json:"traceState,omitempty"`
+	Flags   uint8                     `json:"flags"`
+	Attrs   Attributes                `json:"attrs"`
+}
+
+// IDEncoding selects how trace and span IDs are encoded in SpanData.
+type IDEncoding int
+
+const (
+	// Hex encodes IDs as lowercase hex strings, as in Zipkin and OTLP/JSON.
+	// This is the default.
+	Hex IDEncoding = iota
+	// Base64 encodes IDs as standard base64 strings, as in the canonical
+	// JSON mapping of protobuf bytes.
+	Base64
+)
+
+// String returns the name of the ID encoding.
+func (enc IDEncoding) String() string {
+	switch enc {
+	case Hex:
+		return "hex"
+	case Base64:
+		return "base64"
+	}
+	return "unknown"
+}
+
+// WithIDEncoding configures the encoding of trace and span IDs in SpanData.
+func WithIDEncoding(enc IDEncoding) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.idEncoding = enc
+		return cfg
+	})
+}
+
+// WithOmitEmptyParent configures SpanData of root spans to omit the
+// parentSpanId field, instead of holding an all zero span ID.
+func WithOmitEmptyParent() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.omitEmptyParent = true
+		return cfg
+	})
+}
+
+// converter converts spans to SpanData as configured.
+type converter struct {
+	idEncoding      IDEncoding
+	omitEmptyParent bool
+	serviceName     string
+	payloadVersion  PayloadVersion
+	refs            *resourceRefs // Set when resources are sent once per session
+}
+
+func newConverter(cfg config) converter {
+	return converter{
+		idEncoding:      cfg.idEncoding,
+		omitEmptyParent: cfg.omitEmptyParent,
+		serviceName:     cfg.serviceName,
+		payloadVersion:  cfg.payloadVersion,
 	}
-	return httpSpans
 }
 
+func (c converter) convertSpansToHttp(spans []sdktrace.ReadOnlySpan) []SpanData{
+	return c.appendSpansToHttp(make([]SpanData, 0, len(spans)), spans)
+}
 
-// attributesToMap converts attributes from a slice of key-values to a map for exporting
-func attributesToMap(attributes []attribute.KeyValue) map[attribute.Key]interface{} {
-	attrs := make(map[attribute.Key]interface{})
-	for _, v := range attributes {
-		attrs[v.Key] = v.Value.AsInterface()
+// appendSpansToHttp appends the converted spans to dst.
+func (c converter) appendSpansToHttp(dst []SpanData, spans []sdktrace.ReadOnlySpan) []SpanData {
+	for _, span := range spans {
+		dst = append(dst, c.convertSpanToHttp(span))
 	}
-	return attrs
+	return dst
 }
 
+// convertSpanToHttp converts a single span to its exported representation.
+func (c converter) convertSpanToHttp(span sdktrace.ReadOnlySpan) SpanData {
+	httpSpan := SpanData{}
+	httpSpan.TraceID = c.traceID(span.SpanContext().TraceID())
+	httpSpan.SpanID = c.spanID(span.SpanContext().SpanID())
+	if parent := span.Parent().SpanID(); parent.IsValid() || !c.omitEmptyParent {
+		httpSpan.ParentSpanID = c.spanID(parent)
+	}
+	httpSpan.TraceState = span.SpanContext().TraceState().String()
+	httpSpan.Flags = uint8(span.SpanContext().TraceFlags())
+	httpSpan.SpanKind = span.SpanKind()
+	httpSpan.Name = span.Name()
+	httpSpan.StatusMessage = span.Status().Description
+	httpSpan.StatusCode = span.Status().Code.String()
+	httpSpan.StartTime = span.StartTime().UnixNano()
+	httpSpan.EndTime = span.EndTime().UnixNano()
+	httpSpan.InstrumentationLibraryName = span.InstrumentationLibrary().Name
+	httpSpan.InstrumentationLibraryVersion = span.InstrumentationLibrary().Version
+	httpSpan.Resource = Attributes(span.Resource().Attributes())
+	if c.refs != nil {
+		var known bool
+		if httpSpan.ResourceRef, known = c.refs.ref(span.Resource()); known {
+			httpSpan.Resource = nil
+		}
+	}
+
+	httpSpan.MessageEvents = eventsToSlice(span.Events())
+	httpSpan.Attrs = Attributes(span.Attributes())
+	httpSpan.Links = c.linksToSlice(span.Links())
+	httpSpan.DroppedAttributeCount = span.DroppedAttributes()
+	httpSpan.DroppedLinkCount = span.DroppedLinks()
+	httpSpan.DroppedMessageEventCount = span.DroppedEvents()
+	httpSpan.ServiceName = c.serviceName
+	if c.payloadVersion >= PayloadV2 {
+		setStatusCodes(&httpSpan, span)
+	}
+	return httpSpan
+}
+
+
 // linksToSlice converts links from the format []trace.Link to []Link for exporting
-func linksToSlice(links []sdktrace.Link) []Link {
-	var l []Link
+func (c converter) linksToSlice(links []sdktrace.Link) []Link {
+	if len(links) == 0 {
+		return nil
+	}
+	l := make([]Link, 0, len(links))
 	for _, v := range links {
 		temp := Link{
-			TraceID: v.SpanContext.TraceID().String(),
-			SpanID:  v.SpanContext.SpanID().String(),
-			Attrs:   attributesToMap(v.Attributes),
+			TraceID: c.traceID(v.SpanContext.TraceID()),
+			SpanID:  c.spanID(v.SpanContext.SpanID()),
+			TraceState: v.SpanContext.TraceState().String(),
+			Flags:   uint8(v.SpanContext.TraceFlags()),
+			Attrs:   Attributes(v.Attributes),
 		}
 		l = append(l, temp)
 	}
 	return l
 }
 
+// traceID encodes a trace ID.
+func (c converter) traceID(id trace.TraceID) string {
+	if c.idEncoding == Base64 {
+		return base64.StdEncoding.EncodeToString(id[:])
+	}
+	return id.String()
+}
+
+// spanID encodes a span ID.
+func (c converter) spanID(id trace.SpanID) string {
+	if c.idEncoding == Base64 {
+		return base64.StdEncoding.EncodeToString(id[:])
+	}
+	return id.String()
+}
+
 // eventsToSlice converts events from the format []trace.Event to []Event for exporting
 func eventsToSlice(events []sdktrace.Event) []Event {
-	var e []Event
+	if len(events) == 0 {
+		return nil
+	}
+	e := make([]Event, 0, len(events))
 	for _, v := range events {
 		temp := Event{
 			Ts:    v.Time.UnixNano(),
 			Name:  v.Name,
-			Attrs: attributesToMap(v.Attributes),
+			Attrs: Attributes(v.Attributes),
 		}
 		e = append(e, temp)
 	}
 	return e
 }
+
+// attributeValueString renders an attribute value as a string, encoding
+// slices as JSON arrays, for formats that only support string tags.
+func attributeValueString(v attribute.Value) string {
+	switch v.Type() {
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE:
+		b, err := json.Marshal(v.AsInterface())
+		if err == nil {
+			return string(b)
+		}
+	}
+	return v.Emit()
+}
+
+// serviceName returns the service.name of a resource, or an empty string if
+// it is not set.
+func serviceName(res *resource.Resource) string {
+	if v, ok := res.Set().Value(semconv.ServiceNameKey); ok {
+		return v.AsString()
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package httpExporter
+
+import (
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestConvertTraceState(t *testing.T) {
+	ts, err := trace.ParseTraceState("vendor=value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    testTraceID,
+		SpanID:     testServerID,
+		TraceFlags: trace.FlagsSampled,
+		TraceState: ts,
+	})
+	span := tracetest.SpanStubs{{
+		Name:        "span",
+		SpanContext: sc,
+		Links:       []sdktrace.Link{{SpanContext: sc}},
+	}}.Snapshots()[0]
+
+	sd := newConverter(newConfig()).convertSpanToHttp(span)
+	if sd.TraceState != "vendor=value" || sd.Flags != 1 {
+		t.Errorf("span trace state %q, flags %d", sd.TraceState, sd.Flags)
+	}
+	if l := sd.Links[0]; l.TraceState != "vendor=value" || l.Flags != 1 {
+		t.Errorf("link trace state %q, flags %d", l.TraceState, l.Flags)
+	}
+}
+
+func TestConvertIDEncoding(t *testing.T) {
+	for _, tt := range []struct {
+		opts                  []Option
+		traceID, spanID, root string
+	}{
+		{nil, "66322d805a1b2c3d4e5f60718293a4b5", "00f067aa0ba902b7", "0000000000000000"},
+		{[]Option{WithIDEncoding(Base64)}, "ZjItgFobLD1OX2BxgpOktQ==", "APBnqgupArc=", "AAAAAAAAAAA="},
+		{[]Option{WithOmitEmptyParent()}, "66322d805a1b2c3d4e5f60718293a4b5", "00f067aa0ba902b7", ""},
+	} {
+		spans := newConverter(newConfig(tt.opts...)).convertSpansToHttp(testSpans())
+		root, child := spans[0], spans[1]
+		if root.TraceID != tt.traceID || root.SpanID != tt.spanID || root.ParentSpanID != tt.root {
+			t.Errorf("root span IDs %q, %q, parent %q, want %q, %q, %q",
+				root.TraceID, root.SpanID, root.ParentSpanID, tt.traceID, tt.spanID, tt.root)
+		}
+		if child.ParentSpanID != root.SpanID {
+			t.Errorf("child parentSpanId = %q, want %q", child.ParentSpanID, root.SpanID)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package httpExporter
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// datadogTracesPath is the Datadog trace agent ingestion path.
+	datadogTracesPath = "/v0.4/traces"
+	// datadogAPIKeyHeader is the header carrying the Datadog API key.
+	datadogAPIKeyHeader = "DD-Api-Key"
+)
+
+// datadogEncoder encodes spans as a Datadog trace payload: a JSON list of
+// traces, each a list of spans.
+type datadogEncoder struct{}
+
+func (datadogEncoder) contentType() string { return "application/json" }
+
+func (datadogEncoder) defaultPath() string { return datadogTracesPath }
+
+func (datadogEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var traces [][]datadogSpan
+	index := make(map[trace.TraceID]int)
+	for _, span := range spans {
+		tid := span.SpanContext().TraceID()
+		i, ok := index[tid]
+		if !ok {
+			i = len(traces)
+			index[tid] = i
+			traces = append(traces, nil)
+		}
+		traces[i] = append(traces[i], spanToDatadog(span))
+	}
+	return json.Marshal(traces)
+}
+
+type datadogSpan struct {
+	TraceID  uint64             `json:"trace_id"`
+	SpanID   uint64             `json:"span_id"`
+	ParentID uint64             `json:"parent_id"`
+	Name     string             `json:"name"`
+	Resource string             `json:"resource"`
+	Service  string             `json:"service"`
+	Type     string             `json:"type"`
+	Start    int64              `json:"start"`    // Epoch nanoseconds
+	Duration int64              `json:"duration"` // Nanoseconds
+	Error    int32              `json:"error"`
+	Meta     map[string]string  `json:"meta,omitempty"`
+	Metrics  map[string]float64 `json:"metrics,omitempty"`
+}
+
+// spanToDatadog converts a span to a Datadog span. Datadog IDs are 64 bit,
+// so the lower half of the trace ID is used and the upper half is recorded
+// in the _dd.p.tid tag.
+func spanToDatadog(span sdktrace.ReadOnlySpan) datadogSpan {
+	sc := span.SpanContext()
+	tid := sc.TraceID()
+	sid := sc.SpanID()
+	psid := span.Parent().SpanID()
+	lib := span.InstrumentationLibrary()
+
+	opName := lib.Name
+	if opName == "" {
+		opName = "opentelemetry"
+	}
+	ds := datadogSpan{
+		TraceID:  binary.BigEndian.Uint64(tid[8:]),
+		SpanID:   binary.BigEndian.Uint64(sid[:]),
+		ParentID: binary.BigEndian.Uint64(psid[:]),
+		Name:     fmt.Sprintf("%s.%s", opName, span.SpanKind()),
+		Resource: span.Name(),
+		Service:  serviceName(span.Resource()),
+		Type:     datadogType(span.SpanKind()),
+		Start:    span.StartTime().UnixNano(),
+		Duration: span.EndTime().Sub(span.StartTime()).Nanoseconds(),
+		Meta: map[string]string{
+			"_dd.p.tid": fmt.Sprintf("%016x", binary.BigEndian.Uint64(tid[:8])),
+			"span.kind": span.SpanKind().String(),
+		},
+		Metrics: make(map[string]float64),
+	}
+	if lib.Name != "" {
+		ds.Meta["otel.library.name"] = lib.Name
+		if lib.Version != "" {
+			ds.Meta["otel.library.version"] = lib.Version
+		}
+	}
+	datadogTags(ds.Meta, ds.Metrics, span.Resource().Attributes())
+	datadogTags(ds.Meta, ds.Metrics, span.Attributes())
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		ds.Meta["otel.status_code"] = "OK"
+	case codes.Error:
+		ds.Error = 1
+		ds.Meta["otel.status_code"] = "ERROR"
+		if status.Description != "" {
+			ds.Meta["error.message"] = status.Description
+		}
+	}
+	return ds
+}
+
+// datadogTags adds attributes to the span tags: numbers as metrics, anything
+// else as string meta.
+func datadogTags(meta map[string]string, metrics map[string]float64, attrs []attribute.KeyValue) {
+	for _, kv := range attrs {
+		switch kv.Value.Type() {
+		case attribute.INT64:
+			metrics[string(kv.Key)] = float64(kv.Value.AsInt64())
+		case attribute.FLOAT64:
+			metrics[string(kv.Key)] = kv.Value.AsFloat64()
+		default:
+			meta[string(kv.Key)] = attributeValueString(kv.Value)
+		}
+	}
+}
+
+// datadogType maps a span kind to a Datadog span type.
+func datadogType(kind trace.SpanKind) string {
+	switch kind {
+	case trace.SpanKindServer:
+		return "web"
+	case trace.SpanKindClient:
+		return "http"
+	}
+	return "custom"
+}
+
+// WithDatadogAPIKey configures the API key sent in the DD-Api-Key header, as
+// required when posting directly to the Datadog intake.
+func WithDatadogAPIKey(key string) Option {
+	return WithHeaders(map[string]string{datadogAPIKeyHeader: key})
+}
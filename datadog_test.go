@@ -0,0 +1,16 @@
+package httpExporter
+
+import "testing"
+
+func TestDatadog(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(Datadog))
+	checkEncoder(t, enc, "application/json", "/v0.4/traces")
+	checkGoldenJSON(t, "datadog.json", body)
+}
+
+func TestDatadogAPIKey(t *testing.T) {
+	cfg := newConfig(WithDatadogAPIKey("secret"))
+	if got := cfg.headers["DD-Api-Key"]; got != "secret" {
+		t.Errorf("DD-Api-Key header = %q, want secret", got)
+	}
+}
@@ -0,0 +1,128 @@
+package httpExporter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// WithDebugDump configures the exporter to write every request body it
+// sends to a file in dir, named after the time it was sent and with an
+// extension matching its content type. When the collector does not accept
+// the request, its response (status, headers and the start of the body) or
+// the request error is written next to it, with the .response or .error
+// extension. The directory is created if it does not exist. Failures to
+// write dumps are logged and do not affect exports.
+func WithDebugDump(dir string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.debugDumpDir = dir
+		return cfg
+	})
+}
+
+// dumpExtensions maps content types to the extension of their dumps.
+var dumpExtensions = map[string]string{
+	"application/json":                     ".json",
+	"application/x-ndjson":                 ".ndjson",
+	"application/x-protobuf":               ".pb",
+	"application/cbor":                     ".cbor",
+	"application/vnd.apache.thrift.binary": ".thrift",
+}
+
+// dumper writes request and response dumps.
+type dumper struct {
+	dir string
+	seq uint64 // Disambiguates requests sent at the same instant
+}
+
+func newDumper(dir string) (*dumper, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create debug dump directory: %v", err)
+	}
+	return &dumper{dir: dir}, nil
+}
+
+// request dumps a request body and returns the path prefix of the dump, for
+// the response dump.
+func (d *dumper) request(contentType string, body []byte) (string, error) {
+	prefix := filepath.Join(d.dir, fmt.Sprintf("%s-%06d",
+		time.Now().UTC().Format("20060102T150405.000000000Z"), atomic.AddUint64(&d.seq, 1)))
+	return prefix, ioutil.WriteFile(prefix+dumpExtension(contentType), body, 0o600)
+}
+
+// dumpExtension returns the extension of dumps of request bodies of the
+// given content type.
+func dumpExtension(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		if ext, ok := dumpExtensions[mt]; ok {
+			return ext
+		}
+	}
+	return ".bin"
+}
+
+// dumpContentType returns the content type of request bodies dumped with the
+// given extension, or false if it is not the extension of a request dump.
+func dumpContentType(ext string) (string, bool) {
+	if ext == ".bin" {
+		return "application/octet-stream", true
+	}
+	for ct, e := range dumpExtensions {
+		if e == ext {
+			return ct, true
+		}
+	}
+	return "", false
+}
+
+// response dumps the response to a request that was not accepted.
+func (d *dumper) response(prefix string, resp *http.Response, body []byte) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s\r\n", resp.Proto, resp.Status)
+	resp.Header.Write(&b)
+	b.WriteString("\r\n")
+	b.Write(body)
+	return ioutil.WriteFile(prefix+".response", b.Bytes(), 0o600)
+}
+
+// failure dumps the error of a request that got no response.
+func (d *dumper) failure(prefix string, err error) error {
+	return ioutil.WriteFile(prefix+".error", []byte(err.Error()+"\n"), 0o600)
+}
+
+// dumpRequest dumps a request body when debug dumps are enabled, returning
+// the path prefix of the dump or an empty string.
+func (e *Exporter) dumpRequest(contentType string, body []byte) string {
+	if e.dumper == nil {
+		return ""
+	}
+	prefix, err := e.dumper.request(contentType, body)
+	if err != nil {
+		e.warnf("failed to write debug dump: %v", err)
+		return ""
+	}
+	return prefix
+}
+
+// dumpResponse dumps the response to, or error of, a request that was not
+// accepted, if its body was dumped.
+func (e *Exporter) dumpResponse(prefix string, resp *http.Response, body []byte, reqErr error) {
+	if prefix == "" {
+		return
+	}
+	var err error
+	if resp != nil {
+		err = e.dumper.response(prefix, resp, body)
+	} else {
+		err = e.dumper.failure(prefix, reqErr)
+	}
+	if err != nil {
+		e.warnf("failed to write debug dump: %v", err)
+	}
+}
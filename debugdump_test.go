@@ -0,0 +1,65 @@
+package httpExporter_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestDebugDump(t *testing.T) {
+	c := newCollector(t)
+	dir := filepath.Join(t.TempDir(), "dumps")
+	e := newExporter(t, c.URL(), httpExporter.WithDebugDump(dir))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	c.setStatus(http.StatusBadRequest)
+	_ = e.ExportSpans(ctx, newSpans("b"))
+
+	bodies, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(bodies) != 2 {
+		t.Fatalf("dumped %v, want the bodies of both requests", bodies)
+	}
+	reqs := c.received()
+	for i, name := range bodies {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != string(reqs[i].Body) {
+			t.Errorf("%s holds %s, want %s", name, b, reqs[i].Body)
+		}
+	}
+	responses, _ := filepath.Glob(filepath.Join(dir, "*.response"))
+	if len(responses) != 1 || strings.TrimSuffix(responses[0], ".response") != strings.TrimSuffix(bodies[1], ".json") {
+		t.Fatalf("dumped responses %v, want one next to %s", responses, bodies[1])
+	}
+	b, _ := ioutil.ReadFile(responses[0])
+	if !strings.HasPrefix(string(b), "HTTP/1.1 400 Bad Request\r\n") {
+		t.Errorf("response dump starts with %q", b)
+	}
+}
+
+func TestDebugDumpRequestError(t *testing.T) {
+	c := newCollector(t)
+	url := c.URL()
+	c.server.Close()
+	dir := t.TempDir()
+	e := newExporter(t, url, httpExporter.WithDebugDump(dir))
+	_ = e.ExportSpans(context.Background(), newSpans("a"))
+
+	errs, _ := filepath.Glob(filepath.Join(dir, "*.error"))
+	if len(errs) != 1 {
+		t.Fatalf("dumped errors %v, want one", errs)
+	}
+	if b, _ := ioutil.ReadFile(errs[0]); !strings.Contains(string(b), "connection refused") {
+		t.Errorf("error dump holds %q", b)
+	}
+}
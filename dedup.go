@@ -0,0 +1,146 @@
+package httpExporter
+
+import (
+	"container/list"
+	"encoding/hex"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithDeduplication configures the exporter to remember the last size spans
+// acknowledged by the primary collector, by trace and span ID, and to discard
+// those exported again instead of re-sending them, for instance when an
+// application retries an export or the same spans reach the exporter through
+// several pipelines. The spans of a batch the collector partially rejected
+// are not remembered, as which ones were rejected is unknown. Batches
+// persisted by WithPersistence whose spans were all acknowledged meanwhile
+// are discarded rather than replayed.
+func WithDeduplication(size int) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.dedupSize = size
+		return cfg
+	})
+}
+
+// spanKey identifies a span.
+type spanKey struct {
+	traceID trace.TraceID
+	spanID  trace.SpanID
+}
+
+// dedupCache is an LRU set of the spans acknowledged by the collector.
+type dedupCache struct {
+	size int
+
+	mu    sync.Mutex
+	spans map[spanKey]*list.Element
+	order *list.List // Keys, most recently used first
+}
+
+func newDedupCache(size int) *dedupCache {
+	return &dedupCache{
+		size:  size,
+		spans: make(map[spanKey]*list.Element),
+		order: list.New(),
+	}
+}
+
+func keyOf(span sdktrace.ReadOnlySpan) spanKey {
+	sc := span.SpanContext()
+	return spanKey{traceID: sc.TraceID(), spanID: sc.SpanID()}
+}
+
+func keysOf(spans []sdktrace.ReadOnlySpan) []spanKey {
+	keys := make([]spanKey, 0, len(spans))
+	for _, span := range spans {
+		keys = append(keys, keyOf(span))
+	}
+	return keys
+}
+
+// String returns the hex trace ID followed by the hex span ID.
+func (k spanKey) String() string {
+	return k.traceID.String() + k.spanID.String()
+}
+
+// parseSpanKey parses the string form of a span key.
+func parseSpanKey(s string) (spanKey, bool) {
+	var k spanKey
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(k.traceID)+len(k.spanID) {
+		return spanKey{}, false
+	}
+	copy(k.traceID[:], b)
+	copy(k.spanID[:], b[len(k.traceID):])
+	return k, true
+}
+
+// filter returns the spans not acknowledged yet. The spans slice is not
+// modified, as it belongs to the caller.
+func (c *dedupCache) filter(spans []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var kept []sdktrace.ReadOnlySpan
+	for i, span := range spans {
+		el, seen := c.spans[keyOf(span)]
+		if !seen {
+			if kept != nil {
+				kept = append(kept, span)
+			}
+			continue
+		}
+		c.order.MoveToFront(el)
+		if kept == nil {
+			kept = make([]sdktrace.ReadOnlySpan, i, len(spans)-1)
+			copy(kept, spans[:i])
+		}
+	}
+	if kept == nil {
+		return spans
+	}
+	return kept
+}
+
+// acknowledged reports whether every span of keys is acknowledged.
+func (c *dedupCache) acknowledged(keys []spanKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := c.spans[key]; !ok {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
+
+// add records spans as acknowledged, evicting the least recently used ones
+// beyond the size of the cache.
+func (c *dedupCache) add(keys []spanKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := c.spans[key]; ok {
+			c.order.MoveToFront(el)
+			continue
+		}
+		c.spans[key] = c.order.PushFront(key)
+		if c.order.Len() > c.size {
+			delete(c.spans, c.order.Remove(c.order.Back()).(spanKey))
+		}
+	}
+}
+
+// deduplicate returns the spans that were not acknowledged yet when
+// deduplication is configured.
+func (e *Exporter) deduplicate(spans []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	if e.dedup == nil {
+		return spans
+	}
+	kept := e.dedup.filter(spans)
+	if len(kept) < len(spans) {
+		e.logf("discarded %d already acknowledged spans", len(spans)-len(kept))
+	}
+	return kept
+}
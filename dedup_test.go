@@ -0,0 +1,131 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestDeduplication(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithDeduplication(10))
+
+	ctx := context.Background()
+	spans := newSpans("a", "b")
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// An export retried by the application only sends the new spans.
+	if err := e.ExportSpans(ctx, append(append([]sdktrace.ReadOnlySpan(nil), spans...), newSpans("c")...)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c") {
+		t.Errorf("collector received %v, want every span once", got)
+	}
+	if got := c.count(); got != 2 {
+		t.Errorf("collector received %d requests, want none for a duplicate batch", got)
+	}
+}
+
+func TestDeduplicationFailedExport(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithDeduplication(10))
+
+	ctx := context.Background()
+	spans := newSpans("a")
+	c.respond(http.StatusServiceUnavailable)
+	if err := e.ExportSpans(ctx, spans); err == nil {
+		t.Fatal("ExportSpans succeeded with a 503 response")
+	}
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want the span that was not acknowledged re-sent", got)
+	}
+}
+
+func TestDeduplicationEviction(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithDeduplication(1))
+
+	ctx := context.Background()
+	a, b := newSpans("a"), newSpans("b")
+	for _, spans := range [][]sdktrace.ReadOnlySpan{a, b, a} {
+		if err := e.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if got := c.names(t); !equal(got, "a", "b", "a") {
+		t.Errorf("collector received %v, want a re-sent once evicted", got)
+	}
+}
+
+func TestDeduplicationPartialSuccess(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"accepted": 1, "rejected": 1, "error": "quota"}`))
+		}
+	}))
+	defer srv.Close()
+	e := newExporter(t, srv.URL, httpExporter.WithDeduplication(10))
+
+	ctx := context.Background()
+	spans := newSpans("a", "b")
+	for i := 0; i < 2; i++ {
+		if err := e.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	// Which span was rejected is unknown: none is remembered.
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("collector received %d requests, want the partially rejected batch re-sent", got)
+	}
+}
+
+func TestDeduplicationPersistedBatch(t *testing.T) {
+	c := newCollector(t)
+	dir := t.TempDir()
+	e := newExporter(t, c.URL(), httpExporter.WithDeduplication(10), httpExporter.WithPersistence(httpExporter.PersistenceConfig{Dir: dir}))
+
+	ctx := context.Background()
+	a, b := newSpans("a"), newSpans("b")
+	c.respond(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	for _, spans := range [][]sdktrace.ReadOnlySpan{a, b} {
+		if err := e.ExportSpans(ctx, spans); err == nil {
+			t.Fatal("ExportSpans succeeded with a 503 response")
+		}
+	}
+	// The application retries the export of a, which the persisted copy
+	// need not be replayed for anymore.
+	if err := e.ExportSpans(ctx, a); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b") {
+		t.Fatalf("collector received %v, want a once and the replayed b", got)
+	}
+	if got := persistedBatches(t, dir); len(got) != 0 {
+		t.Errorf("%d batches left persisted", len(got))
+	}
+
+	// The replayed spans are acknowledged.
+	if err := e.ExportSpans(ctx, b); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b") {
+		t.Errorf("collector received %v, want the replayed span deduplicated", got)
+	}
+}
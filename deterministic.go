@@ -0,0 +1,30 @@
+package httpExporter
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithDeterministicEncoding configures the exporter to encode equal batches
+// to identical bytes, for receivers diffing or checksumming payloads and for
+// golden tests: span, event and link attributes are sorted by key rather than
+// kept in the order they were set, whatever the format. Resource attributes
+// and object fields are always encoded in a stable order.
+func WithDeterministicEncoding() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.deterministic = true
+		return cfg
+	})
+}
+
+// sortAttributes returns attrs sorted by key when deterministic encoding is
+// configured. The attrs slice is not modified, as it may belong to the span.
+func (e *Exporter) sortAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if !e.deterministic || len(attrs) < 2 || sort.SliceIsSorted(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key }) {
+		return attrs
+	}
+	sorted := append([]attribute.KeyValue(nil), attrs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	return sorted
+}
@@ -0,0 +1,61 @@
+package httpExporter_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// encodeDryRun returns the body of a request sending spans, encoded by an
+// exporter configured with opts.
+func encodeDryRun(t *testing.T, spans []sdktrace.ReadOnlySpan, opts ...httpExporter.Option) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	e := newExporter(t, "http://localhost:1", append(opts, httpExporter.WithDryRun(&buf))...)
+	if err := e.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDeterministicEncoding(t *testing.T) {
+	now := time.Now()
+	stub := tracetest.SpanStub{
+		Name:        "span",
+		SpanContext: newSpanContext(newTraceID()),
+		StartTime:   now,
+		EndTime:     now,
+		Attributes:  []attribute.KeyValue{attribute.String("b", "2"), attribute.String("a", "1")},
+		Events: []sdktrace.Event{{
+			Name:       "event",
+			Time:       now,
+			Attributes: []attribute.KeyValue{attribute.Int("y", 2), attribute.Int("x", 1)},
+		}},
+	}
+	reordered := stub
+	reordered.Attributes = []attribute.KeyValue{stub.Attributes[1], stub.Attributes[0]}
+	reordered.Events = []sdktrace.Event{stub.Events[0]}
+	reordered.Events[0].Attributes = []attribute.KeyValue{stub.Events[0].Attributes[1], stub.Events[0].Attributes[0]}
+	spans := tracetest.SpanStubs{stub}.Snapshots()
+	reorderedSpans := tracetest.SpanStubs{reordered}.Snapshots()
+
+	format := httpExporter.WithFormat(httpExporter.OTLPProtobuf)
+	if bytes.Equal(encodeDryRun(t, spans, format), encodeDryRun(t, reorderedSpans, format)) {
+		t.Fatal("attributes encoded in the same order by default")
+	}
+	got := encodeDryRun(t, spans, format, httpExporter.WithDeterministicEncoding())
+	want := encodeDryRun(t, reorderedSpans, format, httpExporter.WithDeterministicEncoding())
+	if !bytes.Equal(got, want) {
+		t.Errorf("equal batches encoded differently:\n%x\n%x", got, want)
+	}
+	// The spans of the caller are not modified.
+	if spans[0].Attributes()[0].Key != "b" {
+		t.Error("WithDeterministicEncoding sorted the attributes of the span")
+	}
+}
@@ -0,0 +1,42 @@
+package httpExporter
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// WithDryRun configures the exporter to write the request bodies it would
+// send to w instead of sending them, so the exact payloads can be inspected
+// and no collector is needed. Text bodies are terminated by a newline.
+// Additional endpoints are not written to.
+func WithDryRun(w io.Writer) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.sink = &dryRunWriter{w: w}
+		return cfg
+	})
+}
+
+// sink receives request bodies in place of the collectors.
+type sink interface {
+	write(contentType string, body []byte) error
+}
+
+// dryRunWriter serializes writes of request bodies.
+type dryRunWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *dryRunWriter) write(contentType string, body []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.w.Write(body); err != nil {
+		return err
+	}
+	if strings.Contains(contentType, "json") && (len(body) == 0 || body[len(body)-1] != '\n') {
+		_, err := io.WriteString(d.w, "\n")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package httpExporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestDryRun(t *testing.T) {
+	var buf bytes.Buffer
+	e := newExporter(t, "http://localhost:1", httpExporter.WithDryRun(&buf))
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %q, want a line per batch", buf.String())
+	}
+	for i, want := range []string{"a", "b"} {
+		var batch []span
+		if err := json.Unmarshal([]byte(lines[i]), &batch); err != nil {
+			t.Fatalf("invalid batch %s: %v", lines[i], err)
+		}
+		if got := spanNames(batch); !equal(got, want) {
+			t.Errorf("batch %d holds %v, want [%s]", i, got, want)
+		}
+	}
+}
+
+func TestDryRunBinary(t *testing.T) {
+	var buf bytes.Buffer
+	e := newExporter(t, "http://localhost:1", httpExporter.WithDryRun(&buf), httpExporter.WithFormat(httpExporter.OTLPProtobuf))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// Binary bodies are written as they are, without a newline.
+	if buf.Len() == 0 || json.Valid(buf.Bytes()) {
+		t.Errorf("wrote %q, want a protobuf body", buf.Bytes())
+	}
+}
@@ -0,0 +1,118 @@
+package httpExporter
+
+import (
+	"net/url"
+	"time"
+)
+
+// redacted replaces secrets in an effective configuration.
+const redacted = "REDACTED"
+
+// EffectiveConfig is the configuration an exporter resolved from its
+// options, the environment variables and the defaults, for debugging. Header
+// values and the passwords of URLs are redacted.
+type EffectiveConfig struct {
+	URL                 string   // Primary collector, with its path
+	PoolEndpoints       []string // Replicas of the primary collector
+	FallbackEndpoints   []string
+	AdditionalEndpoints []string // Mirrors
+	Format              string
+	Headers             map[string]string
+	Timeout             time.Duration
+	Compression         string
+	Encryption          bool
+	Retry               RetryConfig
+	TLS                 bool // Whether custom TLS settings are used
+	Insecure            bool
+	Proxy               string
+	UserAgent           string
+	Streaming           bool
+
+	QueueCapacity        int // 0 when batches are sent synchronously
+	QueuePolicy          string
+	Backpressure         string
+	MaxConcurrentExports int
+	MaxPayloadBytes      int
+	RateLimit            float64 // Spans per second, 0 when unlimited
+	RateBurst            int
+	BreakerFailures      int // 0 without a circuit breaker
+	BreakerCooldown      time.Duration
+	FailoverThreshold    int // 0 without fallback collectors
+	FailoverProbe        time.Duration
+}
+
+// EffectiveConfig returns the resolved configuration of the exporter.
+func (e *Exporter) EffectiveConfig() EffectiveConfig {
+	return e.effective
+}
+
+// newEffectiveConfig returns the effective configuration of an exporter
+// created from cfg.
+func newEffectiveConfig(cfg config, e *Exporter) EffectiveConfig {
+	ec := EffectiveConfig{
+		URL:                  redactURL(e.endpoints[0].url),
+		AdditionalEndpoints:  endpointURLs(e.endpoints[1:]),
+		Format:               cfg.format.String(),
+		Timeout:              e.timeout,
+		Compression:          e.compression.String(),
+		Encryption:           e.encrypter != nil,
+		Retry:                e.retry,
+		TLS:                  cfg.tlsConfig != nil || cfg.certificateFile != "",
+		Insecure:             cfg.insecure,
+		UserAgent:            e.userAgent,
+		Streaming:            cfg.streaming,
+		QueueCapacity:        cfg.queueCapacity,
+		Backpressure:         e.backpressure.String(),
+		MaxConcurrentExports: cfg.maxConcurrentExports,
+		MaxPayloadBytes:      e.maxPayloadBytes,
+		RateLimit:            cfg.rateLimit,
+		BreakerFailures:      cfg.breakerFailures,
+	}
+	if e.pool != nil {
+		ec.PoolEndpoints = endpointURLs(e.pool.endpoints[1:])
+	}
+	if e.failover != nil {
+		ec.FallbackEndpoints = endpointURLs(e.failover.fallbacks)
+		ec.FailoverThreshold = e.failover.threshold
+		ec.FailoverProbe = e.failover.probeInterval
+	}
+	if len(e.headers) > 0 {
+		ec.Headers = make(map[string]string, len(e.headers))
+		for k := range e.headers {
+			ec.Headers[k] = redacted
+		}
+	}
+	if cfg.proxyURL != "" {
+		ec.Proxy = redactURL(cfg.proxyURL)
+	}
+	if cfg.queueCapacity > 0 {
+		ec.QueuePolicy = cfg.backpressure.queuePolicy(cfg.queuePolicy).String()
+	}
+	if cfg.rateLimit > 0 {
+		ec.RateBurst = cfg.rateBurst
+	}
+	if cfg.breakerFailures > 0 {
+		ec.BreakerCooldown = cfg.breakerCooldown
+	}
+	return ec
+}
+
+func endpointURLs(eps []*endpoint) []string {
+	if len(eps) == 0 {
+		return nil
+	}
+	urls := make([]string, 0, len(eps))
+	for _, ep := range eps {
+		urls = append(urls, redactURL(ep.url))
+	}
+	return urls
+}
+
+// redactURL returns rawURL with its password redacted.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,160 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// elasticsearchBulkPath is the Elasticsearch bulk API path.
+	elasticsearchBulkPath = "/_bulk"
+	// defaultElasticsearchIndex is the index name template used when none
+	// is configured.
+	defaultElasticsearchIndex = "traces-{service}-{yyyy.MM.dd}"
+)
+
+// elasticsearchEncoder encodes spans as an Elasticsearch bulk request: for
+// each span an action line naming the target index followed by the span
+// document.
+type elasticsearchEncoder struct {
+	index string
+	conv  converter
+}
+
+func (elasticsearchEncoder) contentType() string { return "application/x-ndjson" }
+
+func (elasticsearchEncoder) defaultPath() string { return elasticsearchBulkPath }
+
+func (enc elasticsearchEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	return encodeBuffer(enc, spans)
+}
+
+func (enc elasticsearchEncoder) encodeTo(buf *bytes.Buffer, spans []sdktrace.ReadOnlySpan) error {
+	w := json.NewEncoder(buf)
+	for _, span := range spans {
+		// The create action is used as it is accepted by both regular
+		// indices and data streams.
+		var action struct {
+			Create struct {
+				Index string `json:"_index"`
+			} `json:"create"`
+		}
+		action.Create.Index = elasticsearchIndex(enc.index, serviceName(span.Resource()), span.StartTime())
+		if err := w.Encode(action); err != nil {
+			return err
+		}
+		doc := elasticsearchDocument{
+			Timestamp: span.StartTime().UTC().Format(time.RFC3339Nano),
+			SpanData:  enc.conv.convertSpanToHttp(span),
+		}
+		if err := w.Encode(&doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elasticsearchDocument is a span document with the @timestamp field
+// required by data streams and Kibana.
+type elasticsearchDocument struct {
+	Timestamp string `json:"@timestamp"`
+	SpanData
+}
+
+// elasticsearchIndex expands an index name template. {service} is replaced
+// with the service name and any other {...} placeholder is treated as a date
+// pattern (see formatJavaDate) applied to the span start time in UTC. Index
+// names are lowercased as required by Elasticsearch.
+func elasticsearchIndex(template, service string, t time.Time) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		b.WriteString(template[:start])
+		switch placeholder := template[start+1 : start+end]; placeholder {
+		case "service":
+			if service == "" {
+				service = "unknown_service"
+			}
+			b.WriteString(elasticsearchIndexChars.Replace(service))
+		default:
+			formatJavaDate(&b, placeholder, t.UTC())
+		}
+		template = template[start+end+1:]
+	}
+	b.WriteString(template)
+	return strings.ToLower(b.String())
+}
+
+// elasticsearchIndexChars replaces characters that are not allowed in
+// Elasticsearch index names.
+var elasticsearchIndexChars = strings.NewReplacer(
+	"\\", "_", "/", "_", "*", "_", "?", "_", "\"", "_", "<", "_",
+	">", "_", "|", "_", " ", "_", ",", "_", "#", "_", ":", "_",
+)
+
+// javaDateTokens maps the Java style date pattern tokens used by
+// Elasticsearch tooling to Go time layouts.
+var javaDateTokens = map[string]string{
+	"yyyy": "2006",
+	"yy":   "06",
+	"MM":   "01",
+	"dd":   "02",
+	"HH":   "15",
+	"mm":   "04",
+	"ss":   "05",
+}
+
+// formatJavaDate writes t formatted with a Java style date pattern to b.
+// Runs of a pattern letter are replaced when they form a known token, text in
+// single quotes is literal, two single quotes stand for one, and everything
+// else is copied as is rather than being read as a Go layout.
+func formatJavaDate(b *strings.Builder, pattern string, t time.Time) {
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			switch {
+			case end < 0:
+				b.WriteString(pattern[i+1:])
+				return
+			case end == 0:
+				b.WriteByte('\'')
+			default:
+				b.WriteString(pattern[i+1 : i+1+end])
+			}
+			i += end + 2
+			continue
+		}
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == c {
+			n++
+		}
+		token := pattern[i : i+n]
+		if layout, ok := javaDateTokens[token]; ok {
+			b.WriteString(t.Format(layout))
+		} else {
+			b.WriteString(token)
+		}
+		i += n
+	}
+}
+
+// WithElasticsearchIndex configures the index name template used by the
+// ElasticsearchBulk format, e.g. "traces-{service}-{yyyy.MM.dd}".
+func WithElasticsearchIndex(template string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.elasticsearchIndex = template
+		return cfg
+	})
+}
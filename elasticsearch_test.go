@@ -0,0 +1,58 @@
+package httpExporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestElasticsearchBulk(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(ElasticsearchBulk))
+	checkEncoder(t, enc, "application/x-ndjson", "/_bulk")
+	checkGolden(t, "elasticsearch.ndjson", body)
+}
+
+func TestElasticsearchIndex(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 23, 4, 5, 0, time.FixedZone("CEST", 2*60*60))
+	for _, test := range []struct {
+		template, service, want string
+	}{
+		{"traces-{service}-{yyyy.MM.dd}", "checkout", "traces-checkout-2024.05.01"},
+		{"traces-{service}", "", "traces-unknown_service"},
+		{"traces-{service}", "Shop/Cart Service", "traces-shop_cart_service"},
+		{"traces-{yyyy-MM-dd-HH}", "checkout", "traces-2024-05-01-21"},
+		{"traces-{yy.MM}", "checkout", "traces-24.05"},
+		{"static", "checkout", "static"},
+		{"traces-{yyyy'w'MM}", "checkout", "traces-2024w05"},
+		{"traces-{'yyyy'-yyyy}", "checkout", "traces-yyyy-2024"},
+		{"traces-{yyyy''MM}", "checkout", "traces-2024'05"},
+		{"traces-{'day'1-dd}", "checkout", "traces-day1-01"},
+		{"traces-{yyyy.Jan}", "checkout", "traces-2024.jan"},
+	} {
+		if got := elasticsearchIndex(test.template, test.service, ts); got != test.want {
+			t.Errorf("elasticsearchIndex(%q, %q) = %q, want %q", test.template, test.service, got, test.want)
+		}
+	}
+}
+
+func TestElasticsearchBulkErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"took": 3, "errors": true, "items": [{"create": {"status": 201}}, {"create": {"status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse"}}}]}`))
+	}))
+	defer srv.Close()
+	e, err := New(srv.URL, WithFormat(ElasticsearchBulk))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+
+	if err := e.ExportSpans(context.Background(), testSpans()[:2]); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if s := e.Stats(); s.SpansSent != 1 || s.SpansRejected != 1 {
+		t.Errorf("Stats() = %+v, want the failed bulk item counted as rejected", s)
+	}
+}
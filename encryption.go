@@ -0,0 +1,62 @@
+package httpExporter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Headers of encrypted requests.
+const (
+	encryptionHeader      = "X-Payload-Encryption"
+	encryptionKeyIDHeader = "X-Payload-Key-ID"
+	encryptionAlgorithm   = "aes-gcm"
+)
+
+// WithPayloadEncryption configures the exporter to encrypt request bodies
+// with AES-GCM using key, which must be 16, 24 or 32 bytes long to select
+// AES-128, AES-192 or AES-256, so span contents stay opaque to proxies
+// terminating TLS. The body is compressed first, then replaced by a random
+// 12 byte nonce followed by the sealed body. Requests carry an
+// X-Payload-Encryption: aes-gcm header and an X-Payload-Key-ID header holding
+// the first 8 bytes of the SHA-256 of key in hex, for receivers to select
+// the key to decrypt with. The Content-Type and Content-Encoding headers
+// describe the decrypted body. It cannot be combined with WithStreaming,
+// whose frames are sent unencrypted.
+func WithPayloadEncryption(key []byte) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.encryptionKey = append([]byte(nil), key...)
+		return cfg
+	})
+}
+
+// encrypter seals request bodies.
+type encrypter struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+func newEncrypter(key []byte) (*encrypter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encryption key: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encryption key: %v", err)
+	}
+	sum := sha256.Sum256(key)
+	return &encrypter{aead: aead, keyID: hex.EncodeToString(sum[:8])}, nil
+}
+
+// seal returns body encrypted behind a random nonce.
+func (enc *encrypter) seal(body []byte) ([]byte, error) {
+	nonce := make([]byte, enc.aead.NonceSize(), enc.aead.NonceSize()+len(body)+enc.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return enc.aead.Seal(nonce, nonce, body, nil), nil
+}
@@ -0,0 +1,120 @@
+package httpExporter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// testKey is an AES-128 key, whose ID is the start of its SHA-256.
+var (
+	testKey   = []byte("0123456789abcdef")
+	testKeyID = "9f9f5111f7b27a78"
+)
+
+// encryptedRequest is a request received by an encrypted collector.
+type encryptedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newEncryptedCollector starts a collector recording raw request bodies,
+// which it cannot decompress before decrypting them.
+func newEncryptedCollector(t *testing.T) (*httptest.Server, chan encryptedRequest) {
+	t.Helper()
+	reqs := make(chan encryptedRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		reqs <- encryptedRequest{header: r.Header.Clone(), body: body}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs
+}
+
+// open decrypts a request body sealed with testKey.
+func open(t *testing.T, body []byte) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body) < aead.NonceSize() {
+		t.Fatalf("body of %d bytes holds no nonce", len(body))
+	}
+	plain, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], nil)
+	if err != nil {
+		t.Fatalf("failed to decrypt body: %v", err)
+	}
+	return plain
+}
+
+func TestPayloadEncryption(t *testing.T) {
+	srv, reqs := newEncryptedCollector(t)
+	e := newExporter(t, srv.URL, httpExporter.WithPayloadEncryption(testKey))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	r := <-reqs
+	if r.header.Get("X-Payload-Encryption") != "aes-gcm" || r.header.Get("X-Payload-Key-ID") != testKeyID {
+		t.Errorf("encryption headers %v", r.header)
+	}
+	if got := r.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want the one of the decrypted body", got)
+	}
+	var batch []span
+	if err := json.Unmarshal(open(t, r.body), &batch); err != nil {
+		t.Fatalf("invalid decrypted batch: %v", err)
+	}
+	if got := spanNames(batch); !equal(got, "a") {
+		t.Errorf("decrypted batch holds %v, want [a]", got)
+	}
+}
+
+func TestPayloadEncryptionCompressed(t *testing.T) {
+	srv, reqs := newEncryptedCollector(t)
+	e := newExporter(t, srv.URL, httpExporter.WithPayloadEncryption(testKey), httpExporter.WithCompression(httpExporter.GzipCompression))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := e.ExportSpans(ctx, newSpans("a")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	first, second := <-reqs, <-reqs
+	if bytes.Equal(first.body[:12], second.body[:12]) {
+		t.Error("requests encrypted with the same nonce")
+	}
+	if got := first.header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+	// The body is compressed before being encrypted.
+	zr, err := gzip.NewReader(bytes.NewReader(open(t, first.body)))
+	if err != nil {
+		t.Fatalf("decrypted body is not compressed: %v", err)
+	}
+	var batch []span
+	if err := json.NewDecoder(zr).Decode(&batch); err != nil {
+		t.Fatalf("invalid decrypted batch: %v", err)
+	}
+	if got := spanNames(batch); !equal(got, "a") {
+		t.Errorf("decrypted batch holds %v, want [a]", got)
+	}
+}
+
+func TestPayloadEncryptionInvalidKey(t *testing.T) {
+	if _, err := httpExporter.New("http://localhost:4318", httpExporter.WithPayloadEncryption([]byte("short"))); err == nil {
+		t.Error("New succeeded with a 5 byte key")
+	}
+}
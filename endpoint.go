@@ -0,0 +1,204 @@
+package httpExporter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// endpoint is a collector URL the exporter delivers batches to, along with
+// its delivery accounting.
+type endpoint struct {
+	url    string
+	client *http.Client
+	stream *stream // Set when streaming is enabled
+
+	ownTransport bool // Whether the exporter created the client's transport
+
+	templated bool // Whether url holds placeholders filled per batch
+
+	pending int64 // In-flight deliveries, accessed atomically
+
+	mu                  sync.Mutex
+	batchesSent         uint64
+	batchesFailed       uint64
+	consecutiveFailures uint64
+	lastErr             error
+	lastFailure         time.Time
+	lastSuccess         time.Time
+}
+
+// EndpointStatus is a snapshot of the delivery accounting of a collector
+// endpoint.
+type EndpointStatus struct {
+	URL                 string
+	Pending             int64 // In-flight deliveries
+	BatchesSent         uint64
+	BatchesFailed       uint64
+	ConsecutiveFailures uint64
+	LastError           error
+	LastSuccess         time.Time // Zero if no batch was delivered yet
+}
+
+// WithAdditionalEndpoints configures collector URLs each batch is mirrored
+// to, concurrently with the primary collector. Every endpoint is retried and
+// accounted for independently; failures of additional endpoints are logged
+// but do not fail the export.
+func WithAdditionalEndpoints(urls ...string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.additionalEndpoints = append(append([]string{}, cfg.additionalEndpoints...), urls...)
+		return cfg
+	})
+}
+
+// newEndpoint resolves a collector URL: unix domain socket URLs get a client
+// dialing the socket and URLs without a path get the default path of the
+// encoder.
+func newEndpoint(collectorURL string, cfg config, enc encoder) (*endpoint, error) {
+	if !strings.Contains(collectorURL, "://") && !strings.HasPrefix(collectorURL, "unix:") {
+		// Collectors without a scheme, such as collector:4318, are contacted
+		// over https unless insecure. The scheme of other URLs is kept.
+		scheme := "https"
+		if cfg.insecure {
+			scheme = "http"
+		}
+		collectorURL = scheme + "://" + collectorURL
+	}
+	u, err := url.Parse(collectorURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector URL %q: %v", collectorURL, err)
+	}
+	var socketPath string
+	if u.Scheme == "unix" {
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid collector URL %q: no socket path", collectorURL)
+		}
+		u, socketPath = unixSocketURL(u)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid collector URL %q: no scheme or host", collectorURL)
+	}
+
+	client := cfg.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if socketPath != "" {
+		if client, err = unixSocketClient(client, socketPath); err != nil {
+			return nil, fmt.Errorf("invalid collector URL %q: %v", collectorURL, err)
+		}
+	}
+	// Middlewares wrap the transport dialing the socket.
+	client = withMiddlewares(client, cfg.middlewares)
+	if p := enc.defaultPath(); p != "" && (u.Path == "" || u.Path == "/") {
+		u.Path = p
+	}
+	if cfg.urlPath != "" {
+		u.Path = cfg.urlPath
+	}
+	ep := &endpoint{
+		url:          u.String(),
+		client:       client,
+		ownTransport: cfg.ownTransport || socketPath != "",
+	}
+	// Keep the placeholders of templated URLs readable.
+	if unescaped := strings.NewReplacer("%7B", "{", "%7D", "}").Replace(ep.url); templated(unescaped) {
+		ep.url = unescaped
+		ep.templated = true
+	}
+	return ep, nil
+}
+
+// signalURL returns the URL metrics or logs are posted to for the span
+// endpoint URL rawURL: path joined to its path prefix, which is its path less
+// a trailing OTLP traces path, so that a collector behind a path prefix
+// receives all signals under it.
+func signalURL(rawURL, path string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), otlpTracesPath)
+	u.Path = prefix + "/" + strings.TrimPrefix(path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// record accounts for the outcome of a delivery.
+func (ep *endpoint) record(err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if err != nil {
+		ep.batchesFailed++
+		ep.consecutiveFailures++
+		ep.lastErr = err
+		ep.lastFailure = time.Now()
+		return
+	}
+	ep.batchesSent++
+	ep.consecutiveFailures = 0
+	ep.lastSuccess = time.Now()
+}
+
+func (ep *endpoint) status() EndpointStatus {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return EndpointStatus{
+		URL:                 ep.url,
+		Pending:             atomic.LoadInt64(&ep.pending),
+		BatchesSent:         ep.batchesSent,
+		BatchesFailed:       ep.batchesFailed,
+		ConsecutiveFailures: ep.consecutiveFailures,
+		LastError:           ep.lastErr,
+		LastSuccess:         ep.lastSuccess,
+	}
+}
+
+// newEndpoint resolves a collector URL of the exporter, setting up its
+// stream when streaming is enabled.
+func (e *Exporter) newEndpoint(collectorURL string, cfg config) (*endpoint, error) {
+	ep, err := newEndpoint(collectorURL, cfg, e.encoder)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.streaming {
+		if ep.templated {
+			return nil, fmt.Errorf("invalid collector URL %q: templated URLs cannot be streamed to", collectorURL)
+		}
+		ep.stream = newStream(e, ep)
+	}
+	return ep, nil
+}
+
+// allEndpoints returns the primary collector, followed by any replicas,
+// fallback collectors, mirrors and routed collectors.
+func (e *Exporter) allEndpoints() []*endpoint {
+	eps := e.endpoints[:1:1]
+	if e.pool != nil {
+		eps = append(eps, e.pool.endpoints[1:]...)
+	}
+	if e.failover != nil {
+		eps = append(eps, e.failover.fallbacks...)
+	}
+	eps = append(eps, e.endpoints[1:]...)
+	if e.router != nil {
+		eps = append(eps, e.router.all()...)
+	}
+	return eps
+}
+
+// EndpointStatus returns the delivery accounting of every collector
+// endpoint, starting with the primary collector, followed by any replicas,
+// fallback collectors, mirrors and the collectors spans were routed to.
+func (e *Exporter) EndpointStatus() []EndpointStatus {
+	eps := e.allEndpoints()
+	statuses := make([]EndpointStatus, 0, len(eps))
+	for _, ep := range eps {
+		statuses = append(statuses, ep.status())
+	}
+	return statuses
+}
@@ -0,0 +1,89 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestAdditionalEndpoints(t *testing.T) {
+	primary, first, second := newCollector(t), newCollector(t), newCollector(t)
+	e := newExporter(t, primary.URL(), httpExporter.WithAdditionalEndpoints(first.URL(), second.URL()))
+	if err := e.ExportSpans(context.Background(), newSpans("a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	for name, c := range map[string]*collector{"primary": primary, "first": first, "second": second} {
+		if got := c.names(t); !equal(got, "a", "b") {
+			t.Errorf("%s collector received %v, want [a b]", name, got)
+		}
+	}
+}
+
+func TestAdditionalEndpointFailure(t *testing.T) {
+	primary, mirror := newCollector(t), newCollector(t)
+	mirror.setStatus(http.StatusInternalServerError)
+	e := newExporter(t, primary.URL(), httpExporter.WithAdditionalEndpoints(mirror.URL()))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans failed with a failing mirror: %v", err)
+	}
+	if got := primary.names(t); !equal(got, "a") {
+		t.Errorf("primary collector received %v, want [a]", got)
+	}
+	if got := mirror.count(); got != 1 {
+		t.Errorf("mirror received %d requests, want 1", got)
+	}
+}
+
+func TestAdditionalEndpointsPrimaryFailure(t *testing.T) {
+	primary, mirror := newCollector(t), newCollector(t)
+	primary.setStatus(http.StatusInternalServerError)
+	e := newExporter(t, primary.URL(), httpExporter.WithAdditionalEndpoints(mirror.URL()))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Error("ExportSpans succeeded with a failing primary collector")
+	}
+	if got := mirror.names(t); !equal(got, "a") {
+		t.Errorf("mirror received %v, want [a]", got)
+	}
+}
+
+func TestAdditionalEndpointsRetriedIndependently(t *testing.T) {
+	primary, mirror := newCollector(t), newCollector(t)
+	mirror.respond(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	e := newExporter(t, primary.URL(), httpExporter.WithAdditionalEndpoints(mirror.URL()), fastRetry)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := primary.count(); got != 1 {
+		t.Errorf("primary collector received %d requests, want 1", got)
+	}
+	if got := mirror.count(); got != 3 {
+		t.Errorf("mirror received %d requests, want 3", got)
+	}
+	if got := mirror.names(t); !equal(got, "a") {
+		t.Errorf("mirror received %v, want [a]", got)
+	}
+}
+
+func TestEndpointStatus(t *testing.T) {
+	primary, mirror := newCollector(t), newCollector(t)
+	mirror.setStatus(http.StatusInternalServerError)
+	e := newExporter(t, primary.URL(), httpExporter.WithAdditionalEndpoints(mirror.URL()))
+	for i := 0; i < 2; i++ {
+		if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+
+	status := e.EndpointStatus()
+	if len(status) != 2 {
+		t.Fatalf("got the status of %d endpoints, want 2", len(status))
+	}
+	if s := status[0]; s.BatchesSent != 2 || s.BatchesFailed != 0 || s.LastError != nil || s.LastSuccess.IsZero() {
+		t.Errorf("primary status = %+v", s)
+	}
+	if s := status[1]; s.BatchesSent != 0 || s.BatchesFailed != 2 || s.ConsecutiveFailures != 2 || s.LastError == nil {
+		t.Errorf("mirror status = %+v", s)
+	}
+}
@@ -1,13 +1,85 @@
 package httpExporter
 
-import "os"
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Environment variable names.
 const (
 	// Http endpoint
 	envEndpoint = "OTEL_EXPORTER_HTTP_ENDPOINT"
+	// Export request timeout in milliseconds
+	envTimeout = "OTEL_EXPORTER_HTTP_TIMEOUT"
+	// Request headers as comma separated key=value pairs with URL encoded
+	// values
+	envHeaders = "OTEL_EXPORTER_HTTP_HEADERS"
+	// Request body compression, gzip or none
+	envCompression = "OTEL_EXPORTER_HTTP_COMPRESSION"
+	// Path of a PEM file of certificate authorities to trust
+	envCertificate = "OTEL_EXPORTER_HTTP_CERTIFICATE"
+	// Whether to send requests without transport security
+	envInsecure = "OTEL_EXPORTER_HTTP_INSECURE"
+	// Tenant spans are pushed to with WithTempo
+	envTempoTenant = "OTEL_EXPORTER_HTTP_TEMPO_TENANT"
 )
 
+// applyEnv seeds a configuration from the environment variables. Options
+// applied afterwards take precedence.
+func applyEnv(cfg config) config {
+	cfg.timeout = envDurationOr(envTimeout, defaultTimeout)
+	if v := envOr(envTimeout, ""); v != "" {
+		if ms, err := strconv.Atoi(v); err != nil || ms < 0 {
+			cfg.envProblems = append(cfg.envProblems, fmt.Sprintf("%s: invalid timeout %q", envTimeout, v))
+		}
+	}
+	if v := envOr(envHeaders, ""); v != "" {
+		cfg.headers = parseEnvHeaders(v)
+	}
+	switch v := envOr(envCompression, ""); v {
+	case "", NoCompression.String():
+	case GzipCompression.String():
+		cfg.compression = GzipCompression
+		cfg.envCompressed = true
+	default:
+		cfg.envProblems = append(cfg.envProblems, fmt.Sprintf("%s: unknown compression %q", envCompression, v))
+	}
+	cfg.certificateFile = envOr(envCertificate, "")
+	if v := envOr(envInsecure, ""); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			cfg.envProblems = append(cfg.envProblems, fmt.Sprintf("%s: invalid boolean %q", envInsecure, v))
+		}
+		cfg.insecure = insecure
+		cfg.envInsecure = insecure
+	}
+	cfg.tempoTenant = envOr(envTempoTenant, "")
+	return cfg
+}
+
+// parseEnvHeaders parses headers formatted as comma separated key=value
+// pairs with URL encoded values, skipping invalid pairs.
+func parseEnvHeaders(v string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(v, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		value, err := url.QueryUnescape(strings.TrimSpace(kv[1]))
+		if key == "" || err != nil {
+			continue
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
 // envOr returns an env variable's value if it is exists or the default if not.
 func envOr(key, defaultValue string) string {
 	if v, ok := os.LookupEnv(key); ok && v != "" {
@@ -15,3 +87,14 @@ func envOr(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// envDurationOr returns an env variable's value, as a number of
+// milliseconds, if it exists and is valid or the default if not.
+func envDurationOr(key string, defaultValue time.Duration) time.Duration {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		if ms, err := strconv.Atoi(v); err == nil && ms >= 0 {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+	return defaultValue
+}
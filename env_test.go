@@ -0,0 +1,113 @@
+package httpExporter_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestHeadersFromEnvironment(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_HEADERS", "X-Tenant=shop, Authorization=Basic%20dXNlcg%3D%3D,invalid")
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithHeaders(map[string]string{"X-Tenant": "override"}))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	h := c.received()[0].Header
+	if got := h.Get("Authorization"); got != "Basic dXNlcg==" {
+		t.Errorf("Authorization = %q, want the URL decoded value", got)
+	}
+	if got := h.Get("X-Tenant"); got != "override" {
+		t.Errorf("X-Tenant = %q, want WithHeaders to take precedence", got)
+	}
+}
+
+func TestCompressionFromEnvironment(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_COMPRESSION", "gzip")
+	c := newCollector(t)
+	e := newExporter(t, c.URL())
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.received()[0].Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestCertificateFromEnvironment(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// Without the certificate, the collector is not trusted.
+	e := newExporter(t, srv.URL)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded with an untrusted certificate")
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(path, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OTEL_EXPORTER_HTTP_CERTIFICATE", path)
+	e = newExporter(t, srv.URL)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Errorf("ExportSpans with the certificate from the environment: %v", err)
+	}
+}
+
+func TestInsecureFromEnvironment(t *testing.T) {
+	c := newCollector(t)
+	hostPort := strings.TrimPrefix(c.URL(), "http://")
+
+	// Collectors without a scheme are contacted over https by default.
+	e := newExporter(t, hostPort)
+	if err := e.ExportSpans(context.Background(), newSpans("tls")); err == nil {
+		t.Fatal("ExportSpans succeeded over https to a plain http collector")
+	}
+
+	t.Setenv("OTEL_EXPORTER_HTTP_INSECURE", "true")
+	e = newExporter(t, hostPort)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestInsecureKeepsHTTPS(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_INSECURE", "true")
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	// WithTLSClientConfig overrides the environment rather than conflicting
+	// with it, and the https URL is not downgraded.
+	e := newExporter(t, srv.URL, httpExporter.WithTLSClientConfig(&tls.Config{RootCAs: roots}))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Errorf("ExportSpans over https: %v", err)
+	}
+	if got := e.EffectiveConfig().URL; !strings.HasPrefix(got, "https://") {
+		t.Errorf("collector URL = %s, want https", got)
+	}
+
+	// WithInsecure and WithTLSClientConfig do conflict.
+	_, err := httpExporter.New(srv.URL, httpExporter.WithInsecure(), httpExporter.WithTLSClientConfig(&tls.Config{}))
+	if err == nil {
+		t.Error("New succeeded with WithInsecure and WithTLSClientConfig")
+	}
+}
@@ -0,0 +1,52 @@
+package httpExporter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxResponseBodySize bounds the part of response bodies kept, for
+// ErrPayloadRejected and partial success responses.
+const maxResponseBodySize = 4 << 10
+
+var (
+	// ErrCollectorUnavailable is matched, with errors.Is, by errors of
+	// exports that failed transiently: the collector could not be reached,
+	// answered with 429, 502, 503 or 504, or the circuit breaker is open.
+	ErrCollectorUnavailable = errors.New("collector unavailable")
+	// ErrShutdown is returned when exporting spans after Shutdown. The
+	// metric and log exporters return sdkmetric.ErrExporterShutdown.
+	ErrShutdown = errors.New("exporter is shutdown")
+)
+
+// ErrPayloadRejected is returned, wrapped, when the collector rejects a
+// batch with a non-2xx response that is not transient.
+type ErrPayloadRejected struct {
+	Status int    // Response status code
+	Body   []byte // Response body, truncated to 4 KiB
+}
+
+func (e *ErrPayloadRejected) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("collector rejected payload with status %d", e.Status)
+	}
+	return fmt.Sprintf("collector rejected payload with status %d: %s", e.Status, e.Body)
+}
+
+// ErrInvalidConfig is returned when creating an exporter with invalid or
+// conflicting options or environment variables. It lists every problem
+// found.
+type ErrInvalidConfig struct {
+	Problems []string
+}
+
+func (e *ErrInvalidConfig) Error() string {
+	return "invalid exporter configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Retryable reports whether err is a transient export failure, which may
+// succeed if the batch is sent again later.
+func Retryable(err error) bool {
+	return errors.Is(err, ErrCollectorUnavailable)
+}
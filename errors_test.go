@@ -0,0 +1,107 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestErrCollectorUnavailable(t *testing.T) {
+	for _, status := range []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	} {
+		c := newCollector(t)
+		c.setStatus(status)
+		e := newExporter(t, c.URL())
+		err := e.ExportSpans(context.Background(), newSpans("a"))
+		if !errors.Is(err, httpExporter.ErrCollectorUnavailable) || !httpExporter.Retryable(err) {
+			t.Errorf("ExportSpans with a %d response = %v, want ErrCollectorUnavailable", status, err)
+		}
+	}
+}
+
+func TestErrCollectorUnreachable(t *testing.T) {
+	c := newCollector(t)
+	url := c.URL()
+	c.server.Close()
+	e := newExporter(t, url)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); !httpExporter.Retryable(err) {
+		t.Errorf("ExportSpans to an unreachable collector = %v, want a retryable error", err)
+	}
+}
+
+func TestErrPayloadRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid span name", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	e := newExporter(t, srv.URL)
+
+	err := e.ExportSpans(context.Background(), newSpans("a"))
+	var rerr *httpExporter.ErrPayloadRejected
+	if !errors.As(err, &rerr) {
+		t.Fatalf("ExportSpans = %v, want ErrPayloadRejected", err)
+	}
+	if rerr.Status != http.StatusBadRequest || string(rerr.Body) != "invalid span name\n" {
+		t.Errorf("ErrPayloadRejected = %+v", rerr)
+	}
+	if httpExporter.Retryable(err) {
+		t.Error("a rejected payload is retryable")
+	}
+}
+
+func TestErrPayloadRejectedTruncatesBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(make([]byte, 1<<20))
+	}))
+	defer srv.Close()
+	e := newExporter(t, srv.URL)
+
+	var rerr *httpExporter.ErrPayloadRejected
+	if err := e.ExportSpans(context.Background(), newSpans("a")); !errors.As(err, &rerr) {
+		t.Fatalf("ExportSpans = %v, want ErrPayloadRejected", err)
+	}
+	if len(rerr.Body) != 4<<10 {
+		t.Errorf("kept %d bytes of the response body, want 4 KiB", len(rerr.Body))
+	}
+}
+
+func TestErrCircuitOpenRetryable(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithCircuitBreaker(1, time.Hour))
+	_ = e.ExportSpans(context.Background(), newSpans("a"))
+	if err := e.ExportSpans(context.Background(), newSpans("b")); !errors.Is(err, httpExporter.ErrCollectorUnavailable) {
+		t.Errorf("ExportSpans with an open circuit = %v, want ErrCollectorUnavailable", err)
+	}
+}
+
+func TestErrSerializationWrapped(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL())
+	spans := tracetest.SpanStubs{{
+		Name:        "a",
+		SpanContext: newSpanContext(newTraceID()),
+		StartTime:   time.Now(),
+		EndTime:     time.Now(),
+		Attributes:  []attribute.KeyValue{attribute.Float64("ratio", math.NaN())},
+	}}.Snapshots()
+	err := e.ExportSpans(context.Background(), spans)
+	var unsupported *json.UnsupportedValueError
+	if !errors.As(err, &unsupported) {
+		t.Errorf("ExportSpans with a NaN attribute = %v, want a wrapped *json.UnsupportedValueError", err)
+	}
+}
@@ -0,0 +1,271 @@
+package httpExporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// lokiPushPath is the Loki push API path.
+const lokiPushPath = "/loki/api/v1/push"
+
+// eventLogsTimeout bounds the delivery of the span events of a batch,
+// retries included.
+const eventLogsTimeout = 30 * time.Second
+
+// EventLogFormat selects how span events are sent as log records.
+type EventLogFormat int
+
+const (
+	// EventLogJSON sends span events as a JSON array of LogData, as
+	// LogExporter does. This is the default.
+	EventLogJSON EventLogFormat = iota
+	// EventLogLoki sends span events to the Loki push API, in a stream per
+	// service.
+	EventLogLoki
+)
+
+// String returns the name of the event log format.
+func (f EventLogFormat) String() string {
+	switch f {
+	case EventLogJSON:
+		return "json"
+	case EventLogLoki:
+		return "loki"
+	}
+	return "unknown"
+}
+
+// WithSpanEventLogs configures the exporter to also send the events of the
+// exported spans as log records to logsURL, carrying the trace and span IDs
+// of their span, so they can be searched in a log store. Exception events get
+// the ERROR severity, others INFO. If logsURL has no path, /v1/logs is used
+// for EventLogJSON and /loki/api/v1/push for EventLogLoki. Events are sent
+// after the attribute transforms, in the background with the headers and
+// retries of the exporter, and given up after eventLogsTimeout; failing to
+// send them does not delay or fail the export.
+func WithSpanEventLogs(logsURL string, format EventLogFormat) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.eventLogsURL = logsURL
+		cfg.eventLogFormat = format
+		return cfg
+	})
+}
+
+// newEventLogEndpoint returns the endpoint span events are sent to.
+func (e *Exporter) newEventLogEndpoint(cfg config) (*endpoint, error) {
+	u, err := url.Parse(cfg.eventLogsURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid span event logs URL %q", cfg.eventLogsURL)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultLogsPath
+		if cfg.eventLogFormat == EventLogLoki {
+			u.Path = lokiPushPath
+		}
+	}
+	return &endpoint{url: u.String(), client: e.endpoints[0].client}, nil
+}
+
+// exportEventLogs sends the events of spans as log records, logging
+// failures.
+func (e *Exporter) exportEventLogs(ctx context.Context, spans []sdktrace.ReadOnlySpan) {
+	var body []byte
+	var err error
+	switch e.eventLogFormat {
+	case EventLogLoki:
+		body, err = encodeLokiEvents(spans)
+	default:
+		body, err = encodeJSONEvents(spans)
+	}
+	if err != nil {
+		e.warnf("unable to serialize span events: %v", err)
+		return
+	}
+	if body == nil {
+		return
+	}
+	// The events are sent in the background so that a slow logs collector
+	// does not hold up the spans, and outlive ctx, which is done once the
+	// export returns. Shutdown and ForceFlush wait for them.
+	e.eventLogsPending.add(1)
+	go func() {
+		defer e.eventLogsPending.add(-1)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventLogsTimeout)
+		defer cancel()
+		if err := e.deliver(ctx, e.eventLogs, "application/json", body); err != nil {
+			e.warnf("failed to send span events to %s: %v", e.eventLogs.url, err)
+		}
+	}()
+}
+
+// eventSeverity returns the severity of a span event.
+func eventSeverity(ev sdktrace.Event) log.Severity {
+	if ev.Name == semconv.ExceptionEventName {
+		return log.SeverityError
+	}
+	return log.SeverityInfo
+}
+
+// encodeJSONEvents encodes the events of spans as a JSON array of LogData,
+// or returns nil if there are none.
+func encodeJSONEvents(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var logs []LogData
+	for _, span := range spans {
+		sc := span.SpanContext()
+		for _, ev := range span.Events() {
+			severity := eventSeverity(ev)
+			l := LogData{
+				Timestamp:                     unixNano(ev.Time),
+				ObservedTimestamp:             unixNano(ev.Time),
+				SeverityNumber:                int(severity),
+				SeverityText:                  severity.String(),
+				Body:                          ev.Name,
+				Attrs:                         make(LogAttributes, 0, len(ev.Attributes)+1),
+				DroppedAttributeCount:         ev.DroppedAttributeCount,
+				TraceID:                       sc.TraceID().String(),
+				SpanID:                        sc.SpanID().String(),
+				InstrumentationLibraryName:    span.InstrumentationLibrary().Name,
+				InstrumentationLibraryVersion: span.InstrumentationLibrary().Version,
+				Resource:                      Attributes(span.Resource().Attributes()),
+			}
+			for _, kv := range ev.Attributes {
+				l.Attrs = append(l.Attrs, logKeyValue(kv))
+			}
+			l.Attrs = append(l.Attrs, log.String("span.name", span.Name()))
+			logs = append(logs, l)
+		}
+	}
+	if logs == nil {
+		return nil, nil
+	}
+	return json.Marshal(logs)
+}
+
+// logKeyValue converts a span event attribute to a log record attribute.
+func logKeyValue(kv attribute.KeyValue) log.KeyValue {
+	key := string(kv.Key)
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		return log.Bool(key, kv.Value.AsBool())
+	case attribute.INT64:
+		return log.Int64(key, kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		return log.Float64(key, kv.Value.AsFloat64())
+	case attribute.STRING:
+		return log.String(key, kv.Value.AsString())
+	case attribute.BOOLSLICE:
+		s := kv.Value.AsBoolSlice()
+		values := make([]log.Value, len(s))
+		for i, x := range s {
+			values[i] = log.BoolValue(x)
+		}
+		return log.Slice(key, values...)
+	case attribute.INT64SLICE:
+		s := kv.Value.AsInt64Slice()
+		values := make([]log.Value, len(s))
+		for i, x := range s {
+			values[i] = log.Int64Value(x)
+		}
+		return log.Slice(key, values...)
+	case attribute.FLOAT64SLICE:
+		s := kv.Value.AsFloat64Slice()
+		values := make([]log.Value, len(s))
+		for i, x := range s {
+			values[i] = log.Float64Value(x)
+		}
+		return log.Slice(key, values...)
+	case attribute.STRINGSLICE:
+		s := kv.Value.AsStringSlice()
+		values := make([]log.Value, len(s))
+		for i, x := range s {
+			values[i] = log.StringValue(x)
+		}
+		return log.Slice(key, values...)
+	}
+	return log.KeyValue{Key: key}
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"` // Labels
+	Values [][2]string       `json:"values"` // Nanosecond timestamps and lines
+}
+
+// lokiEntry is a span event logged to a Loki stream.
+type lokiEntry struct {
+	time int64
+	line string
+}
+
+// encodeLokiEvents encodes the events of spans as a Loki push request, or
+// returns nil if there are none. Streams are labeled with the service name
+// and the level; lines are JSON objects holding the event name and
+// attributes and the trace and span IDs.
+func encodeLokiEvents(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	type streamKey struct {
+		service, level string
+	}
+	var keys []streamKey
+	entries := make(map[streamKey][]lokiEntry)
+	for _, span := range spans {
+		sc := span.SpanContext()
+		service := serviceName(span.Resource())
+		for _, ev := range span.Events() {
+			fields := make(map[string]interface{}, len(ev.Attributes)+4)
+			for _, kv := range ev.Attributes {
+				fields[string(kv.Key)] = kv.Value.AsInterface()
+			}
+			fields["event"] = ev.Name
+			fields["span_name"] = span.Name()
+			fields["trace_id"] = sc.TraceID().String()
+			fields["span_id"] = sc.SpanID().String()
+			line, err := json.Marshal(fields)
+			if err != nil {
+				return nil, err
+			}
+			key := streamKey{service: service, level: lokiLevel(eventSeverity(ev))}
+			if _, ok := entries[key]; !ok {
+				keys = append(keys, key)
+			}
+			entries[key] = append(entries[key], lokiEntry{time: unixNano(ev.Time), line: string(line)})
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	req := lokiPushRequest{Streams: make([]lokiStream, 0, len(keys))}
+	for _, key := range keys {
+		stream := lokiStream{Stream: map[string]string{"level": key.level}}
+		if key.service != "" {
+			stream.Stream["service_name"] = key.service
+		}
+		es := entries[key]
+		sort.SliceStable(es, func(i, j int) bool { return es[i].time < es[j].time })
+		for _, entry := range es {
+			stream.Values = append(stream.Values, [2]string{strconv.FormatInt(entry.time, 10), entry.line})
+		}
+		req.Streams = append(req.Streams, stream)
+	}
+	return json.Marshal(req)
+}
+
+// lokiLevel returns the Loki level label of a severity.
+func lokiLevel(severity log.Severity) string {
+	if severity >= log.SeverityError {
+		return "error"
+	}
+	return "info"
+}
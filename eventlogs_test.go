@@ -0,0 +1,148 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newSpanWithEvents returns an ended span of the checkout service with an
+// exception event and a retry event.
+func newSpanWithEvents() []sdktrace.ReadOnlySpan {
+	now := time.Now()
+	return tracetest.SpanStubs{{
+		Name:        "GET /cart",
+		SpanContext: newSpanContext(newTraceID()),
+		StartTime:   now,
+		EndTime:     now,
+		Events: []sdktrace.Event{
+			{Name: "exception", Time: now, Attributes: []attribute.KeyValue{attribute.String("exception.message", "connection reset")}},
+			{Name: "retry", Time: now.Add(time.Millisecond)},
+		},
+		Resource: resource.NewSchemaless(attribute.String("service.name", "checkout")),
+	}}.Snapshots()
+}
+
+// requestsTo returns the requests received by c on path.
+func (c *collector) requestsTo(path string) []request {
+	var reqs []request
+	for _, r := range c.received() {
+		if r.Path == path {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs
+}
+
+func TestSpanEventLogs(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL()+"/v1/traces", httpExporter.WithSpanEventLogs(c.URL(), httpExporter.EventLogJSON))
+	spans := newSpanWithEvents()
+	if err := e.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the span events", func() bool { return len(c.requestsTo("/v1/logs")) == 1 })
+
+	var logs []struct {
+		Body         string                 `json:"body"`
+		SeverityText string                 `json:"severityText"`
+		TraceID      string                 `json:"traceId"`
+		SpanID       string                 `json:"spanId"`
+		Attrs        map[string]interface{} `json:"attrs"`
+	}
+	if err := json.Unmarshal(c.requestsTo("/v1/logs")[0].Body, &logs); err != nil {
+		t.Fatalf("invalid logs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("got %d log records, want one per event", len(logs))
+	}
+	sc := spans[0].SpanContext()
+	for _, l := range logs {
+		if l.TraceID != sc.TraceID().String() || l.SpanID != sc.SpanID().String() || l.Attrs["span.name"] != "GET /cart" {
+			t.Errorf("log record %+v is not correlated with its span", l)
+		}
+	}
+	if logs[0].Body != "exception" || logs[0].SeverityText != "ERROR" || logs[0].Attrs["exception.message"] != "connection reset" {
+		t.Errorf("exception record = %+v", logs[0])
+	}
+	if logs[1].Body != "retry" || logs[1].SeverityText != "INFO" {
+		t.Errorf("retry record = %+v", logs[1])
+	}
+	if got := len(c.requestsTo("/v1/traces")); got != 1 {
+		t.Errorf("collector received %d span requests, want 1", got)
+	}
+}
+
+func TestSpanEventLogsLoki(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL()+"/v1/traces", httpExporter.WithSpanEventLogs(c.URL(), httpExporter.EventLogLoki))
+	if err := e.ExportSpans(context.Background(), newSpanWithEvents()); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the span events", func() bool { return len(c.requestsTo("/loki/api/v1/push")) == 1 })
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(c.requestsTo("/loki/api/v1/push")[0].Body, &push); err != nil {
+		t.Fatalf("invalid push request: %v", err)
+	}
+	if len(push.Streams) != 2 {
+		t.Fatalf("got %d streams, want one per level", len(push.Streams))
+	}
+	for _, s := range push.Streams {
+		if s.Stream["service_name"] != "checkout" || len(s.Values) != 1 {
+			t.Errorf("stream %v holds %d entries", s.Stream, len(s.Values))
+		}
+	}
+	if push.Streams[0].Stream["level"] != "error" || push.Streams[1].Stream["level"] != "info" {
+		t.Errorf("stream levels %v, %v", push.Streams[0].Stream, push.Streams[1].Stream)
+	}
+}
+
+func TestSpanEventLogsFailure(t *testing.T) {
+	c := newCollector(t)
+	logs := newCollector(t)
+	logs.setStatus(http.StatusInternalServerError)
+	e := newExporter(t, c.URL(), httpExporter.WithSpanEventLogs(logs.URL(), httpExporter.EventLogJSON))
+	if err := e.ExportSpans(context.Background(), newSpanWithEvents()); err != nil {
+		t.Fatalf("ExportSpans failed with the span events: %v", err)
+	}
+	if got := c.names(t); !equal(got, "GET /cart") {
+		t.Errorf("collector received %v, want the span", got)
+	}
+}
+
+func TestSpanEventLogsSlowCollector(t *testing.T) {
+	c := newCollector(t)
+	logs := newCollector(t)
+	logs.setLatency(500 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithSpanEventLogs(logs.URL(), httpExporter.EventLogJSON))
+	start := time.Now()
+	if err := e.ExportSpans(context.Background(), newSpanWithEvents()); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("ExportSpans took %v, waiting for the span events", elapsed)
+	}
+	if got := c.names(t); !equal(got, "GET /cart") {
+		t.Errorf("collector received %v, want the span", got)
+	}
+	if err := e.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := len(logs.requestsTo("/v1/logs")); got != 1 {
+		t.Errorf("ForceFlush returned with %d span event requests delivered, want 1", got)
+	}
+}
@@ -2,42 +2,193 @@ package httpExporter
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
-	"net/url"
+	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"bytes"
 	"io"
 	"io/ioutil"
+	"time"
 
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 const (
-	defaultURL = "http://localhost:4000/"
+	defaultURL     = "http://localhost:4000/"
+	defaultTimeout = 10 * time.Second
 )
 
 // Exporter implements the SpanExporter interface that allows us to export span data
 type Exporter struct {
 	url         string
 	serviceName string
-	client      *http.Client
 	logger      *log.Logger
+	encoder     encoder
+	headers     map[string]string
+	retry       RetryConfig
+	timeout     time.Duration
+	compression Compression
+	userAgent   string
+	endpoints   []*endpoint      // The primary collector followed by any mirrors
+	pool        *pool            // Set when replicas of the primary collector are configured
+	failover    *failover        // Set when fallback collectors are configured
+	queue       *queue           // Set when batches are sent asynchronously
+	wal         *wal             // Set when undelivered batches are persisted
+	breaker     *breaker         // Set when a circuit breaker is configured
+	limiter     *limiter         // Set when a rate limit is configured
+	adaptive    *adaptiveBatcher // Set when adaptive batching is configured
+	tailSampler *tailSampler     // Set when tail sampling is configured
+	dedup       *dedupCache      // Set when deduplication is configured
+	router      *router          // Set when spans are routed by resource
+	refs        *resourceRefs    // Set when resources are sent once per session
+	eventLogs   *endpoint        // Set when span events are sent as log records
+	telemetry   *telemetry       // Set when a meter provider is configured
+	stats       stats
+	effective   EffectiveConfig
 
-	stoppedMu sync.RWMutex
-	stopped   bool
+	eventLogFormat EventLogFormat
+
+	onExportSuccess      func(ExportInfo)
+	onExportError        func(ExportInfo)
+	partialSuccessParser PartialSuccessParser
+	slogLogger           *slog.Logger
+	logrLogger           logr.Logger
+	dumper               *dumper    // Set when debug dumps are enabled
+	encrypter            *encrypter // Set when payload encryption is configured
+	sink                 sink       // Set when requests are written instead of sent
+	spanFilters          []func(sdktrace.ReadOnlySpan) bool
+	attributeTransforms  []attributeTransform
+	limits               Limits
+
+	backpressure    BackpressurePolicy
+	deterministic   bool
+	templated       bool // Whether a collector URL holds placeholders
+	idempotencyKeys bool
+	maxPayloadBytes int
+	exportSlots     chan struct{} // Bounds concurrent exports when set
+
+	stoppedMu        sync.RWMutex
+	stopped          bool
+	inflight         pendingGroup // ExportSpans calls in progress
+	eventLogsPending pendingGroup // Span event deliveries in progress
 }
 
 var (
 	_ sdktrace.SpanExporter = &Exporter{}
 )
 
+
 // Options contains configuration for the exporter.
 type config struct {
-	client *http.Client
+	client       *http.Client
+	ownTransport bool   // Whether client's transport was created by newClient
+	collectorURL string // Primary collector, resolved by newDeliveryExporter
 	logger *log.Logger
+	format    Format
+	headers   map[string]string
+	streaming bool
+	retry     RetryConfig
+	timeout   time.Duration
+
+	compression     Compression
+	envCompressed   bool // Compression set by the environment rather than an option
+	encryptionKey   []byte
+	tlsConfig       *tls.Config
+	certificateFile string
+	insecure        bool
+	envInsecure     bool // Insecure set by the environment rather than an option
+	proxyURL        string
+	transport       TransportConfig
+	middlewares     []func(http.RoundTripper) http.RoundTripper
+	userAgent       string
+	idempotencyKeys bool
+	startupCheck    context.Context
+
+	adaptiveBatching *AdaptiveBatchingConfig
+	tailSampling     *TailSamplingConfig
+	dedupSize        int
+
+	additionalEndpoints []string
+	fallbackEndpoints   []string
+	poolEndpoints       []string
+	balancingPolicy     BalancingPolicy
+
+	queueCapacity int
+	queuePolicy   QueuePolicy
+	backpressure  BackpressurePolicy
+
+	persistence PersistenceConfig
+
+	breakerFailures int
+	breakerCooldown time.Duration
+
+	failoverThreshold     int
+	failoverProbeInterval time.Duration
+
+	rateLimit  float64
+	rateBurst  int
+	ratePolicy LimitPolicy
+
+	maxPayloadBytes      int
+	maxConcurrentExports int
+
+	meterProvider metric.MeterProvider
+
+	onExportSuccess      func(ExportInfo)
+	onExportError        func(ExportInfo)
+	partialSuccessParser PartialSuccessParser
+	slogLogger           *slog.Logger
+	logrLogger           logr.Logger
+	debugDumpDir         string
+	sink                 sink
+	spanFilters          []func(sdktrace.ReadOnlySpan) bool
+	attributeTransforms  []attributeTransform
+	limits               Limits
+
+	idEncoding      IDEncoding
+	omitEmptyParent bool
+	payloadLayout   PayloadLayout
+	serviceName     string
+	urlPath         string
+	deterministic   bool
+	payloadVersion  PayloadVersion
+
+	elasticsearchIndex string
+	honeycombDataset   string
+	cloudTraceProject  string
+	instrumentationKey string
+	kafkaTopic         string
+
+	metricsPath         string
+	temporalitySelector sdkmetric.TemporalitySelector
+
+	logsPath string
+
+	signal string // Signal of a metric or log exporter, empty for spans
+
+	envProblems []string // Invalid environment variables
+
+	pipeline PipelineConfig
+
+	router func(*resource.Resource, instrumentation.Scope) string
+
+	resourceReferences bool
+
+	eventLogsURL   string
+	eventLogFormat EventLogFormat
+
+	tempo       bool
+	tempoTenant string
 }
 
 // Option defines a function that configures the exporter.
@@ -51,7 +202,8 @@ func (fn optionFunc) apply(cfg config) config {
 	return fn(cfg)
 }
 
-// WithLogger configures the exporter to use the passed logger.
+// WithLogger configures the exporter to use the passed logger. It prints
+// warnings and errors, as well as request bodies when WithDebugDump is set.
 func WithLogger(logger *log.Logger) Option {
 	return optionFunc(func(cfg config) config {
 		cfg.logger = logger
@@ -67,119 +219,791 @@ func WithClient(client *http.Client) Option {
 	})
 }
 
+// WithMaxPayloadBytes limits the size of request bodies. Batches encoding to
+// more than n bytes are split into several requests. Independently of this
+// option, a batch the collector rejects with 413 Payload Too Large is split
+// in halves which are sent separately.
+func WithMaxPayloadBytes(n int) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.maxPayloadBytes = n
+		return cfg
+	})
+}
+
+// WithTimeout bounds each request to the collector to d, independently of
+// the context passed to ExportSpans. It defaults to the number of
+// milliseconds in the OTEL_EXPORTER_HTTP_TIMEOUT environment variable, or
+// 10 seconds. A zero d disables the timeout.
+func WithTimeout(d time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.timeout = d
+		return cfg
+	})
+}
+
+// WithMaxConcurrentExports limits the number of batches sent concurrently to
+// n, bounding memory use when ExportSpans is called concurrently. With
+// WithQueue, n background senders send queued batches in parallel, so that
+// throughput is not bound by the round-trip latency to the collector.
+func WithMaxConcurrentExports(n int) Option {
+	return optionFunc(func(cfg config) config {
+		if n < 1 {
+			n = 1
+		}
+		cfg.maxConcurrentExports = n
+		return cfg
+	})
+}
+
+// WithHeaders configures additional HTTP headers sent with every export
+// request. It may be used multiple times; later values win.
+func WithHeaders(headers map[string]string) Option {
+	return optionFunc(func(cfg config) config {
+		merged := make(map[string]string, len(cfg.headers)+len(headers))
+		for k, v := range cfg.headers {
+			merged[k] = v
+		}
+		for k, v := range headers {
+			merged[k] = v
+		}
+		cfg.headers = merged
+		return cfg
+	})
+}
+
 func New(collectorURL string, opts ...Option) (*Exporter, error) {
-	if collectorURL == "" {
-		// Use endpoint from env var or default collector URL.
-		collectorURL = envOr(envEndpoint, defaultURL)
+	return newExporter(collectorURL, newConfig(opts...))
+}
+
+// newConfig applies opts to an empty configuration.
+func newConfig(opts ...Option) config {
+	cfg := applyEnv(config{})
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
 	}
-	u, err := url.Parse(collectorURL)
+	return cfg
+}
+
+// newExporter creates an exporter for the collector at collectorURL from a
+// resolved configuration.
+func newExporter(collectorURL string, cfg config) (*Exporter, error) {
+	e, cfg, err := newDeliveryExporter(collectorURL, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("invalid collector URL %q: %v", collectorURL, err)
+		return nil, err
+	}
+	e.serviceName = cfg.serviceName
+	e.backpressure = cfg.backpressure
+	e.deterministic = cfg.deterministic
+	e.maxPayloadBytes = cfg.maxPayloadBytes
+	e.onExportSuccess = cfg.onExportSuccess
+	e.onExportError = cfg.onExportError
+	e.partialSuccessParser = cfg.partialSuccessParser
+	e.spanFilters = cfg.spanFilters
+	e.attributeTransforms = cfg.attributeTransforms
+	e.limits = cfg.limits
+	if je, ok := e.encoder.(*jsonEncoder); ok && cfg.resourceReferences {
+		e.refs = newResourceRefs()
+		je.conv.refs = e.refs
+		headers := map[string]string{resourceSessionHeader: e.refs.session}
+		for k, v := range e.headers {
+			headers[k] = v
+		}
+		e.headers = headers
+	}
+	if pl, ok := e.encoder.(payloadLimitEncoder); ok && e.maxPayloadBytes == 0 {
+		e.maxPayloadBytes = pl.maxPayloadBytes()
+	}
+	if e.partialSuccessParser == nil {
+		e.partialSuccessParser = parsePartialSuccess
+	}
+	for _, rawURL := range append([]string{cfg.collectorURL}, cfg.additionalEndpoints...) {
+		ep, err := e.newEndpoint(rawURL, cfg)
+		if err != nil {
+			return nil, err
+		}
+		e.endpoints = append(e.endpoints, ep)
+	}
+	if len(cfg.poolEndpoints) > 0 {
+		e.pool = &pool{endpoints: []*endpoint{e.endpoints[0]}, policy: cfg.balancingPolicy}
+		for _, rawURL := range cfg.poolEndpoints {
+			ep, err := e.newEndpoint(rawURL, cfg)
+			if err != nil {
+				return nil, err
+			}
+			e.pool.endpoints = append(e.pool.endpoints, ep)
+		}
+	}
+	if len(cfg.fallbackEndpoints) > 0 {
+		var fallbacks []*endpoint
+		for _, rawURL := range cfg.fallbackEndpoints {
+			ep, err := e.newEndpoint(rawURL, cfg)
+			if err != nil {
+				return nil, err
+			}
+			fallbacks = append(fallbacks, ep)
+		}
+		e.failover = newFailover(fallbacks, cfg.failoverThreshold, cfg.failoverProbeInterval)
+	}
+	e.url = e.endpoints[0].url
+	if cfg.router != nil {
+		e.router = newRouter(cfg.router, func(collectorURL string) (*endpoint, error) {
+			return e.newEndpoint(collectorURL, cfg)
+		})
+	}
+	if cfg.eventLogsURL != "" {
+		ep, err := e.newEventLogEndpoint(cfg)
+		if err != nil {
+			return nil, err
+		}
+		e.eventLogs = ep
+		e.eventLogFormat = cfg.eventLogFormat
+	}
+	for _, ep := range e.allEndpoints() {
+		e.templated = e.templated || ep.templated
+	}
+	if cfg.meterProvider != nil {
+		t, err := newTelemetry(cfg.meterProvider)
+		if err != nil {
+			return nil, err
+		}
+		e.telemetry = t
+	}
+	if cfg.maxConcurrentExports > 0 {
+		e.exportSlots = make(chan struct{}, cfg.maxConcurrentExports)
+	}
+	if cfg.rateLimit > 0 {
+		e.limiter = newLimiter(cfg.rateLimit, cfg.rateBurst, cfg.ratePolicy)
+	}
+	if cfg.adaptiveBatching != nil {
+		e.adaptive = newAdaptiveBatcher(*cfg.adaptiveBatching)
+	}
+	if cfg.dedupSize > 0 {
+		e.dedup = newDedupCache(cfg.dedupSize)
+	}
+	if cfg.breakerFailures > 0 {
+		e.breaker = &breaker{threshold: cfg.breakerFailures, cooldown: cfg.breakerCooldown}
+	}
+	if cfg.startupCheck != nil && e.sink == nil {
+		if err := e.startupCheck(cfg.startupCheck); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.persistence.Dir != "" {
+		w, err := newWAL(cfg.persistence)
+		if err != nil {
+			return nil, err
+		}
+		e.wal = w
+		w.start(e)
 	}
-	if u.Scheme == "" || u.Host == "" {
-		return nil, fmt.Errorf("invalid collector URL %q: no scheme or host", collectorURL)
+	if cfg.queueCapacity > 0 {
+		e.queue = newQueue(cfg.queueCapacity, cfg.backpressure.queuePolicy(cfg.queuePolicy))
+		senders := 1
+		if cfg.maxConcurrentExports > 1 {
+			senders = cfg.maxConcurrentExports
+		}
+		e.queue.start(e, senders)
 	}
+	if cfg.tailSampling != nil {
+		e.tailSampler = newTailSampler(*cfg.tailSampling)
+		e.tailSampler.start(e)
+	}
+	e.effective = newEffectiveConfig(cfg, e)
+	return e, nil
+}
 
-	cfg := config{}
-	for _, opt := range opts {
-		cfg = opt.apply(cfg)
+// resolveCollectorURL returns collectorURL, or the URL of the
+// OTEL_EXPORTER_HTTP_ENDPOINT environment variable or the default collector
+// if it is empty.
+func resolveCollectorURL(collectorURL string, cfg config) string {
+	if collectorURL != "" {
+		return collectorURL
+	}
+	def := defaultURL
+	if cfg.tempo {
+		def = defaultTempoURL
+	}
+	return envOr(envEndpoint, def)
+}
+
+// newDeliveryExporter validates cfg and creates an exporter for the
+// collector at collectorURL holding only what sending request bodies
+// requires: the HTTP client, the encoder and the request settings. Endpoints
+// are left to the caller and no background work is started, so the metrics
+// and logs exporters can share it. It returns cfg with its collector URL,
+// client and headers resolved.
+func newDeliveryExporter(collectorURL string, cfg config) (*Exporter, config, error) {
+	cfg.collectorURL = resolveCollectorURL(collectorURL, cfg)
+	if cfg.tempo {
+		cfg.headers = tempoHeaders(cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, cfg, err
+	}
+	if cfg.streaming && cfg.envCompressed {
+		// Streamed batches are not compressed: the environment only sets a
+		// default, which does not apply to them.
+		cfg.compression = NoCompression
+	}
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, cfg, err
 	}
+	// Shutdown only closes the idle connections of transports it created.
+	cfg.ownTransport = client != cfg.client
+	cfg.client = client
+	enc := newEncoder(cfg)
+	e := &Exporter{
+		logger:      cfg.logger,
+		encoder:     enc,
+		headers:     cfg.headers,
+		retry:       cfg.retry,
+		timeout:     cfg.timeout,
+		compression: cfg.compression,
+		userAgent:   cfg.userAgent,
+		sink:        cfg.sink,
+		slogLogger:  cfg.slogLogger,
+		logrLogger:  cfg.logrLogger,
 
-	if cfg.client == nil {
-		cfg.client = http.DefaultClient
+		idempotencyKeys: cfg.idempotencyKeys,
 	}
-	return &Exporter{
-		url:    collectorURL,
-		client: cfg.client,
-		logger: cfg.logger,
-	}, nil
+	if e.userAgent == "" {
+		e.userAgent = defaultUserAgent()
+	}
+	if he, ok := enc.(headerEncoder); ok {
+		headers := he.headers()
+		for k, v := range e.headers {
+			headers[k] = v
+		}
+		e.headers = headers
+	}
+	if cfg.encryptionKey != nil {
+		enc, err := newEncrypter(cfg.encryptionKey)
+		if err != nil {
+			return nil, cfg, err
+		}
+		e.encrypter = enc
+	}
+	if cfg.debugDumpDir != "" {
+		d, err := newDumper(cfg.debugDumpDir)
+		if err != nil {
+			return nil, cfg, err
+		}
+		e.dumper = d
+	}
+	return e, cfg, nil
 }
 
 // Export spans to fluent instance
 func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
 
-	e.stoppedMu.RLock()
-	stopped := e.stopped
-	e.stoppedMu.RUnlock()
-	if stopped {
+	if !e.begin() {
 		e.logf("exporter stopped, not exporting span batch")
-		return nil
+		return ErrShutdown
 	}
+	defer e.end()
 
+	spans = e.filterSpans(spans)
 	if len(spans) == 0 {
 		e.logf("no spans to export")
 		return nil
 	}
+	if e.tailSampler != nil {
+		return e.tailSampler.add(ctx, e, spans)
+	}
+	if e.queue != nil {
+		return e.queue.enqueue(ctx, e, spans)
+	}
+	return e.exportSpans(ctx, spans)
+}
+
+// exportSpans encodes and sends a batch of spans.
+func (e *Exporter) exportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.exportSlots != nil {
+		ok, err := e.acquireSlot(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			e.warnf("too many concurrent exports, dropped %d spans", len(spans))
+			e.recordDropped(len(spans))
+			return nil
+		}
+		defer func() { <-e.exportSlots }()
+	}
+	if spans = e.deduplicate(spans); len(spans) == 0 {
+		return nil
+	}
+	if e.limiter != nil {
+		n, err := e.limiter.admit(ctx, len(spans))
+		if err != nil {
+			return err
+		}
+		if n < len(spans) {
+			e.warnf("rate limit exceeded, dropped %d spans", len(spans)-n)
+			e.recordDropped(len(spans) - n)
+			if spans = spans[:n]; n == 0 {
+				return nil
+			}
+		}
+	}
 
-	httpSpans := convertSpansToHttp(spans)
-	body, err := json.Marshal(&httpSpans)
+	start := time.Now()
+	defer func() { e.recordDuration(time.Since(start)) }()
+
+	spans = e.transformSpans(spans)
+	if e.eventLogs != nil {
+		e.exportEventLogs(ctx, spans)
+	}
+	if e.router != nil {
+		return e.exportRoutes(ctx, spans)
+	}
+	return e.exportResources(ctx, spans, e.templated)
+}
+
+// exportResources sends spans in batches per resource when the encoder or a
+// templated collector URL requires it, or in a single batch.
+func (e *Exporter) exportResources(ctx context.Context, spans []sdktrace.ReadOnlySpan, templated bool) error {
+	batches := [][]sdktrace.ReadOnlySpan{spans}
+	if _, ok := e.encoder.(singleResourceEncoder); ok || templated {
+		batches = splitByResource(spans)
+	}
+	for _, batch := range batches {
+		bctx := ctx
+		if templated {
+			bctx = withResource(ctx, batch[0].Resource())
+		}
+		if err := e.exportBatch(bctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportBatch encodes a batch of spans and sends it to the collector.
+func (e *Exporter) exportBatch(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.adaptive != nil {
+		if limit := e.adaptive.current(); len(spans) > limit {
+			return e.exportChunks(ctx, spans, limit)
+		}
+	}
+	ctx = e.withBatchKey(withSpanCount(ctx, len(spans)))
+	ctx, pb, err := e.encodeBody(ctx, spans)
 
 	if err != nil {
-		return e.errf("unable to serialize span data")
+		return err
 	}
+	defer pb.release()
+	body := pb.body
 
 	if body == nil{
 		return e.errf("empty span data")
 	}
 
-	e.logf("about to send a POST request to %s with body %s", e.url, body)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewBuffer(body))
+	if e.maxPayloadBytes > 0 && len(body) > e.maxPayloadBytes && len(spans) > 1 {
+		return e.bisect(ctx, spans, e.exportBatch)
+	}
+
+	if len(e.endpoints) == 1 || e.sink != nil {
+		return e.exportPrimary(ctx, spans, body)
+	}
+	errs := make([]error, len(e.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range e.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			if i == 0 {
+				errs[i] = e.exportPrimary(ctx, spans, body)
+				return
+			}
+			errs[i] = e.deliver(ctx, ep, e.encoder.contentType(), body)
+		}(i, ep)
+	}
+	wg.Wait()
+	for i, err := range errs[1:] {
+		if err != nil {
+			e.warnf("failed to mirror batch to %s: %v", e.endpoints[i+1].url, err)
+		}
+	}
+	return errs[0]
+}
+
+// exportPrimary sends an encoded batch of spans to the primary collector. A
+// batch rejected as too large is split in halves which are sent separately.
+func (e *Exporter) exportPrimary(ctx context.Context, spans []sdktrace.ReadOnlySpan, body []byte) error {
+	xctx, x := withExchange(withSpanCount(ctx, len(spans)))
+	start := time.Now()
+	err := e.deliverPrimary(xctx, e.encoder.contentType(), body)
+	duration := time.Since(start)
+	var rerr *ErrPayloadRejected
+	if errors.As(err, &rerr) && rerr.Status == http.StatusRequestEntityTooLarge && len(spans) > 1 {
+		e.adapt(len(spans), duration, err, 0)
+		e.logf("batch of %d spans too large, splitting it", len(spans))
+		return e.bisect(ctx, spans, func(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+			ctx, pb, err := e.encodeBody(e.withBatchKey(ctx), spans)
+			if err != nil {
+				return err
+			}
+			defer pb.release()
+			return e.exportPrimary(ctx, spans, pb.body)
+		})
+	}
+	if e.refs != nil && errors.As(err, &rerr) && rerr.Status == http.StatusConflict && !resent(ctx) {
+		e.logf("collector does not know the resources of the batch, sending them again")
+		e.refs.forget()
+		ctx, pb, err := e.encodeBody(withResent(ctx), spans)
+		if err != nil {
+			return err
+		}
+		defer pb.release()
+		return e.exportPrimary(ctx, spans, pb.body)
+	}
+	var ps PartialSuccess
+	switch {
+	case err == nil:
+		if p, ok := e.partialSuccessParser(x.contentType, x.body); ok {
+			if ps = p; ps.Rejected > int64(len(spans)) {
+				ps.Rejected = int64(len(spans))
+			}
+			e.warnf("collector rejected %d of %d spans: %s", ps.Rejected, len(spans), ps.Message)
+		}
+		e.recordExported(len(spans), int(ps.Rejected))
+		if ps.Rejected > 0 {
+			// Which spans were accepted is unknown.
+			break
+		}
+		if e.dedup != nil {
+			e.dedup.add(keysOf(spans))
+		}
+		if e.refs != nil {
+			e.refs.confirm(spans)
+		}
+	case errors.Is(err, errCircuitOpen):
+		e.recordDropped(len(spans))
+	default:
+		e.recordFailed(err)
+	}
+	e.adapt(len(spans), duration, err, int(ps.Rejected))
+	e.notify(ExportInfo{
+		Spans:      len(spans),
+		Bytes:      len(body),
+		Duration:   duration,
+		StatusCode: x.statusCode,
+		Rejected:   int(ps.Rejected),
+		Message:    ps.Message,
+		Err:        err,
+	})
+	if err = e.persistOnFailure(ctx, err, spans, body); errors.Is(err, errCircuitOpen) && e.backpressure == BackpressureDrop {
+		return nil
+	}
+	return err
+}
+
+// encodeBody encodes a batch of spans into a pooled body, to release once
+// sent, which the requests sent with the returned context read from.
+func (e *Exporter) encodeBody(ctx context.Context, spans []sdktrace.ReadOnlySpan) (context.Context, *pooledBody, error) {
+	pb, err := encodeBody(e.encoder, spans)
+	if err != nil {
+		return ctx, nil, e.errf("unable to serialize span data: %w", err)
+	}
+	return withPooledBody(ctx, pb), pb, nil
+}
+
+// bisect exports both halves of a batch of spans with export, returning the
+// first error.
+func (e *Exporter) bisect(ctx context.Context, spans []sdktrace.ReadOnlySpan, export func(context.Context, []sdktrace.ReadOnlySpan) error) error {
+	half := len(spans) / 2
+	err := export(ctx, spans[:half])
+	if err2 := export(ctx, spans[half:]); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// persistOnFailure persists a batch the primary collector was unavailable
+// for when persistence is configured. It returns the delivery error.
+func (e *Exporter) persistOnFailure(ctx context.Context, err error, spans []sdktrace.ReadOnlySpan, body []byte) error {
+	if err == nil || e.wal == nil || routedEndpoint(ctx) != nil || !(unavailable(err) || ctx.Err() != nil) {
+		return err
+	}
+	var keys []spanKey
+	if e.dedup != nil {
+		// Replays are deduplicated too.
+		keys = keysOf(spans)
+	}
+	if perr := e.wal.persist(e.encoder.contentType(), keys, body); perr != nil {
+		e.warnf("%v", perr)
+	}
+	return err
+}
+
+// deliverPrimary sends a request body to the primary collector, unless the
+// circuit breaker is open.
+func (e *Exporter) deliverPrimary(ctx context.Context, contentType string, body []byte) error {
+	if ep := routedEndpoint(ctx); ep != nil {
+		return e.deliver(ctx, ep, contentType, body)
+	}
+	if e.breaker == nil {
+		return e.deliverActive(ctx, contentType, body)
+	}
+	if !e.breaker.allow() {
+		if e.backpressure != BackpressureBlock {
+			return errCircuitOpen
+		}
+		if err := e.breaker.wait(ctx); err != nil {
+			return err
+		}
+	}
+	err := e.deliverActive(ctx, contentType, body)
+	switch opened, closed := e.breaker.record(err); {
+	case opened:
+		e.warnf("circuit breaker opened: %v", err)
+	case closed:
+		e.warnf("circuit breaker closed")
+	}
+	return err
+}
+
+// deliverActive sends a request body to the primary collector, or to the
+// active fallback collector when failed over.
+func (e *Exporter) deliverActive(ctx context.Context, contentType string, body []byte) error {
+	if e.failover != nil {
+		return e.failover.deliver(ctx, e, contentType, body)
+	}
+	return e.deliverReplica(ctx, contentType, body)
+}
+
+// deliver sends a request body to a collector endpoint, retrying transient
+// failures, and accounts for the outcome.
+func (e *Exporter) deliver(ctx context.Context, ep *endpoint, contentType string, body []byte) error {
+	atomic.AddInt64(&ep.pending, 1)
+	defer atomic.AddInt64(&ep.pending, -1)
+	var err error
+	if e.sink != nil {
+		err = e.sink.write(contentType, body)
+	} else if ep.stream != nil {
+		wctx, cancel := e.withTimeout(ctx)
+		e.dumpRequest(contentType, body)
+		err = ep.stream.write(wctx, contentType, body)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			// The stream could not be opened or was closed by the collector.
+			err = retryableError{err: err}
+		}
+	} else {
+		ctx := e.ensureBatchKey(ctx)
+		err = e.retry.do(ctx, func() error {
+			return e.send(ctx, ep, contentType, body)
+		})
+	}
+	ep.record(err)
+	return err
+}
+
+// withTimeout bounds ctx to the request timeout of the exporter.
+func (e *Exporter) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, e.timeout)
+}
+
+// send posts a request body to a collector endpoint.
+func (e *Exporter) send(ctx context.Context, ep *endpoint, contentType string, body []byte) error {
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	reqURL := ep.requestURL(ctx)
+	e.dumpf("about to send a POST request to %s with body %s", reqURL, body)
+	dump := e.dumpRequest(contentType, body)
+	if e.compression == GzipCompression {
+		var err error
+		if body, err = gzipBody(body); err != nil {
+			return e.errf("failed to compress request body: %w", err)
+		}
+	}
+	if e.encrypter != nil {
+		var err error
+		if body, err = e.encrypter.seal(body); err != nil {
+			return e.errf("failed to encrypt request body: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewBuffer(body))
 	if err != nil {
-		return e.errf("failed to create request to %s: %v", e.url, err)
+		return e.errf("failed to create request to %s: %w", reqURL, err)
+	}
+	setRequestBody(ctx, req, body)
+	req.Header.Set("User-Agent", e.userAgent)
+	if id := newRequestID(); id != "" {
+		req.Header.Set(requestIDHeader, id)
+		ctx = withRequestID(ctx, id)
+	}
+	if key, ok := batchKey(ctx); ok {
+		req.Header.Set(idempotencyKeyHeader, key)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := e.client.Do(req)
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if e.compression == GzipCompression {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if e.encrypter != nil {
+		req.Header.Set(encryptionHeader, encryptionAlgorithm)
+		req.Header.Set(encryptionKeyIDHeader, e.encrypter.keyID)
+	}
+	start := time.Now()
+	resp, err := ep.client.Do(req)
+	elapsed := time.Since(start)
+	e.telemetry.request(ep.url, len(body), elapsed)
 	if err != nil {
-		return e.errf("request to %s failed: %v", e.url, err)
+		recordResponse(ctx, 0, "", nil)
+		err = fmt.Errorf("request to %s failed: %w", reqURL, err)
+		e.logAttempt(ctx, ep, 0, elapsed, err)
+		e.dumpResponse(dump, nil, nil, err)
+		return retryableError{err: err}
 	}
 	defer resp.Body.Close()	
 
-	_, err = io.Copy(ioutil.Discard, resp.Body)
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err == nil {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+	}
 	if err != nil {
-		return e.errf("failed to read response body: %v", err)
+		recordResponse(ctx, resp.StatusCode, "", nil)
+		err = fmt.Errorf("failed to read response body: %v", err)
+		e.logAttempt(ctx, ep, resp.StatusCode, elapsed, err)
+		e.dumpResponse(dump, nil, nil, err)
+		return err
 	}
+	recordResponse(ctx, resp.StatusCode, resp.Header.Get("Content-Type"), respBody)
 
-	if resp.StatusCode < 200 && resp.StatusCode > 300{
-		return e.errf("failed to send spans to server with status %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e.dumpResponse(dump, resp, respBody, nil)
+		if retryableStatus(resp.StatusCode) {
+			err := fmt.Errorf("failed to send data to server with status %d", resp.StatusCode)
+			e.logAttempt(ctx, ep, resp.StatusCode, elapsed, err)
+			return retryableError{err: err, retryAfter: retryAfter(resp)}
+		}
+		err := &ErrPayloadRejected{Status: resp.StatusCode, Body: respBody}
+		e.logAttempt(ctx, ep, resp.StatusCode, elapsed, err)
+		return err
 	}
-	e.logf("Spans sent with response code %d", resp.StatusCode)
+	e.logAttempt(ctx, ep, resp.StatusCode, elapsed, nil)
 
 	return nil
 }
 
-// Shutdown stops the exporter flushing any pending exports.
+// ForceFlush sends the batches queued by WithQueue and replays the batches
+// persisted by WithPersistence, waiting for them and for span events sent by
+// WithSpanEventLogs to be delivered. It returns
+// an error if they could not all be delivered before ctx is done.
+func (e *Exporter) ForceFlush(ctx context.Context) error {
+	e.stoppedMu.RLock()
+	stopped := e.stopped
+	e.stoppedMu.RUnlock()
+	if stopped {
+		return nil
+	}
+
+	if e.tailSampler != nil {
+		if err := e.tailSampler.flush(ctx, e); err != nil {
+			return err
+		}
+	}
+	if e.queue != nil {
+		if err := e.queue.flush(ctx); err != nil {
+			return err
+		}
+	}
+	if e.wal != nil && !e.wal.replay(ctx, e) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return e.errf("failed to deliver persisted batches")
+	}
+	return e.eventLogsPending.wait(ctx)
+}
+
+// shutdownGracePeriod bounds each step of Shutdown started once its context
+// is done, so that background goroutines are stopped regardless.
+const shutdownGracePeriod = time.Second
+
+// Shutdown stops the exporter. Exports in progress and batches queued by
+// WithQueue are waited for, up to ctx being done, and idle connections are
+// closed. The tail sampler, queue, persistence and streams are closed even if
+// ctx is done first, each within a short grace period, and all their errors
+// are returned. Exports after Shutdown fail.
 func (e *Exporter) Shutdown(ctx context.Context) error {
 	e.stoppedMu.Lock()
+	if e.stopped {
+		e.stoppedMu.Unlock()
+		return nil
+	}
 	e.stopped = true
 	e.stoppedMu.Unlock()
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
+	var errs []error
+	step := func(close func(context.Context) error) {
+		sctx, cancel := shutdownStepContext(ctx)
+		defer cancel()
+		if err := close(sctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	step(e.inflight.wait)
+	if e.tailSampler != nil {
+		step(func(ctx context.Context) error { return e.tailSampler.close(ctx, e) })
+	}
+	if e.queue != nil {
+		step(e.queue.close)
+	}
+	if e.wal != nil {
+		step(e.wal.close)
+	}
+	step(e.eventLogsPending.wait)
+	for _, ep := range e.allEndpoints() {
+		if ep.stream != nil {
+			step(ep.stream.close)
+		}
+	}
+	// The default client and those of the user are shared with the rest of
+	// the process: only the transports the exporter created are closed.
+	closed := make(map[*http.Client]bool)
+	for _, ep := range e.allEndpoints() {
+		if ep.ownTransport && !closed[ep.client] {
+			closed[ep.client] = true
+			ep.client.CloseIdleConnections()
+		}
 	}
-	return nil
-}
 
-func (e *Exporter) logf(format string, args ...interface{}) {
-	if e.logger != nil {
-		e.logger.Printf(format)
+	if len(errs) == 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 	}
+	return errors.Join(errs...)
 }
 
-func (e *Exporter) errf(format string, args ...interface{}) error {
-	e.logf(format, args...)
-	return fmt.Errorf(format)
+// shutdownStepContext returns the context of a step of Shutdown: ctx, or a
+// context bounded by the grace period if ctx is already done.
+func shutdownStepContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() == nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(context.WithoutCancel(ctx), shutdownGracePeriod)
 }
 
 // MarshalLog is the marshaling function used by the logging system to represent this exporter.
 func (e *Exporter) MarshalLog() interface{} {
 	return struct {
-		Type string
-		URL  string
+		Type        string
+		URL         string
+		ServiceName string
 	}{
-		Type: "http",
-		URL:  e.url,
+		Type:        "http",
+		URL:         e.url,
+		ServiceName: e.serviceName,
 	}
 }
 
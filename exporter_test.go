@@ -0,0 +1,296 @@
+package httpExporter_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// waitTimeout bounds the waits for spans to reach a collector.
+const waitTimeout = 5 * time.Second
+
+var lastID uint64
+
+// newSpans returns ended spans with the given names, each in its own trace.
+func newSpans(names ...string) []sdktrace.ReadOnlySpan {
+	stubs := make(tracetest.SpanStubs, 0, len(names))
+	for _, name := range names {
+		stubs = append(stubs, tracetest.SpanStub{
+			Name:        name,
+			SpanContext: newSpanContext(newTraceID()),
+			StartTime:   time.Now(),
+			EndTime:     time.Now(),
+		})
+	}
+	return stubs.Snapshots()
+}
+
+// newTraceID returns a trace ID unique to the test binary.
+func newTraceID() trace.TraceID {
+	var id trace.TraceID
+	binary.BigEndian.PutUint64(id[8:], atomic.AddUint64(&lastID, 1))
+	return id
+}
+
+// newSpanContext returns a sampled span context of the trace traceID with a
+// unique span ID.
+func newSpanContext(traceID trace.TraceID) trace.SpanContext {
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], atomic.AddUint64(&lastID, 1))
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+// request is a request received by a collector.
+type request struct {
+	Path   string
+	Header http.Header
+	Body   []byte // Decompressed when gzip encoded
+	Status int    // Status the collector answered with
+}
+
+// span is a span of the default JSON format, as received by a collector.
+type span struct {
+	TraceID      string                 `json:"traceId"`
+	SpanID       string                 `json:"spanId"`
+	ParentSpanID string                 `json:"parentSpanId"`
+	Name         string                 `json:"name"`
+	Attrs        map[string]interface{} `json:"attrs"`
+	Resource     map[string]interface{} `json:"resource"`
+}
+
+// collector is an HTTP collector recording the requests it receives.
+type collector struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []request
+	status   int
+	statuses []int // Statuses of the next responses, before status
+	latency  time.Duration
+}
+
+// newCollector starts a collector closed at the end of the test.
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{status: http.StatusOK}
+	c.server = httptest.NewServer(http.HandlerFunc(c.handle))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *collector) handle(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	status, latency := c.status, c.latency
+	if len(c.statuses) > 0 {
+		status, c.statuses = c.statuses[0], c.statuses[1:]
+	}
+	c.requests = append(c.requests, request{
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   b,
+		Status: status,
+	})
+	c.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	w.WriteHeader(status)
+}
+
+// URL returns the URL of the collector.
+func (c *collector) URL() string {
+	return c.server.URL
+}
+
+// setStatus configures the status code of the collector's responses. It
+// defaults to 200.
+func (c *collector) setStatus(code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = code
+}
+
+// respond configures the status codes of the next responses, after which
+// the collector answers with the status set with setStatus again.
+func (c *collector) respond(codes ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statuses = append(c.statuses, codes...)
+}
+
+// setLatency configures a delay before the collector responds.
+func (c *collector) setLatency(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latency = d
+}
+
+// received returns the requests received so far, including failed ones.
+func (c *collector) received() []request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]request(nil), c.requests...)
+}
+
+// count returns the number of requests received so far.
+func (c *collector) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.requests)
+}
+
+// batches returns the batches of the requests answered with a 2xx status.
+func (c *collector) batches(t *testing.T) [][]span {
+	t.Helper()
+	var batches [][]span
+	for _, r := range c.received() {
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		var batch []span
+		if err := json.Unmarshal(r.Body, &batch); err != nil {
+			t.Fatalf("invalid batch %s: %v", r.Body, err)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// spans returns the spans of the batches answered with a 2xx status.
+func (c *collector) spans(t *testing.T) []span {
+	t.Helper()
+	var spans []span
+	for _, batch := range c.batches(t) {
+		spans = append(spans, batch...)
+	}
+	return spans
+}
+
+// names returns the names of the spans received by c, in order.
+func (c *collector) names(t *testing.T) []string {
+	t.Helper()
+	return spanNames(c.spans(t))
+}
+
+// spanNames returns the names of spans.
+func spanNames(spans []span) []string {
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	return names
+}
+
+// equal reports whether got and want hold the same strings in the same
+// order.
+func equal(got []string, want ...string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newExporter creates an exporter shut down at the end of the test.
+func newExporter(t *testing.T, collectorURL string, opts ...httpExporter.Option) *httpExporter.Exporter {
+	t.Helper()
+	e, err := httpExporter.New(collectorURL, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+		defer cancel()
+		_ = e.Shutdown(ctx)
+	})
+	return e
+}
+
+// waitFor polls cond until it holds, failing the test after waitTimeout.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestExportSpans(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL())
+	spans := newSpans("a", "b")
+	if err := e.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(reqs))
+	}
+	if got := reqs[0].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	got := c.spans(t)
+	if names := spanNames(got); !equal(names, "a", "b") {
+		t.Fatalf("collector received %v, want [a b]", names)
+	}
+	if want := spans[0].SpanContext().TraceID().String(); got[0].TraceID != want {
+		t.Errorf("traceId = %s, want %s", got[0].TraceID, want)
+	}
+	if want := spans[1].SpanContext().SpanID().String(); got[1].SpanID != want {
+		t.Errorf("spanId = %s, want %s", got[1].SpanID, want)
+	}
+}
+
+func TestExportSpansCollectorError(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusBadRequest)
+	e := newExporter(t, c.URL())
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Error("ExportSpans succeeded with a 400 response")
+	}
+}
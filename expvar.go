@@ -0,0 +1,68 @@
+package httpExporter
+
+import "expvar"
+
+// Expvar returns an expvar.Var exposing the health of the exporter as JSON,
+// for scraping without OpenTelemetry metrics:
+//
+//	{
+//	  "queueDepth": 0,          // batches queued by WithQueue
+//	  "queueCapacity": 0,
+//	  "endpoints": [{
+//	    "url": "http://localhost:4000/",
+//	    "pending": 0,           // in-flight requests
+//	    "batchesSent": 0,
+//	    "batchesFailed": 0,
+//	    "consecutiveFailures": 0,
+//	    "lastSuccess": 0,       // unix seconds, 0 if none
+//	    "lastError": ""
+//	  }]
+//	}
+//
+// Publish it with expvar.Publish under a name of your choice.
+func (e *Exporter) Expvar() expvar.Var {
+	return expvar.Func(func() interface{} {
+		return e.expvarSnapshot()
+	})
+}
+
+type expvarStats struct {
+	QueueDepth    int              `json:"queueDepth"`
+	QueueCapacity int              `json:"queueCapacity"`
+	Endpoints     []expvarEndpoint `json:"endpoints"`
+}
+
+type expvarEndpoint struct {
+	URL                 string `json:"url"`
+	Pending             int64  `json:"pending"`
+	BatchesSent         uint64 `json:"batchesSent"`
+	BatchesFailed       uint64 `json:"batchesFailed"`
+	ConsecutiveFailures uint64 `json:"consecutiveFailures"`
+	LastSuccess         int64  `json:"lastSuccess"`
+	LastError           string `json:"lastError"`
+}
+
+func (e *Exporter) expvarSnapshot() expvarStats {
+	var stats expvarStats
+	if e.queue != nil {
+		stats.QueueDepth = len(e.queue.batches)
+		stats.QueueCapacity = cap(e.queue.batches)
+	}
+	for _, st := range e.EndpointStatus() {
+		ep := expvarEndpoint{
+			URL:                 st.URL,
+			Pending:             st.Pending,
+			BatchesSent:         st.BatchesSent,
+			BatchesFailed:       st.BatchesFailed,
+			ConsecutiveFailures: st.ConsecutiveFailures,
+		}
+		if !st.LastSuccess.IsZero() {
+			ep.LastSuccess = st.LastSuccess.Unix()
+		}
+		if st.LastError != nil {
+			ep.LastError = st.LastError.Error()
+		}
+		stats.Endpoints = append(stats.Endpoints, ep)
+	}
+	return stats
+}
@@ -0,0 +1,56 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestExpvar(t *testing.T) {
+	primary, mirror := newCollector(t), newCollector(t)
+	mirror.setStatus(http.StatusInternalServerError)
+	e := newExporter(t, primary.URL(),
+		httpExporter.WithAdditionalEndpoints(mirror.URL()),
+		httpExporter.WithQueue(4, httpExporter.Block),
+	)
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	var got struct {
+		QueueDepth    int `json:"queueDepth"`
+		QueueCapacity int `json:"queueCapacity"`
+		Endpoints     []struct {
+			URL                 string `json:"url"`
+			Pending             int64  `json:"pending"`
+			BatchesSent         uint64 `json:"batchesSent"`
+			BatchesFailed       uint64 `json:"batchesFailed"`
+			ConsecutiveFailures uint64 `json:"consecutiveFailures"`
+			LastSuccess         int64  `json:"lastSuccess"`
+			LastError           string `json:"lastError"`
+		} `json:"endpoints"`
+	}
+	if err := json.Unmarshal([]byte(e.Expvar().String()), &got); err != nil {
+		t.Fatalf("invalid expvar %s: %v", e.Expvar(), err)
+	}
+	if got.QueueDepth != 0 || got.QueueCapacity != 4 {
+		t.Errorf("queue depth %d, capacity %d, want 0 and 4", got.QueueDepth, got.QueueCapacity)
+	}
+	if len(got.Endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2", len(got.Endpoints))
+	}
+	p, m := got.Endpoints[0], got.Endpoints[1]
+	if p.URL != primary.URL() || p.BatchesSent != 1 || p.LastSuccess == 0 || p.LastError != "" {
+		t.Errorf("primary endpoint = %+v", p)
+	}
+	if m.URL != mirror.URL() || m.BatchesFailed != 1 || m.ConsecutiveFailures != 1 || m.LastSuccess != 0 || m.LastError == "" {
+		t.Errorf("mirror endpoint = %+v", m)
+	}
+}
@@ -0,0 +1,152 @@
+package httpExporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultFailoverThreshold is the default number of consecutive
+	// delivery failures after which the active collector is failed over.
+	defaultFailoverThreshold = 3
+	// defaultFailoverProbeInterval is how often the primary collector is
+	// probed for recovery while failed over, by default.
+	defaultFailoverProbeInterval = 30 * time.Second
+)
+
+// WithFallbackEndpoints configures collector URLs to fail over to, in order,
+// when the primary collector is unreachable or answers with a 5xx response.
+// A batch the active collector is unavailable for is sent to the next ones
+// in the same export. Once the active collector failed threshold batches in
+// a row (see WithFailoverPolicy), the exporter fails over to the collector
+// that accepted the last one. While failed over, a batch is sent to the
+// primary collector every probe interval to probe for its recovery; on
+// success the exporter switches back to it.
+func WithFallbackEndpoints(urls []string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.fallbackEndpoints = append(append([]string{}, cfg.fallbackEndpoints...), urls...)
+		return cfg
+	})
+}
+
+// WithFailoverPolicy configures the failover to the collectors of
+// WithFallbackEndpoints: the number of consecutive batches the active
+// collector must fail before the exporter fails over, 3 by default, and how
+// often the primary collector is probed for its recovery while failed over,
+// 30 seconds by default. Zero values keep the defaults.
+func WithFailoverPolicy(threshold int, probeInterval time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.failoverThreshold = threshold
+		cfg.failoverProbeInterval = probeInterval
+		return cfg
+	})
+}
+
+// failover delivers batches to the primary collector or, while it is
+// unavailable, to the first healthy fallback collector.
+type failover struct {
+	fallbacks     []*endpoint
+	threshold     int
+	probeInterval time.Duration
+
+	mu        sync.Mutex
+	active    int // 0 for the primary collector, i for fallbacks[i-1]
+	failures  int // Consecutive unavailable errors of the active collector
+	lastProbe time.Time
+}
+
+func newFailover(fallbacks []*endpoint, threshold int, probeInterval time.Duration) *failover {
+	if threshold <= 0 {
+		threshold = defaultFailoverThreshold
+	}
+	if probeInterval <= 0 {
+		probeInterval = defaultFailoverProbeInterval
+	}
+	return &failover{fallbacks: fallbacks, threshold: threshold, probeInterval: probeInterval}
+}
+
+// deliver sends a request body to the active collector or, if it is
+// unavailable, to the next ones, failing over once the active collector
+// failed threshold times in a row.
+func (f *failover) deliver(ctx context.Context, e *Exporter, contentType string, body []byte) error {
+	f.mu.Lock()
+	active := f.active
+	probe := active != 0 && time.Since(f.lastProbe) >= f.probeInterval
+	if probe {
+		f.lastProbe = time.Now()
+	}
+	f.mu.Unlock()
+
+	if probe {
+		if err := f.deliverTo(ctx, e, 0, contentType, body); err == nil {
+			e.warnf("primary collector %s recovered", e.url)
+			f.activate(0)
+			return nil
+		}
+	}
+
+	var err error
+	failures := 0
+	for i := active; i <= len(f.fallbacks); i++ {
+		if err = f.deliverTo(ctx, e, i, contentType, body); err == nil {
+			switch {
+			case i == active:
+				f.mu.Lock()
+				f.failures = 0
+				f.mu.Unlock()
+			case failures >= f.threshold:
+				e.warnf("failed over from %s to %s", f.name(e, active), f.name(e, i))
+				f.activate(i)
+			default:
+				e.logf("sent batch to %s, %s is unavailable", f.name(e, i), f.name(e, active))
+			}
+			return nil
+		}
+		if !unavailable(err) || ctx.Err() != nil {
+			// Another collector would reject the batch as well.
+			return err
+		}
+		if i == active {
+			f.mu.Lock()
+			f.failures++
+			failures = f.failures
+			f.mu.Unlock()
+		}
+	}
+	return err
+}
+
+// deliverTo sends a request body to the i-th collector.
+func (f *failover) deliverTo(ctx context.Context, e *Exporter, i int, contentType string, body []byte) error {
+	if i == 0 {
+		return e.deliverReplica(ctx, contentType, body)
+	}
+	return e.deliver(ctx, f.fallbacks[i-1], contentType, body)
+}
+
+func (f *failover) name(e *Exporter, i int) string {
+	if i == 0 {
+		return e.url
+	}
+	return f.fallbacks[i-1].url
+}
+
+func (f *failover) activate(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active = i
+	f.failures = 0
+	f.lastProbe = time.Now()
+}
+
+// unavailable reports whether err indicates the collector is unreachable or
+// failing, as opposed to rejecting the batch.
+func unavailable(err error) bool {
+	if Retryable(err) {
+		return true
+	}
+	var rerr *ErrPayloadRejected
+	return errors.As(err, &rerr) && rerr.Status >= 500
+}
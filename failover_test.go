@@ -0,0 +1,132 @@
+package httpExporter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestFailover(t *testing.T) {
+	primary, fallback := newCollector(t), newCollector(t)
+	primary.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, primary.URL(), httpExporter.WithFallbackEndpoints([]string{fallback.URL()}))
+
+	ctx := context.Background()
+	// Batches the primary collector fails are sent to the fallback collector
+	// in the same export.
+	names := []string{"a", "b", "c", "d"}
+	for _, name := range names {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if got := fallback.names(t); !equal(got, names...) {
+		t.Errorf("fallback collector received %v, want %v", got, names)
+	}
+	// After 3 failures, the primary collector is not sent to again until it
+	// is probed.
+	if got := primary.count(); got != 3 {
+		t.Errorf("primary collector received %d requests, want 3", got)
+	}
+}
+
+func TestFailoverChain(t *testing.T) {
+	primary, first, second := newCollector(t), newCollector(t), newCollector(t)
+	primary.setStatus(http.StatusBadGateway)
+	first.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, primary.URL(), httpExporter.WithFallbackEndpoints([]string{first.URL(), second.URL()}))
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if err := e.ExportSpans(ctx, newSpans("span")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if got := second.count(); got != 4 {
+		t.Errorf("second fallback received %d requests, want 4", got)
+	}
+	// The exporter failed over to the second fallback, which accepted the
+	// third batch.
+	if got := first.count(); got != 3 {
+		t.Errorf("first fallback received %d requests, want 3", got)
+	}
+}
+
+func TestFailoverAllUnavailable(t *testing.T) {
+	primary, fallback := newCollector(t), newCollector(t)
+	primary.setStatus(http.StatusServiceUnavailable)
+	fallback.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, primary.URL(), httpExporter.WithFallbackEndpoints([]string{fallback.URL()}))
+
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Error("ExportSpans succeeded with every collector unavailable")
+	}
+	if primary.count() != 1 || fallback.count() != 1 {
+		t.Errorf("collectors received %d and %d requests, want 1 each", primary.count(), fallback.count())
+	}
+}
+
+func TestFailoverPolicy(t *testing.T) {
+	primary, fallback := newCollector(t), newCollector(t)
+	primary.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, primary.URL(),
+		httpExporter.WithFallbackEndpoints([]string{fallback.URL()}),
+		httpExporter.WithFailoverPolicy(1, 50*time.Millisecond))
+
+	ctx := context.Background()
+	for _, name := range []string{"failover", "fallback"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if got := fallback.names(t); !equal(got, "failover", "fallback") {
+		t.Errorf("fallback collector received %v, want a failover after one failure", got)
+	}
+
+	// The recovered primary collector is probed after the probe interval.
+	time.Sleep(60 * time.Millisecond)
+	for _, name := range []string{"probe", "primary"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if got := primary.names(t); !equal(got, "probe", "primary") {
+		t.Errorf("primary collector received %v, want [probe primary]", got)
+	}
+
+	cfg := e.EffectiveConfig()
+	if cfg.FailoverThreshold != 1 || cfg.FailoverProbe != 50*time.Millisecond {
+		t.Errorf("EffectiveConfig reports a threshold of %d and probes every %v", cfg.FailoverThreshold, cfg.FailoverProbe)
+	}
+}
+
+func TestFailoverPolicyInvalid(t *testing.T) {
+	_, err := httpExporter.New("http://localhost:4318",
+		httpExporter.WithFallbackEndpoints([]string{"http://localhost:4319"}),
+		httpExporter.WithFailoverPolicy(-1, -time.Second))
+	var invalid *httpExporter.ErrInvalidConfig
+	if !errors.As(err, &invalid) || len(invalid.Problems) != 2 {
+		t.Errorf("New with a negative failover policy = %v, want an ErrInvalidConfig with 2 problems", err)
+	}
+}
+
+func TestFailoverRejectedBatch(t *testing.T) {
+	primary, fallback := newCollector(t), newCollector(t)
+	primary.setStatus(http.StatusBadRequest)
+	e := newExporter(t, primary.URL(), httpExporter.WithFallbackEndpoints([]string{fallback.URL()}))
+
+	for i := 0; i < 5; i++ {
+		if err := e.ExportSpans(context.Background(), newSpans("rejected")); err == nil {
+			t.Fatal("ExportSpans succeeded with a 400 response")
+		}
+	}
+	// A collector rejecting batches is not failed over: others would reject
+	// them as well.
+	if got := fallback.count(); got != 0 {
+		t.Errorf("fallback collector received %d requests, want none", got)
+	}
+}
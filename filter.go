@@ -0,0 +1,53 @@
+package httpExporter
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// WithSpanFilter configures a predicate deciding which spans are exported:
+// spans for which keep returns false are discarded before they are queued or
+// encoded, for instance health check spans, very short spans or spans of
+// specific instrumentation libraries. It may be used multiple times; a span
+// is exported only if every filter keeps it.
+func WithSpanFilter(keep func(sdktrace.ReadOnlySpan) bool) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.spanFilters = append(append([]func(sdktrace.ReadOnlySpan) bool{}, cfg.spanFilters...), keep)
+		return cfg
+	})
+}
+
+// filterSpans returns the spans kept by the span filters. The spans slice is
+// not modified, as it belongs to the caller.
+func (e *Exporter) filterSpans(spans []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	if len(e.spanFilters) == 0 {
+		return spans
+	}
+	var kept []sdktrace.ReadOnlySpan
+	for i, span := range spans {
+		if e.keep(span) {
+			if kept != nil {
+				kept = append(kept, span)
+			}
+			continue
+		}
+		if kept == nil {
+			kept = make([]sdktrace.ReadOnlySpan, i, len(spans)-1)
+			copy(kept, spans[:i])
+		}
+	}
+	if kept == nil {
+		return spans
+	}
+	e.logf("span filters discarded %d of %d spans", len(spans)-len(kept), len(spans))
+	return kept
+}
+
+// keep reports whether every span filter keeps span.
+func (e *Exporter) keep(span sdktrace.ReadOnlySpan) bool {
+	for _, keep := range e.spanFilters {
+		if !keep(span) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSpanFilter(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(),
+		httpExporter.WithSpanFilter(func(s sdktrace.ReadOnlySpan) bool { return s.Name() != "health" }),
+		httpExporter.WithSpanFilter(func(s sdktrace.ReadOnlySpan) bool { return s.Name() != "ping" }),
+	)
+
+	spans := newSpans("a", "health", "b", "ping")
+	if err := e.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b") {
+		t.Errorf("collector received %v, want [a b]", got)
+	}
+	if spans[1].Name() != "health" || spans[3].Name() != "ping" {
+		t.Error("ExportSpans modified the spans of the caller")
+	}
+}
+
+func TestSpanFilterDiscardsBatch(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithSpanFilter(func(sdktrace.ReadOnlySpan) bool { return false }))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests for a discarded batch", got)
+	}
+}
@@ -0,0 +1,126 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestForceFlushQueue(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(50 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(10, httpExporter.Block))
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c") {
+		t.Errorf("collector received %v after ForceFlush, want the queued batches", got)
+	}
+	// The exporter keeps working after a flush.
+	if err := e.ExportSpans(ctx, newSpans("d")); err != nil {
+		t.Fatalf("ExportSpans after ForceFlush: %v", err)
+	}
+}
+
+func TestForceFlushTimeout(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(500 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(10, httpExporter.Block))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	fctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := e.ForceFlush(fctx); err != context.DeadlineExceeded {
+		t.Errorf("ForceFlush = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestForceFlushReplaysPersistedBatches(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	dir := t.TempDir()
+	e := newExporter(t, c.URL(), httpExporter.WithPersistence(httpExporter.PersistenceConfig{Dir: dir}))
+
+	ctx := context.Background()
+	for _, name := range []string{"first", "second"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err == nil {
+			t.Fatal("ExportSpans succeeded while the collector is unavailable")
+		}
+	}
+	if err := e.ForceFlush(ctx); err == nil {
+		t.Error("ForceFlush succeeded while the collector is unavailable")
+	}
+	if got := persistedBatches(t, dir); len(got) != 2 {
+		t.Fatalf("%d batches persisted after a failed replay, want 2", len(got))
+	}
+
+	c.setStatus(http.StatusOK)
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := c.names(t); !equal(got, "first", "second") {
+		t.Errorf("collector received %v, want the persisted batches oldest first", got)
+	}
+	if got := persistedBatches(t, dir); len(got) != 0 {
+		t.Errorf("%d batches still persisted after replay", len(got))
+	}
+}
+
+func TestForceFlushReplaysPreviousProcess(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	pc := httpExporter.PersistenceConfig{Dir: t.TempDir()}
+
+	ctx := context.Background()
+	first := newExporter(t, c.URL(), httpExporter.WithPersistence(pc))
+	if err := first.ExportSpans(ctx, newSpans("persisted")); err == nil {
+		t.Fatal("ExportSpans succeeded while the collector is unavailable")
+	}
+	if err := first.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	c.setStatus(http.StatusOK)
+	second := newExporter(t, c.URL(), httpExporter.WithPersistence(pc))
+	if err := second.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := c.names(t); !equal(got, "persisted") {
+		t.Errorf("collector received %v, want [persisted]", got)
+	}
+	if got := persistedBatches(t, pc.Dir); len(got) != 0 {
+		t.Errorf("%d batches still persisted after replay", len(got))
+	}
+}
+
+func TestForceFlushDiscardsRejectedBatches(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	dir := t.TempDir()
+	e := newExporter(t, c.URL(), httpExporter.WithPersistence(httpExporter.PersistenceConfig{Dir: dir}))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("rejected")); err == nil {
+		t.Fatal("ExportSpans succeeded while the collector is unavailable")
+	}
+	c.setStatus(http.StatusBadRequest)
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := persistedBatches(t, dir); len(got) != 0 {
+		t.Errorf("%d rejected batches still persisted, want them discarded", len(got))
+	}
+}
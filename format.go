@@ -0,0 +1,236 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Format selects the wire format used to encode span batches.
+type Format int
+
+const (
+	// JSON encodes a batch as a JSON array of SpanData. This is the default.
+	JSON Format = iota
+	// OTLPProtobuf encodes a batch as an OTLP ExportTraceServiceRequest
+	// protobuf message, as accepted by any OTLP/HTTP collector.
+	OTLPProtobuf
+	// OTLPJSON encodes a batch as an OTLP/JSON ExportTraceServiceRequest
+	// (resourceSpans/scopeSpans) as accepted by any OTLP/HTTP collector.
+	OTLPJSON
+	// Zipkin encodes a batch as a Zipkin v2 JSON span list.
+	Zipkin
+	// Jaeger encodes a batch as a Thrift binary jaeger.Batch, as accepted by
+	// jaeger-collector.
+	Jaeger
+	// ElasticsearchBulk encodes a batch as an Elasticsearch bulk API request
+	// indexing one document per span. See WithElasticsearchIndex.
+	ElasticsearchBulk
+	// Datadog encodes a batch as a Datadog trace agent JSON payload, grouped
+	// by trace. See WithDatadogAPIKey.
+	Datadog
+	// XRay encodes a batch as an AWS X-Ray PutTraceSegments request holding
+	// one segment document per span.
+	XRay
+	// CBOR encodes a batch as a CBOR array of SpanData using the deterministic
+	// encoding of RFC 8949.
+	CBOR
+	// Honeycomb encodes a batch as a Honeycomb batch events request holding
+	// one event per span, span event and link. See WithHoneycombAPIKey and
+	// WithHoneycombDataset.
+	Honeycomb
+	// CloudTrace encodes a batch as a Google Cloud Trace v2 BatchWriteSpans
+	// request. See WithCloudTraceProject and WithGoogleMetadataToken.
+	CloudTrace
+	// ApplicationInsights encodes a batch as an Azure Monitor Application
+	// Insights track request holding request, dependency and message
+	// envelopes. See WithInstrumentationKey.
+	ApplicationInsights
+	// NewRelic encodes a batch as a New Relic Trace API payload in the
+	// newrelic format. See WithNewRelicAPIKey.
+	NewRelic
+	// KafkaREST encodes a batch as a Confluent Kafka REST Proxy produce
+	// request holding one record of SpanData per span, keyed by trace ID.
+	// See WithKafkaTopic.
+	KafkaREST
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "json"
+	case OTLPProtobuf:
+		return "otlp_proto"
+	case OTLPJSON:
+		return "otlp_json"
+	case Zipkin:
+		return "zipkin"
+	case Jaeger:
+		return "jaeger"
+	case ElasticsearchBulk:
+		return "elasticsearch_bulk"
+	case Datadog:
+		return "datadog"
+	case XRay:
+		return "xray"
+	case CBOR:
+		return "cbor"
+	case Honeycomb:
+		return "honeycomb"
+	case CloudTrace:
+		return "cloud_trace"
+	case ApplicationInsights:
+		return "application_insights"
+	case NewRelic:
+		return "newrelic"
+	case KafkaREST:
+		return "kafka_rest"
+	}
+	return "unknown"
+}
+
+// encoder serializes a batch of spans into a request body.
+type encoder interface {
+	// contentType is the value of the Content-Type header sent with the body.
+	contentType() string
+	// defaultPath is the URL path used when the collector URL has none.
+	defaultPath() string
+	encode(spans []sdktrace.ReadOnlySpan) ([]byte, error)
+}
+
+// singleResourceEncoder is implemented by encoders whose payload can only
+// describe spans from a single resource. Batches are split per resource
+// before being encoded.
+type singleResourceEncoder interface {
+	encoder
+	singleResource()
+}
+
+// headerEncoder is implemented by encoders whose requests need headers other
+// than Content-Type. Headers configured with WithHeaders take precedence.
+type headerEncoder interface {
+	encoder
+	headers() map[string]string
+}
+
+// payloadLimitEncoder is implemented by encoders for collectors limiting the
+// size of request bodies. The limit applies unless WithMaxPayloadBytes is
+// used.
+type payloadLimitEncoder interface {
+	encoder
+	maxPayloadBytes() int
+}
+
+// newEncoder returns the encoder for the configured format.
+func newEncoder(cfg config) encoder {
+	switch cfg.format {
+	case OTLPProtobuf:
+		return otlpProtoEncoder{}
+	case OTLPJSON:
+		return otlpJSONEncoder{}
+	case Zipkin:
+		return zipkinEncoder{}
+	case Jaeger:
+		return jaegerEncoder{}
+	case ElasticsearchBulk:
+		index := cfg.elasticsearchIndex
+		if index == "" {
+			index = defaultElasticsearchIndex
+		}
+		return elasticsearchEncoder{index: index, conv: newConverter(cfg)}
+	case Datadog:
+		return datadogEncoder{}
+	case XRay:
+		return xrayEncoder{}
+	case CBOR:
+		return cborEncoder{conv: newConverter(cfg)}
+	case Honeycomb:
+		dataset := cfg.honeycombDataset
+		if dataset == "" {
+			dataset = defaultHoneycombDataset
+		}
+		return honeycombEncoder{dataset: dataset}
+	case CloudTrace:
+		project := cfg.cloudTraceProject
+		if project == "" {
+			project = os.Getenv(envGoogleCloudProject)
+		}
+		return cloudTraceEncoder{project: project}
+	case ApplicationInsights:
+		return appInsightsEncoder{iKey: cfg.instrumentationKey}
+	case NewRelic:
+		return newRelicEncoder{}
+	case KafkaREST:
+		return kafkaRESTEncoder{topic: cfg.kafkaTopic, conv: newConverter(cfg)}
+	}
+	return &jsonEncoder{conv: newConverter(cfg), layout: cfg.payloadLayout}
+}
+
+// WithFormat configures the wire format used to encode exported spans.
+func WithFormat(format Format) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.format = format
+		return cfg
+	})
+}
+
+// jsonEncoder encodes spans as a JSON array of SpanData.
+type jsonEncoder struct {
+	conv   converter
+	layout PayloadLayout
+}
+
+func (*jsonEncoder) contentType() string { return "application/json" }
+
+func (*jsonEncoder) defaultPath() string { return "" }
+
+func (enc *jsonEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	return encodeBuffer(enc, spans)
+}
+
+// encodeTo converts and encodes the spans one at a time into buf, rather
+// than marshaling a converted copy of the whole batch.
+func (enc *jsonEncoder) encodeTo(buf *bytes.Buffer, spans []sdktrace.ReadOnlySpan) error {
+	switch enc.layout {
+	case ResourceLayout:
+		return enc.encodeResourceLayout(buf, spans)
+	case TraceLayout:
+		return enc.encodeTraceLayout(buf, spans)
+	}
+	je := json.NewEncoder(buf)
+	buf.WriteByte('[')
+	for i, span := range spans {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		httpSpan := enc.conv.convertSpanToHttp(span)
+		if err := je.Encode(&httpSpan); err != nil {
+			return err
+		}
+		// Encode terminates every value with a newline.
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// splitByResource partitions spans by resource, preserving their order.
+func splitByResource(spans []sdktrace.ReadOnlySpan) [][]sdktrace.ReadOnlySpan {
+	var batches [][]sdktrace.ReadOnlySpan
+	index := make(map[attribute.Distinct]int)
+	for _, span := range spans {
+		key := span.Resource().Equivalent()
+		i, ok := index[key]
+		if !ok {
+			i = len(batches)
+			index[key] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], span)
+	}
+	return batches
+}
@@ -0,0 +1,167 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// testStart is the start time of the test spans.
+var testStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+var (
+	testTraceID  = trace.TraceID{0x66, 0x32, 0x2d, 0x80, 0x5a, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5}
+	testLinkID   = trace.TraceID{0x66, 0x32, 0x2d, 0x7f, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c}
+	testServerID = trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	testClientID = trace.SpanID{0x53, 0x99, 0x5c, 0x3f, 0x42, 0xcd, 0x8a, 0xd8}
+)
+
+// testSpans returns a server span holding attributes of every type, an
+// exception event and a link, and a successful client child span, both of
+// the checkout service.
+func testSpans() []sdktrace.ReadOnlySpan {
+	server := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    testTraceID,
+		SpanID:     testServerID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	client := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    testTraceID,
+		SpanID:     testClientID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	linked := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: testLinkID,
+		SpanID:  trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+	})
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "checkout"),
+		attribute.String("host.name", "web-1"),
+	)
+	scope := instrumentation.Scope{Name: "github.com/example/checkout", Version: "1.2.0"}
+	return tracetest.SpanStubs{
+		{
+			Name:        "GET /cart",
+			SpanContext: server,
+			SpanKind:    trace.SpanKindServer,
+			StartTime:   testStart,
+			EndTime:     testStart.Add(150 * time.Millisecond),
+			Attributes: []attribute.KeyValue{
+				attribute.String("http.method", "GET"),
+				attribute.Int("http.status_code", 500),
+				attribute.Float64("cart.total", 12.5),
+				attribute.Bool("cart.empty", false),
+				attribute.StringSlice("cart.items", []string{"apple", "pear"}),
+			},
+			Events: []sdktrace.Event{{
+				Name: "exception",
+				Time: testStart.Add(100 * time.Millisecond),
+				Attributes: []attribute.KeyValue{
+					attribute.String("exception.type", "net.OpError"),
+					attribute.String("exception.message", "connection reset"),
+				},
+			}},
+			Links: []sdktrace.Link{{
+				SpanContext: linked,
+				Attributes:  []attribute.KeyValue{attribute.String("link.reason", "retry")},
+			}},
+			Status:               sdktrace.Status{Code: codes.Error, Description: "cart unavailable"},
+			Resource:             res,
+			InstrumentationScope: scope,
+		},
+		{
+			Name:        "SELECT carts",
+			SpanContext: client,
+			Parent:      server,
+			SpanKind:    trace.SpanKindClient,
+			StartTime:   testStart.Add(10 * time.Millisecond),
+			EndTime:     testStart.Add(60 * time.Millisecond),
+			Attributes: []attribute.KeyValue{
+				attribute.String("db.system", "postgresql"),
+				attribute.String("db.statement", "SELECT * FROM carts"),
+				attribute.String("net.peer.name", "db"),
+				attribute.Int("net.peer.port", 5432),
+			},
+			Status:               sdktrace.Status{Code: codes.Ok},
+			Resource:             res,
+			InstrumentationScope: scope,
+		},
+	}.Snapshots()
+}
+
+// encodeTestSpans encodes the test spans with the encoder configured by
+// opts.
+func encodeTestSpans(t *testing.T, opts ...Option) (encoder, []byte) {
+	t.Helper()
+	enc := newEncoder(newConfig(opts...))
+	body, err := enc.encode(testSpans())
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return enc, body
+}
+
+// checkEncoder checks the content type and default path of an encoder.
+func checkEncoder(t *testing.T, enc encoder, contentType, path string) {
+	t.Helper()
+	if got := enc.contentType(); got != contentType {
+		t.Errorf("content type = %q, want %q", got, contentType)
+	}
+	if got := enc.defaultPath(); got != path {
+		t.Errorf("default path = %q, want %q", got, path)
+	}
+}
+
+// checkGolden compares got with the golden file testdata/name. The golden
+// file is rewritten instead when the tests are run with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run the tests with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("payload differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// checkGoldenJSON compares an indented copy of the JSON document got with
+// the golden file testdata/name.
+func checkGoldenJSON(t *testing.T, name string, got []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, got, "", "\t"); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	buf.WriteByte('\n')
+	checkGolden(t, name, buf.Bytes())
+}
+
+func TestJSON(t *testing.T) {
+	enc, body := encodeTestSpans(t)
+	checkEncoder(t, enc, "application/json", "")
+	checkGoldenJSON(t, "json.json", body)
+}
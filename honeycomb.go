@@ -0,0 +1,151 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// honeycombBatchPath is the Honeycomb batch events API path, followed by
+	// the dataset.
+	honeycombBatchPath = "/1/batch/"
+	// honeycombTeamHeader is the header carrying the Honeycomb API key.
+	honeycombTeamHeader = "X-Honeycomb-Team"
+	// defaultHoneycombDataset sends spans to the dataset named after their
+	// service, as Honeycomb environments do.
+	defaultHoneycombDataset = "{service}"
+)
+
+// honeycombEncoder encodes spans as a Honeycomb batch of events: one event
+// per span, span event and link, with flattened attributes.
+type honeycombEncoder struct {
+	dataset string
+}
+
+func (honeycombEncoder) contentType() string { return "application/json" }
+
+func (enc honeycombEncoder) defaultPath() string { return honeycombBatchPath + enc.dataset }
+
+func (honeycombEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	events := make([]honeycombEvent, 0, len(spans))
+	for _, span := range spans {
+		events = appendHoneycombEvents(events, span)
+	}
+	return json.Marshal(events)
+}
+
+type honeycombEvent struct {
+	Time string                 `json:"time"` // RFC 3339
+	Data map[string]interface{} `json:"data"`
+}
+
+// appendHoneycombEvents appends the events of a span: the span itself,
+// followed by its span events and links annotating it, as the Honeycomb
+// OpenTelemetry ingest does.
+func appendHoneycombEvents(events []honeycombEvent, span sdktrace.ReadOnlySpan) []honeycombEvent {
+	sc := span.SpanContext()
+	traceID := sc.TraceID().String()
+	spanID := sc.SpanID().String()
+	res := span.Resource().Attributes()
+
+	data := make(map[string]interface{}, len(res)+len(span.Attributes())+12)
+	honeycombFields(data, res)
+	honeycombFields(data, span.Attributes())
+	data["trace.trace_id"] = traceID
+	data["trace.span_id"] = spanID
+	if psid := span.Parent().SpanID(); psid.IsValid() {
+		data["trace.parent_id"] = psid.String()
+	}
+	data["name"] = span.Name()
+	data["span.kind"] = span.SpanKind().String()
+	data["duration_ms"] = durationMs(span.EndTime().Sub(span.StartTime()))
+	data["status_code"] = int(statusCodeToOTLP(span.Status().Code))
+	if status := span.Status(); status.Code == codes.Error {
+		data["error"] = true
+		if status.Description != "" {
+			data["status_message"] = status.Description
+		}
+	}
+	if lib := span.InstrumentationLibrary(); lib.Name != "" {
+		data["library.name"] = lib.Name
+		if lib.Version != "" {
+			data["library.version"] = lib.Version
+		}
+	}
+	if name := serviceName(span.Resource()); name != "" {
+		data["service.name"] = name
+	}
+	if n := span.DroppedAttributes(); n > 0 {
+		data["meta.dropped_attributes_count"] = n
+	}
+	events = append(events, honeycombEvent{Time: honeycombTime(span.StartTime()), Data: data})
+
+	for _, ev := range span.Events() {
+		data := make(map[string]interface{}, len(res)+len(ev.Attributes)+6)
+		honeycombFields(data, res)
+		honeycombFields(data, ev.Attributes)
+		data["trace.trace_id"] = traceID
+		data["trace.parent_id"] = spanID
+		data["name"] = ev.Name
+		data["parent_name"] = span.Name()
+		data["meta.annotation_type"] = "span_event"
+		data["duration_ms"] = 0
+		events = append(events, honeycombEvent{Time: honeycombTime(ev.Time), Data: data})
+	}
+	for _, l := range span.Links() {
+		data := make(map[string]interface{}, len(res)+len(l.Attributes)+6)
+		honeycombFields(data, res)
+		honeycombFields(data, l.Attributes)
+		data["trace.trace_id"] = traceID
+		data["trace.parent_id"] = spanID
+		data["trace.link.trace_id"] = l.SpanContext.TraceID().String()
+		data["trace.link.span_id"] = l.SpanContext.SpanID().String()
+		data["parent_name"] = span.Name()
+		data["meta.annotation_type"] = "link"
+		events = append(events, honeycombEvent{Time: honeycombTime(span.StartTime()), Data: data})
+	}
+	return events
+}
+
+// honeycombFields adds attributes to the fields of an event. Honeycomb
+// columns are scalar, so slices are encoded as JSON arrays in strings.
+func honeycombFields(data map[string]interface{}, attrs []attribute.KeyValue) {
+	for _, kv := range attrs {
+		switch kv.Value.Type() {
+		case attribute.BOOL, attribute.INT64, attribute.FLOAT64, attribute.STRING:
+			data[string(kv.Key)] = kv.Value.AsInterface()
+		default:
+			data[string(kv.Key)] = attributeValueString(kv.Value)
+		}
+	}
+}
+
+func honeycombTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// durationMs returns d in fractional milliseconds.
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WithHoneycombAPIKey configures the API key sent in the X-Honeycomb-Team
+// header, as required by the Honeycomb batch events API.
+func WithHoneycombAPIKey(key string) Option {
+	return WithHeaders(map[string]string{honeycombTeamHeader: key})
+}
+
+// WithHoneycombDataset configures the dataset the Honeycomb format sends
+// events to, when the collector URL has no path. It defaults to the
+// service.name of the spans, batches being split by resource, and may hold
+// the placeholders of WithURLPath.
+func WithHoneycombDataset(dataset string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.honeycombDataset = dataset
+		return cfg
+	})
+}
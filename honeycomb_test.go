@@ -0,0 +1,16 @@
+package httpExporter
+
+import "testing"
+
+func TestHoneycomb(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(Honeycomb), WithHoneycombDataset("traces"))
+	checkEncoder(t, enc, "application/json", "/1/batch/traces")
+	checkGoldenJSON(t, "honeycomb.json", body)
+}
+
+func TestHoneycombDefaultDataset(t *testing.T) {
+	enc := newEncoder(newConfig(WithFormat(Honeycomb), WithHoneycombAPIKey("secret")))
+	if got := enc.defaultPath(); got != "/1/batch/{service}" {
+		t.Errorf("default path = %q, want the dataset of the service", got)
+	}
+}
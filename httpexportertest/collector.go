@@ -0,0 +1,310 @@
+// Package httpexportertest provides an in-process collector for testing
+// instrumentation end-to-end with the HTTP exporter.
+package httpexportertest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// Collector is an HTTP collector accepting batches in the default JSON
+// format of the exporter, in any payload layout and optionally gzip
+// compressed, and recording the decoded spans. Resources sent once per
+// session with WithResourceReferences are filled in the spans referencing
+// them, and batches streamed with WithStreaming are recorded as their frames
+// arrive.
+type Collector struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	batches  [][]httpExporter.SpanData
+	requests int
+	status   int
+	latency  time.Duration
+	received chan struct{} // Closed and replaced whenever a batch is recorded
+
+	resources map[resourceKey]httpExporter.Attributes // Referenced resources
+}
+
+// resourceKey identifies a resource referenced in a session.
+type resourceKey struct {
+	session, ref string
+}
+
+// NewCollector starts a collector. It should be closed with Close.
+func NewCollector() *Collector {
+	c := &Collector{
+		status:    http.StatusOK,
+		received:  make(chan struct{}),
+		resources: make(map[resourceKey]httpExporter.Attributes),
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(c.handle))
+	return c
+}
+
+// URL returns the URL to pass to httpExporter.New.
+func (c *Collector) URL() string {
+	return c.server.URL
+}
+
+// Close shuts the collector down.
+func (c *Collector) Close() {
+	c.server.Close()
+}
+
+// SetStatus configures the status code of the collector's responses.
+// Batches are only recorded when it is a 2xx status. It defaults to 200.
+func (c *Collector) SetStatus(code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = code
+}
+
+// ForgetResources makes the collector forget the resources referenced by
+// spans, as a restarted collector would. Batches referencing them are then
+// answered with 409 Conflict.
+func (c *Collector) ForgetResources() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resources = make(map[resourceKey]httpExporter.Attributes)
+}
+
+// SetLatency configures a delay before the collector responds.
+func (c *Collector) SetLatency(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latency = d
+}
+
+func (c *Collector) handle(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.requests++
+	status, latency := c.status, c.latency
+	c.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status < 200 || status >= 300 {
+		_, _ = io.Copy(ioutil.Discard, r.Body)
+		w.WriteHeader(status)
+		return
+	}
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer zr.Close()
+		r.Body = zr
+	}
+	if r.Header.Get("X-Stream-Framing") == "length-prefixed" {
+		c.handleStream(w, r)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if status, err := c.record(r, body); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(status)
+}
+
+// handleStream records the frames of a streaming request until it ends.
+func (c *Collector) handleStream(w http.ResponseWriter, r *http.Request) {
+	var size [4]byte
+	for {
+		if _, err := io.ReadFull(r.Body, size[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r.Body, body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if status, err := c.record(r, body); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// errUnknownResource is returned for batches referencing a resource the
+// collector does not know.
+var errUnknownResource = errors.New("unknown resource reference")
+
+// record decodes and records a batch. It returns the status to answer with
+// on failure.
+func (c *Collector) record(r *http.Request, body []byte) (int, error) {
+	batch, err := decodeBatch(body)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.resolveResources(r.Header.Get("X-Resource-Session"), batch) {
+		return http.StatusConflict, errUnknownResource
+	}
+	c.batches = append(c.batches, batch)
+	close(c.received)
+	c.received = make(chan struct{})
+	return http.StatusOK, nil
+}
+
+// resolveResources records the referenced resources of a batch and fills
+// them in the spans referencing them. It reports false if a referenced
+// resource is unknown.
+func (c *Collector) resolveResources(session string, batch []httpExporter.SpanData) bool {
+	for _, span := range batch {
+		if span.ResourceRef != "" && span.Resource != nil {
+			c.resources[resourceKey{session, span.ResourceRef}] = span.Resource
+		}
+	}
+	for i, span := range batch {
+		if span.ResourceRef == "" || span.Resource != nil {
+			continue
+		}
+		res, ok := c.resources[resourceKey{session, span.ResourceRef}]
+		if !ok {
+			return false
+		}
+		batch[i].Resource = res
+	}
+	return true
+}
+
+// decodeBatch decodes a JSON batch in any layout. Spans of the resource
+// layout get the resource and scope of their group.
+func decodeBatch(body []byte) ([]httpExporter.SpanData, error) {
+	if b := bytes.TrimSpace(body); len(b) == 0 || b[0] != '{' {
+		// A span of the flat layout or a trace of the trace layout.
+		var items []struct {
+			httpExporter.SpanData
+			Spans []httpExporter.SpanData `json:"spans"`
+		}
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, err
+		}
+		var batch []httpExporter.SpanData
+		for _, item := range items {
+			if item.Spans != nil {
+				batch = append(batch, item.Spans...)
+				continue
+			}
+			batch = append(batch, item.SpanData)
+		}
+		return batch, nil
+	}
+	var grouped struct {
+		ResourceSpans []struct {
+			Resource   httpExporter.Attributes `json:"resource"`
+			ScopeSpans []struct {
+				Scope struct {
+					Name    string `json:"name"`
+					Version string `json:"version"`
+				} `json:"scope"`
+				Spans []httpExporter.SpanData `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(body, &grouped); err != nil {
+		return nil, err
+	}
+	var batch []httpExporter.SpanData
+	for _, rs := range grouped.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				span.Resource = rs.Resource
+				span.InstrumentationLibraryName = ss.Scope.Name
+				span.InstrumentationLibraryVersion = ss.Scope.Version
+				batch = append(batch, span)
+			}
+		}
+	}
+	return batch, nil
+}
+
+// Batches returns the batches received so far.
+func (c *Collector) Batches() [][]httpExporter.SpanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]httpExporter.SpanData(nil), c.batches...)
+}
+
+// Requests returns the number of requests received so far, including
+// failed ones.
+func (c *Collector) Requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+// Spans returns the spans received so far, in order.
+func (c *Collector) Spans() []httpExporter.SpanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []httpExporter.SpanData
+	for _, batch := range c.batches {
+		spans = append(spans, batch...)
+	}
+	return spans
+}
+
+// SpansByName returns the spans received so far with the given name.
+func (c *Collector) SpansByName(name string) []httpExporter.SpanData {
+	var spans []httpExporter.SpanData
+	for _, span := range c.Spans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// WaitForSpans waits until at least n spans were received and returns them.
+// It fails the test if they are not received within timeout.
+func (c *Collector) WaitForSpans(tb testing.TB, n int, timeout time.Duration) []httpExporter.SpanData {
+	tb.Helper()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		c.mu.Lock()
+		received := c.received
+		c.mu.Unlock()
+		if spans := c.Spans(); len(spans) >= n {
+			return spans
+		}
+		select {
+		case <-received:
+		case <-deadline.C:
+			spans := c.Spans()
+			tb.Fatalf("received %d spans within %v, want at least %d", len(spans), timeout, n)
+			return spans
+		}
+	}
+}
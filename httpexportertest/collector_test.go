@@ -0,0 +1,171 @@
+package httpexportertest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"github.com/Syn3rman/httpExporter/httpexportertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newSpans returns ended spans with the given names and resource.
+func newSpans(res *resource.Resource, names ...string) []sdktrace.ReadOnlySpan {
+	stubs := make(tracetest.SpanStubs, 0, len(names))
+	for i, name := range names {
+		stubs = append(stubs, tracetest.SpanStub{
+			Name: name,
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    trace.TraceID{1},
+				SpanID:     trace.SpanID{byte(i + 1)},
+				TraceFlags: trace.FlagsSampled,
+			}),
+			StartTime: time.Now(),
+			EndTime:   time.Now(),
+			Resource:  res,
+		})
+	}
+	return stubs.Snapshots()
+}
+
+func export(t *testing.T, c *httpexportertest.Collector, spans []sdktrace.ReadOnlySpan, opts ...httpExporter.Option) error {
+	t.Helper()
+	e, err := httpExporter.New(c.URL(), opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+	return e.ExportSpans(context.Background(), spans)
+}
+
+func TestCollectorRecordsBatches(t *testing.T) {
+	c := httpexportertest.NewCollector()
+	defer c.Close()
+
+	if err := export(t, c, newSpans(nil, "a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := export(t, c, newSpans(nil, "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := len(c.Batches()); got != 2 {
+		t.Errorf("recorded %d batches, want 2", got)
+	}
+	if got := c.WaitForSpans(t, 3, time.Second); len(got) != 3 || got[0].Name != "a" {
+		t.Errorf("WaitForSpans returned %v", got)
+	}
+	if got := len(c.SpansByName("b")); got != 2 {
+		t.Errorf("SpansByName returned %d spans, want 2", got)
+	}
+	if got := c.Requests(); got != 2 {
+		t.Errorf("Requests() = %d, want 2", got)
+	}
+}
+
+func TestCollectorStatus(t *testing.T) {
+	c := httpexportertest.NewCollector()
+	defer c.Close()
+	c.SetStatus(http.StatusServiceUnavailable)
+
+	if err := export(t, c, newSpans(nil, "a")); err == nil {
+		t.Error("ExportSpans succeeded against a failing collector")
+	}
+	if got := c.Requests(); got != 1 {
+		t.Errorf("Requests() = %d, want 1", got)
+	}
+	if got := c.Spans(); len(got) != 0 {
+		t.Errorf("recorded %d spans of a failed request", len(got))
+	}
+}
+
+func TestCollectorGzip(t *testing.T) {
+	c := httpexportertest.NewCollector()
+	defer c.Close()
+
+	if err := export(t, c, newSpans(nil, "a"), httpExporter.WithCompression(httpExporter.GzipCompression)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.Spans(); len(got) != 1 || got[0].Name != "a" {
+		t.Errorf("recorded %v, want the span of the compressed batch", got)
+	}
+}
+
+func TestCollectorResourceLayout(t *testing.T) {
+	c := httpexportertest.NewCollector()
+	defer c.Close()
+	res := resource.NewSchemaless(attribute.String("service.name", "checkout"))
+
+	if err := export(t, c, newSpans(res, "a", "b"), httpExporter.WithPayloadLayout(httpExporter.ResourceLayout)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	spans := c.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	for _, span := range spans {
+		if len(span.Resource) != 1 || span.Resource[0] != attribute.String("service.name", "checkout") {
+			t.Errorf("span %s has resource %v", span.Name, span.Resource)
+		}
+	}
+}
+
+func TestCollectorUnknownResourceReference(t *testing.T) {
+	c := httpexportertest.NewCollector()
+	defer c.Close()
+
+	body := strings.NewReader(`[{"Name":"a","ResourceRef":"unknown"}]`)
+	req, err := http.NewRequest(http.MethodPost, c.URL(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Resource-Session", "session")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("got status %d, want 409", resp.StatusCode)
+	}
+	if got := c.Spans(); len(got) != 0 {
+		t.Errorf("recorded %d spans referencing an unknown resource", len(got))
+	}
+}
+
+func TestCollectorStream(t *testing.T) {
+	c := httpexportertest.NewCollector()
+	defer c.Close()
+
+	var body bytes.Buffer
+	for _, frame := range []string{`[{"Name":"a"}]`, `[{"Name":"b"}]`} {
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(frame)))
+		body.Write(size[:])
+		body.WriteString(frame)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.URL(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Stream-Framing", "length-prefixed")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("got status %d, want 200", resp.StatusCode)
+	}
+	if got := len(c.Batches()); got != 2 {
+		t.Errorf("recorded %d batches, want one per frame", got)
+	}
+}
@@ -0,0 +1,62 @@
+package httpExporter
+
+import (
+	"context"
+	"sync"
+)
+
+// pendingGroup counts pending operations, like a sync.WaitGroup whose Wait
+// honors a context.
+type pendingGroup struct {
+	mu   sync.Mutex
+	n    int
+	idle []chan struct{} // Closed once n drops to zero
+}
+
+// add adjusts the number of pending operations.
+func (g *pendingGroup) add(delta int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.n += delta
+	if g.n == 0 {
+		for _, ch := range g.idle {
+			close(ch)
+		}
+		g.idle = nil
+	}
+}
+
+// wait waits until there are no pending operations or ctx is done.
+func (g *pendingGroup) wait(ctx context.Context) error {
+	g.mu.Lock()
+	if g.n == 0 {
+		g.mu.Unlock()
+		return nil
+	}
+	idle := make(chan struct{})
+	g.idle = append(g.idle, idle)
+	g.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin accounts for an export, unless the exporter is shut down, in which
+// case it returns false. Each successful call must be paired with end.
+func (e *Exporter) begin() bool {
+	e.stoppedMu.RLock()
+	defer e.stoppedMu.RUnlock()
+	if e.stopped {
+		return false
+	}
+	e.inflight.add(1)
+	return true
+}
+
+// end accounts for the completion of an export.
+func (e *Exporter) end() {
+	e.inflight.add(-1)
+}
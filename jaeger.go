@@ -0,0 +1,275 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// jaegerTracesPath is the jaeger-collector Thrift over HTTP ingestion path.
+const jaegerTracesPath = "/api/traces"
+
+// jaegerEncoder encodes spans as a Thrift binary jaeger.Batch, as accepted
+// by jaeger-collector. A Batch describes a single process, so batches are
+// split per resource before encoding.
+type jaegerEncoder struct{}
+
+func (jaegerEncoder) contentType() string { return "application/vnd.apache.thrift.binary" }
+
+func (jaegerEncoder) defaultPath() string { return jaegerTracesPath }
+
+func (jaegerEncoder) singleResource() {}
+
+func (jaegerEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var w thriftWriter
+	// Batch
+	w.fieldHeader(thriftStruct, 1)
+	jaegerProcess(&w, spans)
+	w.fieldHeader(thriftList, 2)
+	w.listHeader(thriftStruct, len(spans))
+	for _, span := range spans {
+		jaegerSpan(&w, span)
+	}
+	w.fieldStop()
+	return w.buf.Bytes(), nil
+}
+
+// Jaeger tag value types.
+const (
+	jaegerTagString = 0
+	jaegerTagDouble = 1
+	jaegerTagBool   = 2
+	jaegerTagLong   = 3
+)
+
+// Jaeger span reference types.
+const (
+	jaegerRefChildOf     = 0
+	jaegerRefFollowsFrom = 1
+)
+
+// jaegerProcess writes the Process struct describing the resource of spans.
+func jaegerProcess(w *thriftWriter, spans []sdktrace.ReadOnlySpan) {
+	var (
+		name  string
+		attrs []attribute.KeyValue
+	)
+	if len(spans) > 0 {
+		res := spans[0].Resource()
+		name = serviceName(res)
+		attrs = res.Attributes()
+	}
+	w.fieldHeader(thriftString, 1)
+	w.string(name)
+	if len(attrs) > 0 {
+		w.fieldHeader(thriftList, 2)
+		w.listHeader(thriftStruct, len(attrs))
+		for _, kv := range attrs {
+			jaegerTag(w, kv)
+		}
+	}
+	w.fieldStop()
+}
+
+// jaegerSpan writes a span as a jaeger.Span struct. Links are mapped to
+// FOLLOWS_FROM references and events to logs.
+func jaegerSpan(w *thriftWriter, span sdktrace.ReadOnlySpan) {
+	sc := span.SpanContext()
+	tid := sc.TraceID()
+	sid := sc.SpanID()
+	psid := span.Parent().SpanID()
+
+	w.fieldHeader(thriftI64, 1)
+	w.i64(int64(binary.BigEndian.Uint64(tid[8:])))
+	w.fieldHeader(thriftI64, 2)
+	w.i64(int64(binary.BigEndian.Uint64(tid[:8])))
+	w.fieldHeader(thriftI64, 3)
+	w.i64(int64(binary.BigEndian.Uint64(sid[:])))
+	w.fieldHeader(thriftI64, 4)
+	w.i64(int64(binary.BigEndian.Uint64(psid[:])))
+	w.fieldHeader(thriftString, 5)
+	w.string(span.Name())
+
+	links := span.Links()
+	if psid.IsValid() || len(links) > 0 {
+		refs := len(links)
+		if psid.IsValid() {
+			refs++
+		}
+		w.fieldHeader(thriftList, 6)
+		w.listHeader(thriftStruct, refs)
+		if psid.IsValid() {
+			jaegerSpanRef(w, jaegerRefChildOf, tid, psid)
+		}
+		for _, l := range links {
+			jaegerSpanRef(w, jaegerRefFollowsFrom, l.SpanContext.TraceID(), l.SpanContext.SpanID())
+		}
+	}
+
+	w.fieldHeader(thriftI32, 7)
+	w.i32(int32(sc.TraceFlags()))
+	w.fieldHeader(thriftI64, 8)
+	w.i64(span.StartTime().UnixNano() / int64(time.Microsecond))
+	w.fieldHeader(thriftI64, 9)
+	w.i64(span.EndTime().Sub(span.StartTime()).Microseconds())
+
+	tags := append([]attribute.KeyValue{}, span.Attributes()...)
+	if kind := span.SpanKind(); kind != trace.SpanKindInternal && kind != trace.SpanKindUnspecified {
+		tags = append(tags, attribute.String("span.kind", kind.String()))
+	}
+	if lib := span.InstrumentationLibrary(); lib.Name != "" {
+		tags = append(tags, attribute.String("otel.library.name", lib.Name))
+		if lib.Version != "" {
+			tags = append(tags, attribute.String("otel.library.version", lib.Version))
+		}
+	}
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		tags = append(tags, attribute.String("otel.status_code", "OK"))
+	case codes.Error:
+		tags = append(tags,
+			attribute.String("otel.status_code", "ERROR"),
+			attribute.Bool("error", true),
+		)
+		if status.Description != "" {
+			tags = append(tags, attribute.String("otel.status_description", status.Description))
+		}
+	}
+	if len(tags) > 0 {
+		w.fieldHeader(thriftList, 10)
+		w.listHeader(thriftStruct, len(tags))
+		for _, kv := range tags {
+			jaegerTag(w, kv)
+		}
+	}
+
+	if events := span.Events(); len(events) > 0 {
+		w.fieldHeader(thriftList, 11)
+		w.listHeader(thriftStruct, len(events))
+		for _, ev := range events {
+			w.fieldHeader(thriftI64, 1)
+			w.i64(ev.Time.UnixNano() / int64(time.Microsecond))
+			fields := append([]attribute.KeyValue{attribute.String("event", ev.Name)}, ev.Attributes...)
+			w.fieldHeader(thriftList, 2)
+			w.listHeader(thriftStruct, len(fields))
+			for _, kv := range fields {
+				jaegerTag(w, kv)
+			}
+			w.fieldStop()
+		}
+	}
+	w.fieldStop()
+}
+
+// jaegerSpanRef writes a jaeger.SpanRef struct.
+func jaegerSpanRef(w *thriftWriter, refType int32, tid trace.TraceID, sid trace.SpanID) {
+	w.fieldHeader(thriftI32, 1)
+	w.i32(refType)
+	w.fieldHeader(thriftI64, 2)
+	w.i64(int64(binary.BigEndian.Uint64(tid[8:])))
+	w.fieldHeader(thriftI64, 3)
+	w.i64(int64(binary.BigEndian.Uint64(tid[:8])))
+	w.fieldHeader(thriftI64, 4)
+	w.i64(int64(binary.BigEndian.Uint64(sid[:])))
+	w.fieldStop()
+}
+
+// jaegerTag writes an attribute as a jaeger.Tag struct. Slices have no
+// native representation and are encoded as JSON strings.
+func jaegerTag(w *thriftWriter, kv attribute.KeyValue) {
+	w.fieldHeader(thriftString, 1)
+	w.string(string(kv.Key))
+	w.fieldHeader(thriftI32, 2)
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		w.i32(jaegerTagBool)
+		w.fieldHeader(thriftBool, 5)
+		w.bool(kv.Value.AsBool())
+	case attribute.INT64:
+		w.i32(jaegerTagLong)
+		w.fieldHeader(thriftI64, 6)
+		w.i64(kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		w.i32(jaegerTagDouble)
+		w.fieldHeader(thriftDouble, 4)
+		w.double(kv.Value.AsFloat64())
+	default:
+		w.i32(jaegerTagString)
+		w.fieldHeader(thriftString, 3)
+		w.string(attributeValueString(kv.Value))
+	}
+	w.fieldStop()
+}
+
+// Thrift binary protocol type identifiers.
+const (
+	thriftStop   byte = 0
+	thriftBool   byte = 2
+	thriftDouble byte = 4
+	thriftI32    byte = 8
+	thriftI64    byte = 10
+	thriftString byte = 11
+	thriftStruct byte = 12
+	thriftList   byte = 15
+)
+
+// thriftWriter is a minimal Thrift binary protocol writer covering the types
+// used by the Jaeger data model.
+type thriftWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *thriftWriter) fieldHeader(typ byte, id int16) {
+	w.buf.WriteByte(typ)
+	w.i16(id)
+}
+
+func (w *thriftWriter) fieldStop() {
+	w.buf.WriteByte(thriftStop)
+}
+
+func (w *thriftWriter) listHeader(elemType byte, size int) {
+	w.buf.WriteByte(elemType)
+	w.i32(int32(size))
+}
+
+func (w *thriftWriter) bool(v bool) {
+	if v {
+		w.buf.WriteByte(1)
+	} else {
+		w.buf.WriteByte(0)
+	}
+}
+
+func (w *thriftWriter) i16(v int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	w.buf.Write(b[:])
+}
+
+func (w *thriftWriter) i32(v int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(v))
+	w.buf.Write(b[:])
+}
+
+func (w *thriftWriter) i64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	w.buf.Write(b[:])
+}
+
+func (w *thriftWriter) double(v float64) {
+	w.i64(int64(math.Float64bits(v)))
+}
+
+func (w *thriftWriter) string(v string) {
+	w.i32(int32(len(v)))
+	w.buf.WriteString(v)
+}
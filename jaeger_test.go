@@ -0,0 +1,193 @@
+package httpExporter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// thriftStructValue is a decoded Thrift struct, keyed by field ID.
+type thriftStructValue map[int16]interface{}
+
+// decodeThrift decodes a Thrift binary value of type typ from b and returns
+// the rest of b. Structs decode to thriftStructValue, lists to slices,
+// strings to strings and numbers to int32, int64 or float64.
+func decodeThrift(b []byte, typ byte) (interface{}, []byte, error) {
+	need := func(n int) error {
+		if len(b) < n {
+			return fmt.Errorf("truncated value of type %d", typ)
+		}
+		return nil
+	}
+	switch typ {
+	case thriftBool:
+		if err := need(1); err != nil {
+			return nil, nil, err
+		}
+		return b[0] != 0, b[1:], nil
+	case thriftI32:
+		if err := need(4); err != nil {
+			return nil, nil, err
+		}
+		return int32(binary.BigEndian.Uint32(b)), b[4:], nil
+	case thriftI64:
+		if err := need(8); err != nil {
+			return nil, nil, err
+		}
+		return int64(binary.BigEndian.Uint64(b)), b[8:], nil
+	case thriftDouble:
+		if err := need(8); err != nil {
+			return nil, nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), b[8:], nil
+	case thriftString:
+		if err := need(4); err != nil {
+			return nil, nil, err
+		}
+		n := int(binary.BigEndian.Uint32(b))
+		b = b[4:]
+		if err := need(n); err != nil {
+			return nil, nil, err
+		}
+		return string(b[:n]), b[n:], nil
+	case thriftList:
+		if err := need(5); err != nil {
+			return nil, nil, err
+		}
+		elemType, n := b[0], int(binary.BigEndian.Uint32(b[1:]))
+		b = b[5:]
+		list := make([]interface{}, n)
+		for i := range list {
+			var err error
+			if list[i], b, err = decodeThrift(b, elemType); err != nil {
+				return nil, nil, err
+			}
+		}
+		return list, b, nil
+	case thriftStruct:
+		s := make(thriftStructValue)
+		for {
+			if err := need(1); err != nil {
+				return nil, nil, err
+			}
+			if b[0] == thriftStop {
+				return s, b[1:], nil
+			}
+			if err := need(3); err != nil {
+				return nil, nil, err
+			}
+			fieldType, id := b[0], int16(binary.BigEndian.Uint16(b[1:]))
+			var err error
+			if s[id], b, err = decodeThrift(b[3:], fieldType); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("unsupported type %d", typ)
+}
+
+// jaegerTags returns the tags of a decoded jaeger.Span or jaeger.Process
+// field, keyed by tag name.
+func jaegerTags(field interface{}) map[string]interface{} {
+	tags := make(map[string]interface{})
+	list, _ := field.([]interface{})
+	for _, tag := range list {
+		tag := tag.(thriftStructValue)
+		var v interface{}
+		switch tag[2].(int32) {
+		case jaegerTagString:
+			v = tag[3]
+		case jaegerTagDouble:
+			v = tag[4]
+		case jaegerTagBool:
+			v = tag[5]
+		case jaegerTagLong:
+			v = tag[6]
+		}
+		tags[tag[1].(string)] = v
+	}
+	return tags
+}
+
+func TestJaeger(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(Jaeger))
+	checkEncoder(t, enc, "application/vnd.apache.thrift.binary", "/api/traces")
+	if _, ok := enc.(singleResourceEncoder); !ok {
+		t.Error("Jaeger batches are not split by resource")
+	}
+
+	v, rest, err := decodeThrift(body, thriftStruct)
+	if err != nil {
+		t.Fatalf("invalid jaeger.Batch: %v", err)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("%d trailing bytes after the batch", len(rest))
+	}
+	batch := v.(thriftStructValue)
+	process := batch[1].(thriftStructValue)
+	if process[1] != "checkout" {
+		t.Errorf("service name = %v, want checkout", process[1])
+	}
+	if tags := jaegerTags(process[2]); tags["host.name"] != "web-1" {
+		t.Errorf("process tags = %v", tags)
+	}
+	spans := batch[2].([]interface{})
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	server := spans[0].(thriftStructValue)
+	if got, want := server[1], int64(binary.BigEndian.Uint64(testTraceID[8:])); got != want {
+		t.Errorf("traceIdLow = %v, want %v", got, want)
+	}
+	if got, want := server[2], int64(binary.BigEndian.Uint64(testTraceID[:8])); got != want {
+		t.Errorf("traceIdHigh = %v, want %v", got, want)
+	}
+	if got, want := server[3], int64(binary.BigEndian.Uint64(testServerID[:])); got != want {
+		t.Errorf("spanId = %v, want %v", got, want)
+	}
+	if server[4] != int64(0) || server[5] != "GET /cart" {
+		t.Errorf("parentSpanId %v, operationName %v", server[4], server[5])
+	}
+	if server[8] != testStart.UnixNano()/1000 || server[9] != int64(150000) {
+		t.Errorf("startTime %v, duration %v, want microseconds", server[8], server[9])
+	}
+	refs := server[6].([]interface{})
+	if len(refs) != 1 || refs[0].(thriftStructValue)[1] != int32(jaegerRefFollowsFrom) {
+		t.Errorf("references = %v, want the link as FOLLOWS_FROM", refs)
+	}
+	tags := jaegerTags(server[10])
+	for key, want := range map[string]interface{}{
+		"http.method":             "GET",
+		"http.status_code":        int64(500),
+		"cart.total":              12.5,
+		"cart.empty":              false,
+		"cart.items":              `["apple","pear"]`,
+		"span.kind":               "server",
+		"otel.library.name":       "github.com/example/checkout",
+		"otel.status_code":        "ERROR",
+		"error":                   true,
+		"otel.status_description": "cart unavailable",
+	} {
+		if tags[key] != want {
+			t.Errorf("tag %s = %#v, want %#v", key, tags[key], want)
+		}
+	}
+	logs := server[11].([]interface{})
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want the exception event", len(logs))
+	}
+	if fields := jaegerTags(logs[0].(thriftStructValue)[2]); fields["event"] != "exception" || fields["exception.type"] != "net.OpError" {
+		t.Errorf("log fields = %v", fields)
+	}
+
+	client := spans[1].(thriftStructValue)
+	if got, want := client[4], int64(binary.BigEndian.Uint64(testServerID[:])); got != want {
+		t.Errorf("client parentSpanId = %v, want %v", got, want)
+	}
+	refs = client[6].([]interface{})
+	if len(refs) != 1 || refs[0].(thriftStructValue)[1] != int32(jaegerRefChildOf) {
+		t.Errorf("client references = %v, want the parent as CHILD_OF", refs)
+	}
+}
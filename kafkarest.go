@@ -0,0 +1,52 @@
+package httpExporter
+
+import (
+	"encoding/json"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// kafkaRESTEncoder encodes spans as a Confluent Kafka REST Proxy v2 produce
+// request: one record per span holding its SpanData, keyed by trace ID so
+// that the spans of a trace land on the same partition.
+type kafkaRESTEncoder struct {
+	topic string
+	conv  converter
+}
+
+func (kafkaRESTEncoder) contentType() string { return "application/vnd.kafka.json.v2+json" }
+
+func (enc kafkaRESTEncoder) defaultPath() string { return "/topics/" + enc.topic }
+
+func (enc kafkaRESTEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var body []byte
+	err := withSpanData(enc.conv, spans, func(httpSpans []SpanData) error {
+		req := kafkaRESTRequest{Records: make([]kafkaRESTRecord, len(httpSpans))}
+		for i := range httpSpans {
+			req.Records[i] = kafkaRESTRecord{Key: httpSpans[i].TraceID, Value: &httpSpans[i]}
+		}
+		var err error
+		body, err = json.Marshal(req)
+		return err
+	})
+	return body, err
+}
+
+type kafkaRESTRequest struct {
+	Records []kafkaRESTRecord `json:"records"`
+}
+
+type kafkaRESTRecord struct {
+	Key   string    `json:"key"`
+	Value *SpanData `json:"value"`
+}
+
+// WithKafkaTopic configures the topic the KafkaREST format produces spans
+// to, when the collector URL has no path. It may hold the placeholders of
+// WithURLPath, for instance "traces-{service}".
+func WithKafkaTopic(topic string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.kafkaTopic = topic
+		return cfg
+	})
+}
@@ -0,0 +1,9 @@
+package httpExporter
+
+import "testing"
+
+func TestKafkaREST(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(KafkaREST), WithKafkaTopic("traces"))
+	checkEncoder(t, enc, "application/vnd.kafka.json.v2+json", "/topics/traces")
+	checkGoldenJSON(t, "kafkarest.json", body)
+}
@@ -0,0 +1,184 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/json"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PayloadLayout selects how the JSON format structures a batch of spans.
+type PayloadLayout int
+
+const (
+	// FlatLayout encodes a batch as an array of SpanData, each holding its
+	// resource and instrumentation scope. This is the default.
+	FlatLayout PayloadLayout = iota
+	// ResourceLayout groups the spans of a batch by resource and then by
+	// instrumentation scope, like OTLP resourceSpans and scopeSpans, so each
+	// resource is encoded once:
+	//
+	//	{"resourceSpans": [{"resource": {...}, "scopeSpans": [
+	//		{"scope": {"name": "...", "version": "..."}, "spans": [SpanData...]}
+	//	]}]}
+	//
+	// The spans omit the resource and instrumentation library fields.
+	ResourceLayout
+	// TraceLayout groups the spans of a batch by trace, so receivers can
+	// process whole traces without reassembling them:
+	//
+	//	[{"traceId": "...", "spans": [SpanData...]}]
+	TraceLayout
+)
+
+// String returns the name of the payload layout.
+func (l PayloadLayout) String() string {
+	switch l {
+	case FlatLayout:
+		return "flat"
+	case ResourceLayout:
+		return "resource"
+	case TraceLayout:
+		return "trace"
+	}
+	return "unknown"
+}
+
+// WithPayloadLayout configures how the JSON format structures a batch of
+// spans. It has no effect on other formats.
+func WithPayloadLayout(layout PayloadLayout) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.payloadLayout = layout
+		return cfg
+	})
+}
+
+// jsonScope is an instrumentation scope in the resource layout.
+type jsonScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// groupedSpanData is a SpanData whose resource and instrumentation scope are
+// encoded by its group. The shallower fields hide the ones of SpanData.
+type groupedSpanData struct {
+	*SpanData
+	InstrumentationLibraryName    string `json:"instrumentationLibraryName,omitempty"`
+	InstrumentationLibraryVersion string `json:"instrumentationLibraryVersion,omitempty"`
+}
+
+// encodeResourceLayout encodes spans grouped by resource and scope.
+func (enc *jsonEncoder) encodeResourceLayout(buf *bytes.Buffer, spans []sdktrace.ReadOnlySpan) error {
+	je := json.NewEncoder(buf)
+	buf.WriteString(`{"resourceSpans":[`)
+	for i, resourceSpans := range splitByResource(spans) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`{"resource":`)
+		if err := encodeJSON(je, buf, Attributes(resourceSpans[0].Resource().Attributes())); err != nil {
+			return err
+		}
+		buf.WriteString(`,"scopeSpans":[`)
+		for j, scopeSpans := range splitByScope(resourceSpans) {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			scope := scopeSpans[0].InstrumentationScope()
+			buf.WriteString(`{"scope":`)
+			if err := encodeJSON(je, buf, jsonScope{Name: scope.Name, Version: scope.Version}); err != nil {
+				return err
+			}
+			buf.WriteString(`,"spans":[`)
+			for k, span := range scopeSpans {
+				if k > 0 {
+					buf.WriteByte(',')
+				}
+				httpSpan := enc.conv.convertSpanToHttp(span)
+				httpSpan.Resource = nil
+				if err := encodeJSON(je, buf, groupedSpanData{SpanData: &httpSpan}); err != nil {
+					return err
+				}
+			}
+			buf.WriteString("]}")
+		}
+		buf.WriteString("]}")
+	}
+	buf.WriteString("]}")
+	return nil
+}
+
+// encodeTraceLayout encodes spans grouped by trace.
+func (enc *jsonEncoder) encodeTraceLayout(buf *bytes.Buffer, spans []sdktrace.ReadOnlySpan) error {
+	je := json.NewEncoder(buf)
+	buf.WriteByte('[')
+	for i, traceSpans := range splitByTrace(spans) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`{"traceId":`)
+		if err := encodeJSON(je, buf, enc.conv.traceID(traceSpans[0].SpanContext().TraceID())); err != nil {
+			return err
+		}
+		buf.WriteString(`,"spans":[`)
+		for j, span := range traceSpans {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			httpSpan := enc.conv.convertSpanToHttp(span)
+			if err := encodeJSON(je, buf, &httpSpan); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]}")
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// encodeJSON encodes v to buf with je, without the terminating newline.
+func encodeJSON(je *json.Encoder, buf *bytes.Buffer, v interface{}) error {
+	if err := je.Encode(v); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+// splitByScope partitions spans by instrumentation scope, preserving their
+// order.
+func splitByScope(spans []sdktrace.ReadOnlySpan) [][]sdktrace.ReadOnlySpan {
+	type scopeKey struct{ name, version, schemaURL string }
+	var batches [][]sdktrace.ReadOnlySpan
+	index := make(map[scopeKey]int)
+	for _, span := range spans {
+		scope := span.InstrumentationScope()
+		key := scopeKey{scope.Name, scope.Version, scope.SchemaURL}
+		i, ok := index[key]
+		if !ok {
+			i = len(batches)
+			index[key] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], span)
+	}
+	return batches
+}
+
+// splitByTrace partitions spans by trace, preserving their order.
+func splitByTrace(spans []sdktrace.ReadOnlySpan) [][]sdktrace.ReadOnlySpan {
+	var batches [][]sdktrace.ReadOnlySpan
+	index := make(map[trace.TraceID]int)
+	for _, span := range spans {
+		tid := span.SpanContext().TraceID()
+		i, ok := index[tid]
+		if !ok {
+			i = len(batches)
+			index[tid] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], span)
+	}
+	return batches
+}
@@ -0,0 +1,92 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// interleavedSpans returns the test spans with a span of another trace and
+// resource between them.
+func interleavedSpans() []sdktrace.ReadOnlySpan {
+	spans := testSpans()
+	other := tracetest.SpanStubs{{
+		Name: "other",
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: testLinkID,
+			SpanID:  trace.SpanID{1},
+		}),
+		StartTime: testStart,
+		EndTime:   testStart,
+		Resource:  resource.NewSchemaless(attribute.String("service.name", "billing")),
+	}}.Snapshots()
+	return []sdktrace.ReadOnlySpan{spans[0], other[0], spans[1]}
+}
+
+func TestResourceLayout(t *testing.T) {
+	_, body := encodeTestSpans(t, WithPayloadLayout(ResourceLayout))
+	checkGoldenJSON(t, "json_resource.json", body)
+}
+
+func TestResourceLayoutGroups(t *testing.T) {
+	body, err := newEncoder(newConfig(WithPayloadLayout(ResourceLayout))).encode(interleavedSpans())
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var payload struct {
+		ResourceSpans []struct {
+			Resource   map[string]interface{}
+			ScopeSpans []struct{ Spans []SpanData }
+		}
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("invalid payload %s: %v", body, err)
+	}
+	var got []string
+	for _, rs := range payload.ResourceSpans {
+		got = append(got, rs.Resource["service.name"].(string))
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				got = append(got, span.Name)
+			}
+		}
+	}
+	if want := "[checkout GET /cart SELECT carts billing other]"; fmt.Sprint(got) != want {
+		t.Errorf("payload holds %v, want %s", got, want)
+	}
+}
+
+func TestTraceLayout(t *testing.T) {
+	_, body := encodeTestSpans(t, WithPayloadLayout(TraceLayout))
+	checkGoldenJSON(t, "json_trace.json", body)
+}
+
+func TestTraceLayoutGroups(t *testing.T) {
+	body, err := newEncoder(newConfig(WithPayloadLayout(TraceLayout))).encode(interleavedSpans())
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var traces []struct {
+		TraceID string
+		Spans   []SpanData
+	}
+	if err := json.Unmarshal(body, &traces); err != nil {
+		t.Fatalf("invalid payload %s: %v", body, err)
+	}
+	var got []string
+	for _, tr := range traces {
+		got = append(got, tr.TraceID)
+		for _, span := range tr.Spans {
+			got = append(got, span.Name)
+		}
+	}
+	if want := fmt.Sprint([]string{testTraceID.String(), "GET /cart", "SELECT carts", testLinkID.String(), "other"}); fmt.Sprint(got) != want {
+		t.Errorf("payload holds %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,107 @@
+package httpExporter
+
+import (
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Limits bounds the size of exported spans. Zero fields are unlimited.
+type Limits struct {
+	// AttributeValueLength is the maximum length in bytes of string
+	// attribute values, and of each element of string slice values. Longer
+	// values are truncated.
+	AttributeValueLength int
+	// AttributeCount is the maximum number of attributes of a span, event or
+	// link. Further attributes are dropped.
+	AttributeCount int
+	// EventCount is the maximum number of events of a span. Like in the SDK,
+	// the oldest events are dropped.
+	EventCount int
+	// LinkCount is the maximum number of links of a span. Like in the SDK,
+	// the last links are dropped.
+	LinkCount int
+}
+
+// WithLimits configures limits enforced on spans before they are encoded, on
+// top of the span limits of the SDK. Dropped attributes, events and links are
+// added to the dropped counts of the exported spans, so the payload stays
+// honest about truncation. Attribute value lengths also apply to resources.
+func WithLimits(l Limits) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.limits = l
+		return cfg
+	})
+}
+
+// limitAttributes truncates attribute values and drops the attributes beyond
+// the attribute count limit, returning the number of dropped attributes.
+// attrs is not modified.
+func (e *Exporter) limitAttributes(attrs []attribute.KeyValue) ([]attribute.KeyValue, int) {
+	dropped := 0
+	if n := e.limits.AttributeCount; n > 0 && len(attrs) > n {
+		dropped = len(attrs) - n
+		attrs = attrs[:n]
+	}
+	return e.truncateValues(attrs), dropped
+}
+
+// truncateValues truncates attribute values to the value length limit.
+// attrs is not modified.
+func (e *Exporter) truncateValues(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if e.limits.AttributeValueLength <= 0 {
+		return attrs
+	}
+	var out []attribute.KeyValue
+	for i, kv := range attrs {
+		t, truncated := truncateValue(kv, e.limits.AttributeValueLength)
+		if !truncated {
+			if out != nil {
+				out = append(out, kv)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]attribute.KeyValue, i, len(attrs))
+			copy(out, attrs[:i])
+		}
+		out = append(out, t)
+	}
+	if out == nil {
+		return attrs
+	}
+	return out
+}
+
+// truncateValue truncates a string or string slice value to limit bytes,
+// reporting whether it was truncated.
+func truncateValue(kv attribute.KeyValue, limit int) (attribute.KeyValue, bool) {
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		if s := kv.Value.AsString(); len(s) > limit {
+			return kv.Key.String(truncateString(s, limit)), true
+		}
+	case attribute.STRINGSLICE:
+		ss := kv.Value.AsStringSlice()
+		truncated := false
+		for i, s := range ss {
+			if len(s) > limit {
+				ss[i] = truncateString(s, limit)
+				truncated = true
+			}
+		}
+		if truncated {
+			return kv.Key.StringSlice(ss), true
+		}
+	}
+	return kv, false
+}
+
+// truncateString truncates s to at most limit bytes without splitting a
+// UTF-8 encoded rune.
+func truncateString(s string, limit int) string {
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
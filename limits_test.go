@@ -0,0 +1,88 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLimits(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithLimits(httpExporter.Limits{
+		AttributeValueLength: 4,
+		AttributeCount:       2,
+		EventCount:           2,
+		LinkCount:            1,
+	}))
+
+	now := time.Now()
+	stub := tracetest.SpanStub{
+		Name:        "span",
+		SpanContext: newSpanContext(newTraceID()),
+		StartTime:   now,
+		EndTime:     now,
+		Attributes: []attribute.KeyValue{
+			attribute.String("a", "héllo"),
+			attribute.StringSlice("b", []string{"abcdef", "ab"}),
+			attribute.String("c", "dropped"),
+		},
+		DroppedAttributes: 1,
+		Events: []sdktrace.Event{
+			{Name: "first", Time: now},
+			{Name: "second", Time: now},
+			{Name: "third", Time: now, Attributes: []attribute.KeyValue{attribute.String("x", "truncated")}},
+		},
+		Links: []sdktrace.Link{
+			{SpanContext: newSpanContext(newTraceID())},
+			{SpanContext: newSpanContext(newTraceID())},
+			{SpanContext: newSpanContext(newTraceID())},
+		},
+		Resource: resource.NewSchemaless(attribute.String("service.name", "checkout")),
+	}
+	if err := e.ExportSpans(context.Background(), tracetest.SpanStubs{stub}.Snapshots()); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(reqs))
+	}
+	var spans []struct {
+		Attrs         map[string]interface{} `json:"attrs"`
+		DroppedAttrs  int                    `json:"droppedAttributesCount"`
+		Links         []json.RawMessage      `json:"links"`
+		DroppedLinks  int                    `json:"droppedLinkCount"`
+		Events        []httpExporter.Event   `json:"messageEvents"`
+		DroppedEvents int                    `json:"droppedMessageEventCount"`
+		Resource      map[string]interface{} `json:"resource"`
+	}
+	if err := json.Unmarshal(reqs[0].Body, &spans); err != nil || len(spans) != 1 {
+		t.Fatalf("invalid batch %s: %v", reqs[0].Body, err)
+	}
+	s := spans[0]
+	if len(s.Attrs) != 2 || s.Attrs["a"] != "hél" {
+		t.Errorf("attributes = %v, want 2 truncated to 4 bytes without splitting runes", s.Attrs)
+	}
+	if b, _ := s.Attrs["b"].([]interface{}); len(b) != 2 || b[0] != "abcd" || b[1] != "ab" {
+		t.Errorf("string slice attribute = %v", s.Attrs["b"])
+	}
+	if s.DroppedAttrs != 2 {
+		t.Errorf("droppedAttributesCount = %d, want the SDK's and ours", s.DroppedAttrs)
+	}
+	if len(s.Events) != 2 || s.Events[0].Name != "second" || s.Events[1].Name != "third" || s.DroppedEvents != 1 {
+		t.Errorf("events %+v, %d dropped, want the 2 most recent", s.Events, s.DroppedEvents)
+	}
+	if len(s.Links) != 1 || s.DroppedLinks != 2 {
+		t.Errorf("%d links, %d dropped, want the first one", len(s.Links), s.DroppedLinks)
+	}
+	if s.Resource["service.name"] != "chec" {
+		t.Errorf("resource = %v, want values truncated", s.Resource)
+	}
+}
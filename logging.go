@@ -0,0 +1,127 @@
+package httpExporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// levelPayload is the level of records dumping request bodies, below debug.
+const levelPayload = slog.LevelDebug - 4
+
+// WithSlogLogger configures the exporter to log through logger, with levels
+// and structured fields. It takes precedence over WithLogger. Each request
+// to a collector is logged with its endpoint, span_count, status_code and
+// duration: at debug level when it succeeds and at warn level otherwise.
+// Request bodies are dumped at level slog.LevelDebug-4.
+func WithSlogLogger(logger *slog.Logger) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.slogLogger = logger
+		return cfg
+	})
+}
+
+// WithLogr configures the exporter to log through logger, as OpenTelemetry
+// SDK components do: errors with Error, events affecting the delivery of
+// spans at V(0), per request records at V(1) and request body dumps at V(2).
+// WithSlogLogger takes precedence over it.
+func WithLogr(logger logr.Logger) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.logrLogger = logger
+		return cfg
+	})
+}
+
+// log emits a log record at level with attrs through the slog.Logger or
+// logr.Logger of the exporter. Without either, records at info level or
+// above are printed by the log.Logger, if any, and so are request body dumps
+// when debug dumps are enabled.
+func (e *Exporter) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	if e.slogLogger != nil {
+		e.slogLogger.LogAttrs(context.Background(), level, msg, attrs...)
+		return
+	}
+	if e.logrLogger.GetSink() != nil {
+		kvs := make([]interface{}, 0, 2*len(attrs))
+		for _, a := range attrs {
+			kvs = append(kvs, a.Key, a.Value.Any())
+		}
+		switch {
+		case level >= slog.LevelError:
+			e.logrLogger.Error(nil, msg, kvs...)
+		case level >= slog.LevelWarn:
+			e.logrLogger.Info(msg, kvs...)
+		case level >= slog.LevelDebug:
+			e.logrLogger.V(1).Info(msg, kvs...)
+		default:
+			e.logrLogger.V(2).Info(msg, kvs...)
+		}
+		return
+	}
+	if e.logger == nil {
+		return
+	}
+	if level < slog.LevelInfo && !(level == levelPayload && e.dumper != nil) {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, a := range attrs {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+	}
+	e.logger.Print(b.String())
+}
+
+// logf logs a debug diagnostic.
+func (e *Exporter) logf(format string, args ...interface{}) {
+	e.log(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// dumpf logs a request body dump.
+func (e *Exporter) dumpf(format string, args ...interface{}) {
+	e.log(levelPayload, fmt.Sprintf(format, args...))
+}
+
+// warnf logs an event affecting the delivery of spans.
+func (e *Exporter) warnf(format string, args ...interface{}) {
+	e.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// errf logs and returns an error.
+func (e *Exporter) errf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	e.log(slog.LevelError, err.Error())
+	return err
+}
+
+// logAttempt logs the outcome of a request to a collector.
+func (e *Exporter) logAttempt(ctx context.Context, ep *endpoint, statusCode int, d time.Duration, err error) {
+	attrs := []slog.Attr{
+		slog.String("endpoint", ep.url),
+		slog.Int("status_code", statusCode),
+		slog.Duration("duration", d),
+	}
+	if n, ok := ctx.Value(spanCountKey{}).(int); ok {
+		attrs = append(attrs, slog.Int("span_count", n))
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if err != nil {
+		e.log(slog.LevelWarn, "export request failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	e.log(slog.LevelDebug, "export request sent", attrs...)
+}
+
+type spanCountKey struct{}
+
+// withSpanCount annotates ctx with the number of spans of the batch being
+// exported, for logging.
+func withSpanCount(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, spanCountKey{}, n)
+}
@@ -0,0 +1,178 @@
+package httpExporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"github.com/go-logr/logr/funcr"
+)
+
+// logBuffer is a concurrency-safe buffer for log output.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// records decodes the JSON records of a slog.JSONHandler written to b.
+func (b *logBuffer) records(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid log record %s: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// find returns the first record with the message msg.
+func find(records []map[string]interface{}, msg string) map[string]interface{} {
+	for _, r := range records {
+		if r["msg"] == msg {
+			return r
+		}
+	}
+	return nil
+}
+
+func TestSlogLogger(t *testing.T) {
+	c := newCollector(t)
+	var buf logBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	e := newExporter(t, c.URL(), httpExporter.WithSlogLogger(logger))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	c.setStatus(http.StatusBadRequest)
+	_ = e.ExportSpans(ctx, newSpans("c"))
+
+	records := buf.records(t)
+	sent := find(records, "export request sent")
+	if sent == nil {
+		t.Fatalf("no record of the request sent in %v", records)
+	}
+	if sent["level"] != "DEBUG" || sent["endpoint"] != c.URL() || sent["span_count"] != 2.0 || sent["status_code"] != 200.0 {
+		t.Errorf("request sent record = %v", sent)
+	}
+	if _, ok := sent["duration"]; !ok {
+		t.Errorf("request sent record has no duration: %v", sent)
+	}
+	failed := find(records, "export request failed")
+	if failed == nil {
+		t.Fatalf("no record of the failed request in %v", records)
+	}
+	if failed["level"] != "WARN" || failed["span_count"] != 1.0 || failed["status_code"] != 400.0 || failed["error"] == nil {
+		t.Errorf("request failed record = %v", failed)
+	}
+	// Request bodies are dumped below the debug level only.
+	if strings.Contains(buf.String(), `\"name\":\"a\"`) {
+		t.Error("request body dumped at debug level")
+	}
+}
+
+func TestSlogLoggerPayloadLevel(t *testing.T) {
+	c := newCollector(t)
+	var buf logBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+	e := newExporter(t, c.URL(), httpExporter.WithSlogLogger(logger))
+	if err := e.ExportSpans(context.Background(), newSpans("dumped")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if !strings.Contains(buf.String(), "dumped") {
+		t.Errorf("request body not dumped at level DEBUG-4:\n%s", buf.String())
+	}
+}
+
+func TestLogr(t *testing.T) {
+	for _, tt := range []struct {
+		verbosity  int
+		sent, dump bool
+	}{
+		{0, false, false},
+		{1, true, false},
+		{2, true, true},
+	} {
+		c := newCollector(t)
+		var buf logBuffer
+		logger := funcr.New(func(prefix, args string) {
+			buf.Write([]byte(args + "\n"))
+		}, funcr.Options{Verbosity: tt.verbosity})
+		e := newExporter(t, c.URL(), httpExporter.WithLogr(logger))
+
+		ctx := context.Background()
+		if err := e.ExportSpans(ctx, newSpans("dumped")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+		c.setStatus(http.StatusBadRequest)
+		_ = e.ExportSpans(ctx, newSpans("rejected"))
+
+		out := buf.String()
+		if got := strings.Contains(out, `"msg"="export request sent"`); got != tt.sent {
+			t.Errorf("V(%d): request sent logged = %v, want %v", tt.verbosity, got, tt.sent)
+		}
+		if got := strings.Contains(out, "dumped"); got != tt.dump {
+			t.Errorf("V(%d): request body dumped = %v, want %v", tt.verbosity, got, tt.dump)
+		}
+		// Failed requests are logged at V(0).
+		if !strings.Contains(out, `"msg"="export request failed"`) || !strings.Contains(out, `"status_code"=400`) {
+			t.Errorf("V(%d): failed request not logged:\n%s", tt.verbosity, out)
+		}
+	}
+}
+
+func TestLogger(t *testing.T) {
+	for _, dump := range []bool{false, true} {
+		c := newCollector(t)
+		var buf logBuffer
+		opts := []httpExporter.Option{httpExporter.WithLogger(log.New(&buf, "", 0))}
+		if dump {
+			opts = append(opts, httpExporter.WithDebugDump(t.TempDir()))
+		}
+		e := newExporter(t, c.URL(), opts...)
+
+		ctx := context.Background()
+		if err := e.ExportSpans(ctx, newSpans("dumped")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+		c.setStatus(http.StatusBadRequest)
+		_ = e.ExportSpans(ctx, newSpans("rejected"))
+
+		out := buf.String()
+		if strings.Contains(out, "export request sent") {
+			t.Errorf("debug record printed:\n%s", out)
+		}
+		if !strings.Contains(out, "export request failed") {
+			t.Errorf("warning not printed:\n%s", out)
+		}
+		if got := strings.Contains(out, "dumped"); got != dump {
+			t.Errorf("request body printed = %v with debug dumps %v:\n%s", got, dump, out)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package httpExporter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// defaultLogsPath is the collector path log records are posted to when none
+// is configured.
+const defaultLogsPath = "/v1/logs"
+
+// LogExporter implements the sdk/log Exporter interface, posting log records
+// as JSON to the same collector and with the same options as the span
+// Exporter.
+type LogExporter struct {
+	exporter *Exporter
+	endpoint *endpoint
+}
+
+var (
+	_ sdklog.Exporter = &LogExporter{}
+)
+
+// WithLogsPath configures the collector URL path log records are posted to.
+// Defaults to /v1/logs. It is joined to the path of the collector URL, less a
+// trailing /v1/traces.
+func WithLogsPath(path string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.logsPath = path
+		return cfg
+	})
+}
+
+// NewLogExporter creates a log exporter for the collector at collectorURL.
+// It accepts the same options as New, but only the client, request and
+// retry ones apply: the span pipeline, such as the queue, persistence or
+// tail sampling, is not started. Log records are always encoded as JSON, so
+// other formats are rejected.
+func NewLogExporter(collectorURL string, opts ...Option) (*LogExporter, error) {
+	cfg := newConfig(opts...)
+	cfg.signal = "logs"
+	exp, cfg, err := newDeliveryExporter(collectorURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := newEndpoint(cfg.collectorURL, cfg, exp.encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.logsPath == "" {
+		cfg.logsPath = defaultLogsPath
+	}
+	if ep.url, err = signalURL(ep.url, cfg.logsPath); err != nil {
+		return nil, err
+	}
+	exp.endpoints = []*endpoint{ep}
+	exp.url = ep.url
+	return &LogExporter{
+		exporter: exp,
+		endpoint: ep,
+	}, nil
+}
+
+// Export log records to the collector. After Shutdown, it returns
+// sdkmetric.ErrExporterShutdown, like MetricExporter.
+func (e *LogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if !e.exporter.begin() {
+		return sdkmetric.ErrExporterShutdown
+	}
+	defer e.exporter.end()
+
+	if len(records) == 0 {
+		e.exporter.logf("no log records to export")
+		return nil
+	}
+
+	logs := convertLogsToHttp(records)
+	body, err := json.Marshal(&logs)
+	if err != nil {
+		return e.exporter.errf("unable to serialize log data")
+	}
+	return e.exporter.deliver(ctx, e.endpoint, "application/json", body)
+}
+
+// ForceFlush does nothing, log records are not buffered by the exporter.
+func (e *LogExporter) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Shutdown stops the exporter.
+func (e *LogExporter) Shutdown(ctx context.Context) error {
+	return e.exporter.Shutdown(ctx)
+}
+
+// MarshalLog is the marshaling function used by the logging system to represent this exporter.
+func (e *LogExporter) MarshalLog() interface{} {
+	return struct {
+		Type string
+		URL  string
+	}{
+		Type: "http_log",
+		URL:  e.endpoint.url,
+	}
+}
+
+// LogData contains all the properties of a log record.
+type LogData struct {
+	Timestamp                     int64         `json:"timestamp"`         // Time the event occurred
+	ObservedTimestamp             int64         `json:"observedTimestamp"` // Time the event was observed
+	SeverityNumber                int           `json:"severityNumber"`
+	SeverityText                  string        `json:"severityText"`
+	Body                          interface{}   `json:"body"`
+	Attrs                         LogAttributes `json:"attrs"` // A collection of key-value pairs
+	DroppedAttributeCount         int           `json:"droppedAttributesCount"`
+	TraceID                       string        `json:"traceId,omitempty"` // Trace the record was emitted in, if any
+	SpanID                        string        `json:"spanId,omitempty"`  // Span the record was emitted in, if any
+	InstrumentationLibraryName    string        `json:"instrumentationLibraryName"`
+	InstrumentationLibraryVersion string        `json:"instrumentationLibraryVersion"`
+	Resource                      Attributes    `json:"resource,omitempty"` // Contains attributes representing an entity that produced this record
+}
+
+func convertLogsToHttp(records []sdklog.Record) []LogData {
+	logs := make([]LogData, 0, len(records))
+	for _, r := range records {
+		l := LogData{
+			Timestamp:                     unixNano(r.Timestamp()),
+			ObservedTimestamp:             unixNano(r.ObservedTimestamp()),
+			SeverityNumber:                int(r.Severity()),
+			SeverityText:                  r.SeverityText(),
+			Body:                          logValueToInterface(r.Body()),
+			Attrs:                         make(LogAttributes, 0, r.AttributesLen()),
+			DroppedAttributeCount:         r.DroppedAttributes(),
+			InstrumentationLibraryName:    r.InstrumentationScope().Name,
+			InstrumentationLibraryVersion: r.InstrumentationScope().Version,
+		}
+		if tid := r.TraceID(); tid.IsValid() {
+			l.TraceID = tid.String()
+		}
+		if sid := r.SpanID(); sid.IsValid() {
+			l.SpanID = sid.String()
+		}
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			l.Attrs = append(l.Attrs, kv)
+			return true
+		})
+		res := r.Resource()
+		l.Resource = Attributes(res.Attributes())
+		logs = append(logs, l)
+	}
+	return logs
+}
+
+// unixNano returns t as nanoseconds since the epoch, or 0 if t is unset.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// logValueToInterface converts a log value to its JSON representation.
+func logValueToInterface(v log.Value) interface{} {
+	switch v.Kind() {
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindString:
+		return v.AsString()
+	case log.KindBytes:
+		return v.AsBytes()
+	case log.KindSlice:
+		values := make([]interface{}, 0, len(v.AsSlice()))
+		for _, item := range v.AsSlice() {
+			values = append(values, logValueToInterface(item))
+		}
+		return values
+	case log.KindMap:
+		m := make(map[string]interface{}, len(v.AsMap()))
+		for _, kv := range v.AsMap() {
+			m[kv.Key] = logValueToInterface(kv.Value)
+		}
+		return m
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/log/logtest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// newLogExporter creates a log exporter shut down at the end of the test.
+func newLogExporter(t *testing.T, collectorURL string, opts ...httpExporter.Option) *httpExporter.LogExporter {
+	t.Helper()
+	e, err := httpExporter.NewLogExporter(collectorURL, opts...)
+	if err != nil {
+		t.Fatalf("NewLogExporter: %v", err)
+	}
+	t.Cleanup(func() { _ = e.Shutdown(context.Background()) })
+	return e
+}
+
+// testRecords returns an error record emitted in a span of the checkout
+// service.
+func testRecords() []sdklog.Record {
+	sc := newSpanContext(newTraceID())
+	return []sdklog.Record{logtest.RecordFactory{
+		Timestamp:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Severity:     log.SeverityError,
+		SeverityText: "ERROR",
+		Body:         log.StringValue("payment declined"),
+		Attributes: []log.KeyValue{
+			log.Int("attempt", 2),
+			log.Map("card", log.String("brand", "visa"), log.Bool("expired", true)),
+		},
+		TraceID:              sc.TraceID(),
+		SpanID:               sc.SpanID(),
+		Resource:             resource.NewSchemaless(attribute.String("service.name", "checkout")),
+		InstrumentationScope: &instrumentation.Scope{Name: "github.com/example/checkout"},
+	}.NewRecord()}
+}
+
+func TestLogExporter(t *testing.T) {
+	c := newCollector(t)
+	e := newLogExporter(t, c.URL())
+	records := testRecords()
+	if err := e.Export(context.Background(), records); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(reqs))
+	}
+	if reqs[0].Path != "/v1/logs" {
+		t.Errorf("logs posted to %s, want /v1/logs", reqs[0].Path)
+	}
+	var logs []map[string]interface{}
+	if err := json.Unmarshal(reqs[0].Body, &logs); err != nil {
+		t.Fatalf("invalid logs %s: %v", reqs[0].Body, err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("got %d log records, want 1", len(logs))
+	}
+	l := logs[0]
+	if l["body"] != "payment declined" || l["severityText"] != "ERROR" || l["severityNumber"] != float64(log.SeverityError) {
+		t.Errorf("record body %v, severity %v %v", l["body"], l["severityText"], l["severityNumber"])
+	}
+	if l["traceId"] != records[0].TraceID().String() || l["spanId"] != records[0].SpanID().String() {
+		t.Errorf("record traceId %v, spanId %v", l["traceId"], l["spanId"])
+	}
+	if l["timestamp"] != float64(records[0].Timestamp().UnixNano()) {
+		t.Errorf("record timestamp %v", l["timestamp"])
+	}
+	attrs, _ := l["attrs"].(map[string]interface{})
+	card, _ := attrs["card"].(map[string]interface{})
+	if attrs["attempt"] != 2.0 || card["brand"] != "visa" || card["expired"] != true {
+		t.Errorf("record attributes %v", l["attrs"])
+	}
+	if res, _ := l["resource"].(map[string]interface{}); res["service.name"] != "checkout" {
+		t.Errorf("record resource %v", l["resource"])
+	}
+	if l["instrumentationLibraryName"] != "github.com/example/checkout" {
+		t.Errorf("record scope %v", l["instrumentationLibraryName"])
+	}
+}
+
+func TestLogExporterPath(t *testing.T) {
+	c := newCollector(t)
+	e := newLogExporter(t, c.URL(), httpExporter.WithLogsPath("/custom/logs"))
+	if err := e.Export(context.Background(), testRecords()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if reqs := c.received(); len(reqs) != 1 || reqs[0].Path != "/custom/logs" {
+		t.Errorf("collector received %+v, want a request to /custom/logs", reqs)
+	}
+}
+
+func TestLogExporterEmpty(t *testing.T) {
+	c := newCollector(t)
+	e := newLogExporter(t, c.URL())
+	if err := e.Export(context.Background(), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests for no records", got)
+	}
+}
+
+func TestLogExporterShutdown(t *testing.T) {
+	c := newCollector(t)
+	e := newLogExporter(t, c.URL())
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := e.Export(context.Background(), testRecords()); err != sdkmetric.ErrExporterShutdown {
+		t.Errorf("Export after Shutdown = %v, want ErrExporterShutdown", err)
+	}
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests after Shutdown", got)
+	}
+}
+
+func TestLogExporterWithoutSpanPipeline(t *testing.T) {
+	c := newCollector(t)
+	newLogExporter(t, c.URL(), httpExporter.WithStartupCheck(context.Background()))
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests before any export", got)
+	}
+}
+
+func TestLogExporterPathPrefix(t *testing.T) {
+	c := newCollector(t)
+	e := newLogExporter(t, c.URL()+"/otel/v1/traces", httpExporter.WithLogsPath("logs"))
+	if err := e.Export(context.Background(), testRecords()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if reqs := c.received(); len(reqs) != 1 || reqs[0].Path != "/otel/logs" {
+		t.Errorf("collector received %+v, want a request to /otel/logs", reqs)
+	}
+}
+
+func TestLogExporterFormat(t *testing.T) {
+	if _, err := httpExporter.NewLogExporter("http://localhost:4318", httpExporter.WithFormat(httpExporter.Zipkin)); err == nil {
+		t.Error("NewLogExporter succeeded with the Zipkin format")
+	}
+}
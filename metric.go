@@ -0,0 +1,340 @@
+package httpExporter
+
+import (
+	"context"
+	"encoding/json"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// defaultMetricsPath is the collector path metrics are posted to when none
+// is configured.
+const defaultMetricsPath = "/v1/metrics"
+
+// MetricExporter implements the sdk/metric Exporter interface, posting
+// metrics as JSON to the same collector and with the same options as the
+// span Exporter.
+type MetricExporter struct {
+	exporter *Exporter
+	endpoint *endpoint
+
+	temporalitySelector sdkmetric.TemporalitySelector
+}
+
+var (
+	_ sdkmetric.Exporter = &MetricExporter{}
+)
+
+// WithMetricsPath configures the collector URL path metrics are posted to.
+// Defaults to /v1/metrics. It is joined to the path of the collector URL,
+// less a trailing /v1/traces.
+func WithMetricsPath(path string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.metricsPath = path
+		return cfg
+	})
+}
+
+// WithTemporalitySelector configures the temporality used for each metric
+// instrument kind. Defaults to cumulative for all instruments.
+func WithTemporalitySelector(selector sdkmetric.TemporalitySelector) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.temporalitySelector = selector
+		return cfg
+	})
+}
+
+// NewMetricExporter creates a metric exporter for the collector at
+// collectorURL. It accepts the same options as New, but only the client,
+// request and retry ones apply: the span pipeline, such as the queue,
+// persistence or tail sampling, is not started. Metrics are always encoded
+// as JSON, so other formats are rejected.
+func NewMetricExporter(collectorURL string, opts ...Option) (*MetricExporter, error) {
+	cfg := newConfig(opts...)
+	cfg.signal = "metrics"
+	exp, cfg, err := newDeliveryExporter(collectorURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := newEndpoint(cfg.collectorURL, cfg, exp.encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.metricsPath == "" {
+		cfg.metricsPath = defaultMetricsPath
+	}
+	if cfg.temporalitySelector == nil {
+		cfg.temporalitySelector = sdkmetric.DefaultTemporalitySelector
+	}
+
+	if ep.url, err = signalURL(ep.url, cfg.metricsPath); err != nil {
+		return nil, err
+	}
+	exp.endpoints = []*endpoint{ep}
+	exp.url = ep.url
+	return &MetricExporter{
+		exporter:            exp,
+		endpoint:            ep,
+		temporalitySelector: cfg.temporalitySelector,
+	}, nil
+}
+
+// Temporality returns the Temporality to use for an instrument kind.
+func (e *MetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.temporalitySelector(kind)
+}
+
+// Aggregation returns the Aggregation to use for an instrument kind.
+func (e *MetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+// Export metrics to the collector
+func (e *MetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	if !e.exporter.begin() {
+		return sdkmetric.ErrExporterShutdown
+	}
+	defer e.exporter.end()
+
+	metrics := convertMetricsToHttp(rm)
+	if len(metrics) == 0 {
+		e.exporter.logf("no metrics to export")
+		return nil
+	}
+	body, err := json.Marshal(&metrics)
+	if err != nil {
+		return e.exporter.errf("unable to serialize metric data")
+	}
+	return e.exporter.deliver(ctx, e.endpoint, "application/json", body)
+}
+
+// ForceFlush does nothing, metrics are not buffered by the exporter.
+func (e *MetricExporter) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Shutdown stops the exporter.
+func (e *MetricExporter) Shutdown(ctx context.Context) error {
+	return e.exporter.Shutdown(ctx)
+}
+
+// MarshalLog is the marshaling function used by the logging system to represent this exporter.
+func (e *MetricExporter) MarshalLog() interface{} {
+	return struct {
+		Type string
+		URL  string
+	}{
+		Type: "http_metric",
+		URL:  e.endpoint.url,
+	}
+}
+
+// MetricData contains a metric and its data points.
+type MetricData struct {
+	Name                          string            `json:"name"`
+	Description                   string            `json:"description,omitempty"`
+	Unit                          string            `json:"unit,omitempty"`
+	Type                          string            `json:"type"`                  // gauge, sum, histogram, exponentialHistogram or summary
+	Temporality                   string            `json:"temporality,omitempty"` // cumulative or delta
+	IsMonotonic                   bool              `json:"isMonotonic,omitempty"`
+	DataPoints                    []MetricDataPoint `json:"dataPoints"`
+	InstrumentationLibraryName    string            `json:"instrumentationLibraryName"` // Instrumentation library that created the metric
+	InstrumentationLibraryVersion string            `json:"instrumentationLibraryVersion"`
+	Resource                      Attributes        `json:"resource,omitempty"` // Contains attributes representing an entity that produced this metric
+}
+
+// MetricDataPoint is a single point of a metric. Gauges and sums use Value,
+// histograms and summaries the aggregate fields.
+type MetricDataPoint struct {
+	Attrs           Attributes          `json:"attrs"`     // A collection of key-value pairs
+	StartTime       int64               `json:"startTime"` // Start of the aggregation period
+	Time            int64               `json:"time"`      // Time the point was recorded
+	Value           interface{}         `json:"value,omitempty"`
+	Count           uint64              `json:"count,omitempty"`
+	Sum             interface{}         `json:"sum,omitempty"`
+	Min             interface{}         `json:"min,omitempty"`
+	Max             interface{}         `json:"max,omitempty"`
+	Bounds          []float64           `json:"bounds,omitempty"`
+	BucketCounts    []uint64            `json:"bucketCounts,omitempty"`
+	Scale           int32               `json:"scale,omitempty"`
+	ZeroCount       uint64              `json:"zeroCount,omitempty"`
+	PositiveBuckets *ExponentialBuckets `json:"positiveBuckets,omitempty"`
+	NegativeBuckets *ExponentialBuckets `json:"negativeBuckets,omitempty"`
+	QuantileValues  []QuantileValue     `json:"quantileValues,omitempty"`
+}
+
+// ExponentialBuckets contains the buckets of an exponential histogram.
+type ExponentialBuckets struct {
+	Offset int32    `json:"offset"`
+	Counts []uint64 `json:"counts"`
+}
+
+// QuantileValue is the value of a quantile of a summary.
+type QuantileValue struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+func convertMetricsToHttp(rm *metricdata.ResourceMetrics) []MetricData {
+	metrics := []MetricData{}
+	resource := Attributes(rm.Resource.Attributes())
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			md := MetricData{
+				Name:                          m.Name,
+				Description:                   m.Description,
+				Unit:                          m.Unit,
+				InstrumentationLibraryName:    sm.Scope.Name,
+				InstrumentationLibraryVersion: sm.Scope.Version,
+				Resource:                      resource,
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				md.Type = "gauge"
+				md.DataPoints = dataPointsToSlice(data.DataPoints)
+			case metricdata.Gauge[float64]:
+				md.Type = "gauge"
+				md.DataPoints = dataPointsToSlice(data.DataPoints)
+			case metricdata.Sum[int64]:
+				md.Type = "sum"
+				md.Temporality = temporalityString(data.Temporality)
+				md.IsMonotonic = data.IsMonotonic
+				md.DataPoints = dataPointsToSlice(data.DataPoints)
+			case metricdata.Sum[float64]:
+				md.Type = "sum"
+				md.Temporality = temporalityString(data.Temporality)
+				md.IsMonotonic = data.IsMonotonic
+				md.DataPoints = dataPointsToSlice(data.DataPoints)
+			case metricdata.Histogram[int64]:
+				md.Type = "histogram"
+				md.Temporality = temporalityString(data.Temporality)
+				md.DataPoints = histogramDataPointsToSlice(data.DataPoints)
+			case metricdata.Histogram[float64]:
+				md.Type = "histogram"
+				md.Temporality = temporalityString(data.Temporality)
+				md.DataPoints = histogramDataPointsToSlice(data.DataPoints)
+			case metricdata.ExponentialHistogram[int64]:
+				md.Type = "exponentialHistogram"
+				md.Temporality = temporalityString(data.Temporality)
+				md.DataPoints = exponentialHistogramDataPointsToSlice(data.DataPoints)
+			case metricdata.ExponentialHistogram[float64]:
+				md.Type = "exponentialHistogram"
+				md.Temporality = temporalityString(data.Temporality)
+				md.DataPoints = exponentialHistogramDataPointsToSlice(data.DataPoints)
+			case metricdata.Summary:
+				md.Type = "summary"
+				md.DataPoints = summaryDataPointsToSlice(data.DataPoints)
+			default:
+				continue
+			}
+			metrics = append(metrics, md)
+		}
+	}
+	return metrics
+}
+
+// temporalityString returns the JSON name of a temporality.
+func temporalityString(t metricdata.Temporality) string {
+	switch t {
+	case metricdata.CumulativeTemporality:
+		return "cumulative"
+	case metricdata.DeltaTemporality:
+		return "delta"
+	}
+	return ""
+}
+
+// dataPointsToSlice converts gauge and sum data points for exporting
+func dataPointsToSlice[N int64 | float64](dps []metricdata.DataPoint[N]) []MetricDataPoint {
+	points := make([]MetricDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		points = append(points, MetricDataPoint{
+			Attrs:     Attributes(dp.Attributes.ToSlice()),
+			StartTime: dp.StartTime.UnixNano(),
+			Time:      dp.Time.UnixNano(),
+			Value:     dp.Value,
+		})
+	}
+	return points
+}
+
+// histogramDataPointsToSlice converts histogram data points for exporting
+func histogramDataPointsToSlice[N int64 | float64](dps []metricdata.HistogramDataPoint[N]) []MetricDataPoint {
+	points := make([]MetricDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		p := MetricDataPoint{
+			Attrs:        Attributes(dp.Attributes.ToSlice()),
+			StartTime:    dp.StartTime.UnixNano(),
+			Time:         dp.Time.UnixNano(),
+			Count:        dp.Count,
+			Sum:          dp.Sum,
+			Bounds:       dp.Bounds,
+			BucketCounts: dp.BucketCounts,
+		}
+		if v, ok := dp.Min.Value(); ok {
+			p.Min = v
+		}
+		if v, ok := dp.Max.Value(); ok {
+			p.Max = v
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// exponentialHistogramDataPointsToSlice converts exponential histogram data
+// points for exporting
+func exponentialHistogramDataPointsToSlice[N int64 | float64](dps []metricdata.ExponentialHistogramDataPoint[N]) []MetricDataPoint {
+	points := make([]MetricDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		p := MetricDataPoint{
+			Attrs:     Attributes(dp.Attributes.ToSlice()),
+			StartTime: dp.StartTime.UnixNano(),
+			Time:      dp.Time.UnixNano(),
+			Count:     dp.Count,
+			Sum:       dp.Sum,
+			Scale:     dp.Scale,
+			ZeroCount: dp.ZeroCount,
+			PositiveBuckets: &ExponentialBuckets{
+				Offset: dp.PositiveBucket.Offset,
+				Counts: dp.PositiveBucket.Counts,
+			},
+			NegativeBuckets: &ExponentialBuckets{
+				Offset: dp.NegativeBucket.Offset,
+				Counts: dp.NegativeBucket.Counts,
+			},
+		}
+		if v, ok := dp.Min.Value(); ok {
+			p.Min = v
+		}
+		if v, ok := dp.Max.Value(); ok {
+			p.Max = v
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// summaryDataPointsToSlice converts summary data points for exporting
+func summaryDataPointsToSlice(dps []metricdata.SummaryDataPoint) []MetricDataPoint {
+	points := make([]MetricDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		p := MetricDataPoint{
+			Attrs:     Attributes(dp.Attributes.ToSlice()),
+			StartTime: dp.StartTime.UnixNano(),
+			Time:      dp.Time.UnixNano(),
+			Count:     dp.Count,
+			Sum:       dp.Sum,
+		}
+		for _, qv := range dp.QuantileValues {
+			p.QuantileValues = append(p.QuantileValues, QuantileValue{
+				Quantile: qv.Quantile,
+				Value:    qv.Value,
+			})
+		}
+		points = append(points, p)
+	}
+	return points
+}
@@ -0,0 +1,184 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// newMetricExporter creates a metric exporter shut down at the end of the
+// test.
+func newMetricExporter(t *testing.T, collectorURL string, opts ...httpExporter.Option) *httpExporter.MetricExporter {
+	t.Helper()
+	e, err := httpExporter.NewMetricExporter(collectorURL, opts...)
+	if err != nil {
+		t.Fatalf("NewMetricExporter: %v", err)
+	}
+	t.Cleanup(func() { _ = e.Shutdown(context.Background()) })
+	return e
+}
+
+// testMetrics returns a counter and a histogram of the checkout service.
+func testMetrics() *metricdata.ResourceMetrics {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(time.Minute)
+	attrs := attribute.NewSet(attribute.String("route", "/cart"))
+	return &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attribute.String("service.name", "checkout")),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope: instrumentation.Scope{Name: "github.com/example/checkout", Version: "1.2.0"},
+			Metrics: []metricdata.Metrics{
+				{
+					Name: "http.requests",
+					Unit: "{request}",
+					Data: metricdata.Sum[int64]{
+						Temporality: metricdata.CumulativeTemporality,
+						IsMonotonic: true,
+						DataPoints: []metricdata.DataPoint[int64]{{
+							Attributes: attrs, StartTime: start, Time: now, Value: 42,
+						}},
+					},
+				},
+				{
+					Name: "http.duration",
+					Unit: "ms",
+					Data: metricdata.Histogram[float64]{
+						Temporality: metricdata.DeltaTemporality,
+						DataPoints: []metricdata.HistogramDataPoint[float64]{{
+							Attributes:   attrs,
+							StartTime:    start,
+							Time:         now,
+							Count:        3,
+							Sum:          37.5,
+							Bounds:       []float64{10, 100},
+							BucketCounts: []uint64{1, 2, 0},
+							Min:          metricdata.NewExtrema(2.5),
+							Max:          metricdata.NewExtrema(20.0),
+						}},
+					},
+				},
+			},
+		}},
+	}
+}
+
+func TestMetricExporter(t *testing.T) {
+	c := newCollector(t)
+	e := newMetricExporter(t, c.URL())
+	if err := e.Export(context.Background(), testMetrics()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(reqs))
+	}
+	if reqs[0].Path != "/v1/metrics" {
+		t.Errorf("metrics posted to %s, want /v1/metrics", reqs[0].Path)
+	}
+	var metrics []httpExporter.MetricData
+	if err := json.Unmarshal(reqs[0].Body, &metrics); err != nil {
+		t.Fatalf("invalid metrics %s: %v", reqs[0].Body, err)
+	}
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics, want 2", len(metrics))
+	}
+
+	sum := metrics[0]
+	if sum.Name != "http.requests" || sum.Type != "sum" || sum.Temporality != "cumulative" || !sum.IsMonotonic {
+		t.Errorf("sum = %+v", sum)
+	}
+	if sum.InstrumentationLibraryName != "github.com/example/checkout" || len(sum.Resource) != 1 {
+		t.Errorf("sum scope %q, resource %v", sum.InstrumentationLibraryName, sum.Resource)
+	}
+	if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 42.0 || len(sum.DataPoints[0].Attrs) != 1 {
+		t.Errorf("sum data points = %+v", sum.DataPoints)
+	}
+
+	hist := metrics[1]
+	if hist.Type != "histogram" || hist.Temporality != "delta" || len(hist.DataPoints) != 1 {
+		t.Fatalf("histogram = %+v", hist)
+	}
+	p := hist.DataPoints[0]
+	if p.Count != 3 || p.Sum != 37.5 || p.Min != 2.5 || p.Max != 20.0 {
+		t.Errorf("histogram point count %d, sum %v, min %v, max %v", p.Count, p.Sum, p.Min, p.Max)
+	}
+	if len(p.Bounds) != 2 || len(p.BucketCounts) != 3 || p.BucketCounts[1] != 2 {
+		t.Errorf("histogram bounds %v, bucket counts %v", p.Bounds, p.BucketCounts)
+	}
+}
+
+func TestMetricExporterOptions(t *testing.T) {
+	c := newCollector(t)
+	e := newMetricExporter(t, c.URL(),
+		httpExporter.WithMetricsPath("/custom/metrics"),
+		httpExporter.WithHeaders(map[string]string{"X-Tenant": "shop"}),
+		httpExporter.WithTemporalitySelector(func(sdkmetric.InstrumentKind) metricdata.Temporality {
+			return metricdata.DeltaTemporality
+		}),
+	)
+	if got := e.Temporality(sdkmetric.InstrumentKindCounter); got != metricdata.DeltaTemporality {
+		t.Errorf("Temporality = %v, want delta", got)
+	}
+	if err := e.Export(context.Background(), testMetrics()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	reqs := c.received()
+	if len(reqs) != 1 || reqs[0].Path != "/custom/metrics" || reqs[0].Header.Get("X-Tenant") != "shop" {
+		t.Errorf("collector received %+v, want a request to /custom/metrics with the configured header", reqs)
+	}
+}
+
+func TestMetricExporterShutdown(t *testing.T) {
+	c := newCollector(t)
+	e := newMetricExporter(t, c.URL())
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := e.Export(context.Background(), testMetrics()); err != sdkmetric.ErrExporterShutdown {
+		t.Errorf("Export after Shutdown = %v, want ErrExporterShutdown", err)
+	}
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests after Shutdown", got)
+	}
+}
+
+func TestMetricExporterWithoutSpanPipeline(t *testing.T) {
+	c := newCollector(t)
+	// The startup check belongs to the span pipeline: it must not send an
+	// empty span batch on behalf of the metric exporter.
+	newMetricExporter(t, c.URL(), httpExporter.WithStartupCheck(context.Background()))
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests before any export", got)
+	}
+}
+
+func TestMetricExporterPathPrefix(t *testing.T) {
+	for _, path := range []string{"/otel", "/otel/", "/otel/v1/traces"} {
+		c := newCollector(t)
+		e := newMetricExporter(t, c.URL()+path)
+		if err := e.Export(context.Background(), testMetrics()); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+		if reqs := c.received(); len(reqs) != 1 || reqs[0].Path != "/otel/v1/metrics" {
+			t.Errorf("collector URL path %s: collector received %+v, want a request to /otel/v1/metrics", path, reqs)
+		}
+	}
+}
+
+func TestMetricExporterFormat(t *testing.T) {
+	_, err := httpExporter.NewMetricExporter("http://localhost:4318", httpExporter.WithFormat(httpExporter.OTLPProtobuf))
+	var invalid *httpExporter.ErrInvalidConfig
+	if !errors.As(err, &invalid) {
+		t.Errorf("NewMetricExporter with the OTLP protobuf format = %v, want an ErrInvalidConfig", err)
+	}
+}
@@ -0,0 +1,159 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// newRelicTracePath is the New Relic Trace API path.
+	newRelicTracePath = "/trace/v1"
+	// newRelicAPIKeyHeader is the header carrying the New Relic license or
+	// API key.
+	newRelicAPIKeyHeader = "Api-Key"
+)
+
+// New Relic Trace API limits. Attributes with longer names are dropped and
+// longer string values truncated.
+const (
+	newRelicMaxPayloadBytes = 1000000
+	newRelicMaxKeyLength    = 255
+	newRelicMaxValueLength  = 4095
+	newRelicMaxAttributes   = 254
+)
+
+// newRelicEncoder encodes spans as a New Relic Trace API payload in the
+// newrelic format, with one block of common attributes per resource.
+type newRelicEncoder struct{}
+
+func (newRelicEncoder) contentType() string { return "application/json" }
+
+func (newRelicEncoder) defaultPath() string { return newRelicTracePath }
+
+func (newRelicEncoder) headers() map[string]string {
+	return map[string]string{
+		"Data-Format":         "newrelic",
+		"Data-Format-Version": "1",
+	}
+}
+
+func (newRelicEncoder) maxPayloadBytes() int { return newRelicMaxPayloadBytes }
+
+func (newRelicEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var batches []newRelicBatch
+	index := make(map[attribute.Distinct]int)
+	for _, span := range spans {
+		res := span.Resource()
+		i, ok := index[res.Equivalent()]
+		if !ok {
+			i = len(batches)
+			index[res.Equivalent()] = i
+			batches = append(batches, newRelicBatch{
+				Common: newRelicCommon{Attributes: newRelicAttributes(res.Attributes(), nil)},
+			})
+		}
+		batches[i].Spans = append(batches[i].Spans, spanToNewRelic(span))
+	}
+	return json.Marshal(batches)
+}
+
+type newRelicBatch struct {
+	Common newRelicCommon `json:"common"`
+	Spans  []newRelicSpan `json:"spans"`
+}
+
+type newRelicCommon struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+type newRelicSpan struct {
+	TraceID    string                 `json:"trace.id"`
+	ID         string                 `json:"id"`
+	Timestamp  int64                  `json:"timestamp"` // Epoch milliseconds
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// spanToNewRelic converts a span to a New Relic span. Span events and links
+// have no equivalent in the newrelic format and are not sent.
+func spanToNewRelic(span sdktrace.ReadOnlySpan) newRelicSpan {
+	sc := span.SpanContext()
+	attrs := map[string]interface{}{
+		"name":        span.Name(),
+		"duration.ms": durationMs(span.EndTime().Sub(span.StartTime())),
+		"span.kind":   span.SpanKind().String(),
+	}
+	if psid := span.Parent().SpanID(); psid.IsValid() {
+		attrs["parent.id"] = psid.String()
+	}
+	if name := serviceName(span.Resource()); name != "" {
+		attrs["service.name"] = name
+	}
+	if lib := span.InstrumentationLibrary(); lib.Name != "" {
+		attrs["otel.library.name"] = lib.Name
+		if lib.Version != "" {
+			attrs["otel.library.version"] = lib.Version
+		}
+	}
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		attrs["otel.status_code"] = "OK"
+	case codes.Error:
+		attrs["otel.status_code"] = "ERROR"
+		attrs["error"] = true
+		if status.Description != "" {
+			attrs["otel.status_description"] = status.Description
+			attrs["error.message"] = newRelicValue(attribute.StringValue(status.Description))
+		}
+	}
+	return newRelicSpan{
+		TraceID:    sc.TraceID().String(),
+		ID:         sc.SpanID().String(),
+		Timestamp:  span.StartTime().UnixNano() / 1e6,
+		Attributes: newRelicAttributes(span.Attributes(), attrs),
+	}
+}
+
+// newRelicAttributes adds attributes to fields, which take precedence, within
+// the New Relic limits.
+func newRelicAttributes(attrs []attribute.KeyValue, fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		fields = make(map[string]interface{}, len(attrs))
+	}
+	for _, kv := range attrs {
+		key := string(kv.Key)
+		if utf8.RuneCountInString(key) > newRelicMaxKeyLength || len(fields) >= newRelicMaxAttributes {
+			continue
+		}
+		if _, ok := fields[key]; !ok {
+			fields[key] = newRelicValue(kv.Value)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// newRelicValue converts an attribute value. Slices are encoded as JSON
+// arrays in strings and strings truncated to the New Relic limit.
+func newRelicValue(v attribute.Value) interface{} {
+	switch v.Type() {
+	case attribute.BOOL, attribute.INT64, attribute.FLOAT64:
+		return v.AsInterface()
+	}
+	s := attributeValueString(v)
+	if utf8.RuneCountInString(s) > newRelicMaxValueLength {
+		s = string([]rune(s)[:newRelicMaxValueLength])
+	}
+	return s
+}
+
+// WithNewRelicAPIKey configures the license or API key sent in the Api-Key
+// header, as required by the New Relic Trace API.
+func WithNewRelicAPIKey(key string) Option {
+	return WithHeaders(map[string]string{newRelicAPIKeyHeader: key})
+}
@@ -0,0 +1,37 @@
+package httpExporter
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestNewRelic(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(NewRelic))
+	checkEncoder(t, enc, "application/json", "/trace/v1")
+	checkGoldenJSON(t, "newrelic.json", body)
+
+	headers := enc.(headerEncoder).headers()
+	if headers["Data-Format"] != "newrelic" || headers["Data-Format-Version"] != "1" {
+		t.Errorf("headers = %v", headers)
+	}
+	if got := enc.(payloadLimitEncoder).maxPayloadBytes(); got != 1000000 {
+		t.Errorf("payload limit = %d, want 1000000", got)
+	}
+}
+
+func TestNewRelicLimits(t *testing.T) {
+	attrs := []attribute.KeyValue{
+		attribute.String(strings.Repeat("k", 256), "dropped"),
+		attribute.String("long", strings.Repeat("é", 5000)),
+	}
+	fields := newRelicAttributes(attrs, nil)
+	if len(fields) != 1 {
+		t.Fatalf("got %d attributes, want the one with a long name dropped", len(fields))
+	}
+	if n := utf8.RuneCountInString(fields["long"].(string)); n != 4095 {
+		t.Errorf("long value truncated to %d characters, want 4095", n)
+	}
+}
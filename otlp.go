@@ -0,0 +1,190 @@
+package httpExporter
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpTracesPath is the standard OTLP/HTTP traces endpoint path.
+const otlpTracesPath = "/v1/traces"
+
+// otlpProtoEncoder encodes spans as an OTLP ExportTraceServiceRequest.
+type otlpProtoEncoder struct{}
+
+func (otlpProtoEncoder) contentType() string { return "application/x-protobuf" }
+
+func (otlpProtoEncoder) defaultPath() string { return otlpTracesPath }
+
+func (otlpProtoEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	// ExportTraceServiceRequest and TracesData share the same wire
+	// representation (field 1: repeated ResourceSpans). Marshaling TracesData
+	// avoids pulling the gRPC collector service package into the build.
+	return proto.Marshal(&tracepb.TracesData{
+		ResourceSpans: spansToOTLP(spans),
+	})
+}
+
+// spansToOTLP converts spans to OTLP ResourceSpans, grouping them by
+// resource and instrumentation scope.
+func spansToOTLP(spans []sdktrace.ReadOnlySpan) []*tracepb.ResourceSpans {
+	type scopeKey struct {
+		resource attribute.Distinct
+		scope    instrumentation.Library
+	}
+	var rss []*tracepb.ResourceSpans
+	resources := make(map[attribute.Distinct]*tracepb.ResourceSpans)
+	scopes := make(map[scopeKey]*tracepb.ScopeSpans)
+	for _, span := range spans {
+		res := span.Resource()
+		rKey := res.Equivalent()
+		rs, ok := resources[rKey]
+		if !ok {
+			rs = &tracepb.ResourceSpans{
+				Resource: &resourcepb.Resource{
+					Attributes: attributesToOTLP(res.Attributes()),
+				},
+				SchemaUrl: res.SchemaURL(),
+			}
+			resources[rKey] = rs
+			rss = append(rss, rs)
+		}
+
+		lib := span.InstrumentationLibrary()
+		sKey := scopeKey{resource: rKey, scope: lib}
+		ss, ok := scopes[sKey]
+		if !ok {
+			ss = &tracepb.ScopeSpans{
+				Scope: &commonpb.InstrumentationScope{
+					Name:    lib.Name,
+					Version: lib.Version,
+				},
+				SchemaUrl: lib.SchemaURL,
+			}
+			scopes[sKey] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, spanToOTLP(span))
+	}
+	return rss
+}
+
+// spanToOTLP converts a single span to its OTLP representation.
+func spanToOTLP(span sdktrace.ReadOnlySpan) *tracepb.Span {
+	sc := span.SpanContext()
+	tid := sc.TraceID()
+	sid := sc.SpanID()
+	s := &tracepb.Span{
+		TraceId:                tid[:],
+		SpanId:                 sid[:],
+		TraceState:             sc.TraceState().String(),
+		Name:                   span.Name(),
+		Kind:                   tracepb.Span_SpanKind(span.SpanKind()),
+		StartTimeUnixNano:      uint64(span.StartTime().UnixNano()),
+		EndTimeUnixNano:        uint64(span.EndTime().UnixNano()),
+		Attributes:             attributesToOTLP(span.Attributes()),
+		DroppedAttributesCount: uint32(span.DroppedAttributes()),
+		DroppedEventsCount:     uint32(span.DroppedEvents()),
+		DroppedLinksCount:      uint32(span.DroppedLinks()),
+		Status: &tracepb.Status{
+			Code:    statusCodeToOTLP(span.Status().Code),
+			Message: span.Status().Description,
+		},
+	}
+	if psid := span.Parent().SpanID(); psid.IsValid() {
+		s.ParentSpanId = psid[:]
+	}
+	for _, ev := range span.Events() {
+		s.Events = append(s.Events, &tracepb.Span_Event{
+			TimeUnixNano:           uint64(ev.Time.UnixNano()),
+			Name:                   ev.Name,
+			Attributes:             attributesToOTLP(ev.Attributes),
+			DroppedAttributesCount: uint32(ev.DroppedAttributeCount),
+		})
+	}
+	for _, l := range span.Links() {
+		ltid := l.SpanContext.TraceID()
+		lsid := l.SpanContext.SpanID()
+		s.Links = append(s.Links, &tracepb.Span_Link{
+			TraceId:                ltid[:],
+			SpanId:                 lsid[:],
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             attributesToOTLP(l.Attributes),
+			DroppedAttributesCount: uint32(l.DroppedAttributeCount),
+		})
+	}
+	return s
+}
+
+// statusCodeToOTLP maps an OpenTelemetry status code to its OTLP value.
+func statusCodeToOTLP(code codes.Code) tracepb.Status_StatusCode {
+	switch code {
+	case codes.Ok:
+		return tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		return tracepb.Status_STATUS_CODE_ERROR
+	}
+	return tracepb.Status_STATUS_CODE_UNSET
+}
+
+// attributesToOTLP converts attributes to OTLP key-values.
+func attributesToOTLP(attributes []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+	kvs := make([]*commonpb.KeyValue, 0, len(attributes))
+	for _, kv := range attributes {
+		kvs = append(kvs, &commonpb.KeyValue{
+			Key:   string(kv.Key),
+			Value: valueToOTLP(kv.Value),
+		})
+	}
+	return kvs
+}
+
+// valueToOTLP converts an attribute value to an OTLP AnyValue.
+func valueToOTLP(v attribute.Value) *commonpb.AnyValue {
+	av := &commonpb.AnyValue{}
+	switch v.Type() {
+	case attribute.BOOL:
+		av.Value = &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}
+	case attribute.INT64:
+		av.Value = &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}
+	case attribute.FLOAT64:
+		av.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}
+	case attribute.STRING:
+		av.Value = &commonpb.AnyValue_StringValue{StringValue: v.AsString()}
+	case attribute.BOOLSLICE:
+		var values []*commonpb.AnyValue
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: b}})
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		var values []*commonpb.AnyValue
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}})
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		var values []*commonpb.AnyValue
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}})
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		var values []*commonpb.AnyValue
+		for _, s := range v.AsStringSlice() {
+			values = append(values, &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s}})
+		}
+		av.Value = &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}
+	default:
+		av.Value = &commonpb.AnyValue_StringValue{StringValue: v.Emit()}
+	}
+	return av
+}
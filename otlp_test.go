@@ -0,0 +1,75 @@
+package httpExporter
+
+import (
+	"bytes"
+	"testing"
+
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLPProtobuf(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(OTLPProtobuf))
+	checkEncoder(t, enc, "application/x-protobuf", "/v1/traces")
+
+	var td tracepb.TracesData
+	if err := proto.Unmarshal(body, &td); err != nil {
+		t.Fatalf("invalid ExportTraceServiceRequest: %v", err)
+	}
+	if len(td.ResourceSpans) != 1 || len(td.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("got %d resources, want the spans grouped in a single resource and scope", len(td.ResourceSpans))
+	}
+	rs := td.ResourceSpans[0]
+	if attrs := rs.Resource.Attributes; len(attrs) != 2 || attrs[1].Key != "service.name" || attrs[1].Value.GetStringValue() != "checkout" {
+		t.Errorf("resource attributes = %v", attrs)
+	}
+	ss := rs.ScopeSpans[0]
+	if ss.Scope.Name != "github.com/example/checkout" || ss.Scope.Version != "1.2.0" {
+		t.Errorf("scope = %v", ss.Scope)
+	}
+	if len(ss.Spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(ss.Spans))
+	}
+
+	server, client := ss.Spans[0], ss.Spans[1]
+	if !bytes.Equal(server.TraceId, testTraceID[:]) || !bytes.Equal(server.SpanId, testServerID[:]) {
+		t.Errorf("server span IDs = %x/%x", server.TraceId, server.SpanId)
+	}
+	if server.ParentSpanId != nil {
+		t.Errorf("root span has parent %x", server.ParentSpanId)
+	}
+	if server.Kind != tracepb.Span_SPAN_KIND_SERVER {
+		t.Errorf("server span kind = %v", server.Kind)
+	}
+	if got, want := server.StartTimeUnixNano, uint64(testStart.UnixNano()); got != want {
+		t.Errorf("start time = %d, want %d", got, want)
+	}
+	if server.Status.Code != tracepb.Status_STATUS_CODE_ERROR || server.Status.Message != "cart unavailable" {
+		t.Errorf("server span status = %v", server.Status)
+	}
+	if len(server.Attributes) != 5 {
+		t.Fatalf("got %d attributes, want 5", len(server.Attributes))
+	}
+	if v := server.Attributes[1].Value.GetIntValue(); v != 500 {
+		t.Errorf("int attribute = %d, want 500", v)
+	}
+	if v := server.Attributes[2].Value.GetDoubleValue(); v != 12.5 {
+		t.Errorf("float attribute = %v, want 12.5", v)
+	}
+	if v := server.Attributes[4].Value.GetArrayValue().GetValues(); len(v) != 2 || v[1].GetStringValue() != "pear" {
+		t.Errorf("slice attribute = %v", v)
+	}
+	if len(server.Events) != 1 || server.Events[0].Name != "exception" || len(server.Events[0].Attributes) != 2 {
+		t.Errorf("events = %v", server.Events)
+	}
+	if len(server.Links) != 1 || !bytes.Equal(server.Links[0].TraceId, testLinkID[:]) {
+		t.Errorf("links = %v", server.Links)
+	}
+
+	if !bytes.Equal(client.ParentSpanId, testServerID[:]) {
+		t.Errorf("client span parent = %x, want %x", client.ParentSpanId, testServerID[:])
+	}
+	if client.Kind != tracepb.Span_SPAN_KIND_CLIENT || client.Status.Code != tracepb.Status_STATUS_CODE_OK {
+		t.Errorf("client span kind %v, status %v", client.Kind, client.Status)
+	}
+}
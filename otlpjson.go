@@ -0,0 +1,208 @@
+package httpExporter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// otlpJSONEncoder encodes spans following the OTLP/JSON encoding rules of
+// the OTLP specification: lowerCamelCase field names, hex-encoded trace and
+// span IDs, integer enum values and 64 bit integers as decimal strings.
+// protojson cannot be used directly since it base64-encodes IDs.
+type otlpJSONEncoder struct{}
+
+func (otlpJSONEncoder) contentType() string { return "application/json" }
+
+func (otlpJSONEncoder) defaultPath() string { return otlpTracesPath }
+
+func (otlpJSONEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	return json.Marshal(otlpJSONTracesData(spansToOTLP(spans)))
+}
+
+type otlpJSONRequest struct {
+	ResourceSpans []otlpJSONResourceSpans `json:"resourceSpans"`
+}
+
+type otlpJSONResourceSpans struct {
+	Resource   otlpJSONResource     `json:"resource"`
+	ScopeSpans []otlpJSONScopeSpans `json:"scopeSpans"`
+	SchemaURL  string               `json:"schemaUrl,omitempty"`
+}
+
+type otlpJSONResource struct {
+	Attributes             []otlpJSONKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount uint32             `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpJSONScopeSpans struct {
+	Scope     otlpJSONScope  `json:"scope"`
+	Spans     []otlpJSONSpan `json:"spans"`
+	SchemaURL string         `json:"schemaUrl,omitempty"`
+}
+
+type otlpJSONScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpJSONSpan struct {
+	TraceID                string             `json:"traceId"`
+	SpanID                 string             `json:"spanId"`
+	TraceState             string             `json:"traceState,omitempty"`
+	ParentSpanID           string             `json:"parentSpanId,omitempty"`
+	Name                   string             `json:"name"`
+	Kind                   int32              `json:"kind"`
+	StartTimeUnixNano      string             `json:"startTimeUnixNano"`
+	EndTimeUnixNano        string             `json:"endTimeUnixNano"`
+	Attributes             []otlpJSONKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount uint32             `json:"droppedAttributesCount,omitempty"`
+	Events                 []otlpJSONEvent    `json:"events,omitempty"`
+	DroppedEventsCount     uint32             `json:"droppedEventsCount,omitempty"`
+	Links                  []otlpJSONLink     `json:"links,omitempty"`
+	DroppedLinksCount      uint32             `json:"droppedLinksCount,omitempty"`
+	Status                 otlpJSONStatus     `json:"status"`
+}
+
+type otlpJSONEvent struct {
+	TimeUnixNano           string             `json:"timeUnixNano"`
+	Name                   string             `json:"name"`
+	Attributes             []otlpJSONKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount uint32             `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpJSONLink struct {
+	TraceID                string             `json:"traceId"`
+	SpanID                 string             `json:"spanId"`
+	TraceState             string             `json:"traceState,omitempty"`
+	Attributes             []otlpJSONKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount uint32             `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpJSONStatus struct {
+	Code    int32  `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpJSONKeyValue struct {
+	Key   string           `json:"key"`
+	Value otlpJSONAnyValue `json:"value"`
+}
+
+type otlpJSONAnyValue struct {
+	StringValue *string             `json:"stringValue,omitempty"`
+	BoolValue   *bool               `json:"boolValue,omitempty"`
+	IntValue    *string             `json:"intValue,omitempty"`
+	DoubleValue *float64            `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpJSONArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpJSONArrayValue struct {
+	Values []otlpJSONAnyValue `json:"values"`
+}
+
+// otlpJSONTracesData converts OTLP ResourceSpans to their JSON form.
+func otlpJSONTracesData(rss []*tracepb.ResourceSpans) otlpJSONRequest {
+	req := otlpJSONRequest{ResourceSpans: make([]otlpJSONResourceSpans, 0, len(rss))}
+	for _, rs := range rss {
+		jrs := otlpJSONResourceSpans{
+			Resource: otlpJSONResource{
+				Attributes:             otlpJSONAttributes(rs.GetResource().GetAttributes()),
+				DroppedAttributesCount: rs.GetResource().GetDroppedAttributesCount(),
+			},
+			SchemaURL: rs.SchemaUrl,
+		}
+		for _, ss := range rs.ScopeSpans {
+			jss := otlpJSONScopeSpans{
+				Scope: otlpJSONScope{
+					Name:    ss.GetScope().GetName(),
+					Version: ss.GetScope().GetVersion(),
+				},
+				Spans:     make([]otlpJSONSpan, 0, len(ss.Spans)),
+				SchemaURL: ss.SchemaUrl,
+			}
+			for _, s := range ss.Spans {
+				jss.Spans = append(jss.Spans, otlpJSONSpanData(s))
+			}
+			jrs.ScopeSpans = append(jrs.ScopeSpans, jss)
+		}
+		req.ResourceSpans = append(req.ResourceSpans, jrs)
+	}
+	return req
+}
+
+func otlpJSONSpanData(s *tracepb.Span) otlpJSONSpan {
+	js := otlpJSONSpan{
+		TraceID:                hex.EncodeToString(s.TraceId),
+		SpanID:                 hex.EncodeToString(s.SpanId),
+		TraceState:             s.TraceState,
+		ParentSpanID:           hex.EncodeToString(s.ParentSpanId),
+		Name:                   s.Name,
+		Kind:                   int32(s.Kind),
+		StartTimeUnixNano:      strconv.FormatUint(s.StartTimeUnixNano, 10),
+		EndTimeUnixNano:        strconv.FormatUint(s.EndTimeUnixNano, 10),
+		Attributes:             otlpJSONAttributes(s.Attributes),
+		DroppedAttributesCount: s.DroppedAttributesCount,
+		DroppedEventsCount:     s.DroppedEventsCount,
+		DroppedLinksCount:      s.DroppedLinksCount,
+		Status: otlpJSONStatus{
+			Code:    int32(s.GetStatus().GetCode()),
+			Message: s.GetStatus().GetMessage(),
+		},
+	}
+	for _, ev := range s.Events {
+		js.Events = append(js.Events, otlpJSONEvent{
+			TimeUnixNano:           strconv.FormatUint(ev.TimeUnixNano, 10),
+			Name:                   ev.Name,
+			Attributes:             otlpJSONAttributes(ev.Attributes),
+			DroppedAttributesCount: ev.DroppedAttributesCount,
+		})
+	}
+	for _, l := range s.Links {
+		js.Links = append(js.Links, otlpJSONLink{
+			TraceID:                hex.EncodeToString(l.TraceId),
+			SpanID:                 hex.EncodeToString(l.SpanId),
+			TraceState:             l.TraceState,
+			Attributes:             otlpJSONAttributes(l.Attributes),
+			DroppedAttributesCount: l.DroppedAttributesCount,
+		})
+	}
+	return js
+}
+
+func otlpJSONAttributes(kvs []*commonpb.KeyValue) []otlpJSONKeyValue {
+	if len(kvs) == 0 {
+		return nil
+	}
+	out := make([]otlpJSONKeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		out = append(out, otlpJSONKeyValue{Key: kv.Key, Value: otlpJSONValue(kv.Value)})
+	}
+	return out
+}
+
+func otlpJSONValue(v *commonpb.AnyValue) otlpJSONAnyValue {
+	var jv otlpJSONAnyValue
+	switch x := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		jv.StringValue = &x.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		jv.BoolValue = &x.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		i := strconv.FormatInt(x.IntValue, 10)
+		jv.IntValue = &i
+	case *commonpb.AnyValue_DoubleValue:
+		jv.DoubleValue = &x.DoubleValue
+	case *commonpb.AnyValue_ArrayValue:
+		arr := &otlpJSONArrayValue{Values: make([]otlpJSONAnyValue, 0, len(x.ArrayValue.GetValues()))}
+		for _, av := range x.ArrayValue.GetValues() {
+			arr.Values = append(arr.Values, otlpJSONValue(av))
+		}
+		jv.ArrayValue = arr
+	}
+	return jv
+}
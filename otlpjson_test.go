@@ -0,0 +1,9 @@
+package httpExporter
+
+import "testing"
+
+func TestOTLPJSON(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(OTLPJSON))
+	checkEncoder(t, enc, "application/json", "/v1/traces")
+	checkGoldenJSON(t, "otlp.json", body)
+}
@@ -0,0 +1,191 @@
+package httpExporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// PartialSuccess is a successful response of the collector reporting that
+// part of a batch was rejected.
+type PartialSuccess struct {
+	Rejected int64  // Number of rejected spans
+	Message  string // Reason of the rejection, if any
+}
+
+// PartialSuccessParser parses the body of a successful response, reporting
+// whether it describes a partial success.
+type PartialSuccessParser func(contentType string, body []byte) (PartialSuccess, bool)
+
+// WithPartialSuccessParser configures how partial success responses are
+// recognized. By default, OTLP partial_success responses, in protobuf or JSON,
+// JSON bodies of the form {"accepted": 950, "rejected": 50, "error": "..."},
+// Application Insights track responses, Elasticsearch bulk responses, Kafka
+// REST Proxy produce responses and per event statuses of the form
+// [{"status": 202}, {"status": 400, "error": "..."}], as returned by the
+// Honeycomb batch API, are recognized. Rejected spans are logged and
+// reported by Stats and the export callbacks.
+func WithPartialSuccessParser(p PartialSuccessParser) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.partialSuccessParser = p
+		return cfg
+	})
+}
+
+// parsePartialSuccess is the default PartialSuccessParser.
+func parsePartialSuccess(contentType string, body []byte) (PartialSuccess, bool) {
+	if len(body) == 0 {
+		return PartialSuccess{}, false
+	}
+	var ps PartialSuccess
+	if strings.HasPrefix(contentType, "application/x-protobuf") {
+		ps = parseOTLPPartialSuccess(body)
+	} else if b := bytes.TrimSpace(body); len(b) > 0 && b[0] == '[' {
+		var statuses []struct {
+			Status int    `json:"status"`
+			Error  string `json:"error"`
+		}
+		if err := json.Unmarshal(b, &statuses); err != nil {
+			return PartialSuccess{}, false
+		}
+		for _, st := range statuses {
+			if st.Status < 200 || st.Status >= 300 {
+				ps.Rejected++
+				if ps.Message == "" {
+					ps.Message = st.Error
+				}
+			}
+		}
+	} else {
+		var resp struct {
+			PartialSuccess  *otlpJSONPartialSuccess `json:"partialSuccess"`
+			PartialSuccess2 *otlpJSONPartialSuccess `json:"partial_success"`
+			Rejected        int64                   `json:"rejected"`
+			Error           string                  `json:"error"`
+			ItemsReceived   *int64                  `json:"itemsReceived"`
+			ItemsAccepted   int64                   `json:"itemsAccepted"`
+			// Errors is an array in track responses and a bool in bulk
+			// responses.
+			Errors json.RawMessage `json:"errors"`
+			// Items holds the result of each action of a bulk request,
+			// keyed by the action.
+			Items []map[string]struct {
+				Status int `json:"status"`
+				Error  *struct {
+					Type   string `json:"type"`
+					Reason string `json:"reason"`
+				} `json:"error"`
+			} `json:"items"`
+			Offsets []struct {
+				ErrorCode *int   `json:"error_code"`
+				Error     string `json:"error"`
+			} `json:"offsets"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return PartialSuccess{}, false
+		}
+		if resp.PartialSuccess == nil {
+			resp.PartialSuccess = resp.PartialSuccess2
+		}
+		if p := resp.PartialSuccess; p != nil {
+			n, _ := p.RejectedSpans.Int64()
+			ps = PartialSuccess{Rejected: n, Message: p.ErrorMessage}
+		} else if resp.Offsets != nil {
+			for _, offset := range resp.Offsets {
+				if offset.ErrorCode != nil {
+					ps.Rejected++
+					if ps.Message == "" {
+						ps.Message = offset.Error
+					}
+				}
+			}
+		} else if resp.Items != nil {
+			for _, item := range resp.Items {
+				for _, result := range item {
+					if result.Status >= 200 && result.Status < 300 && result.Error == nil {
+						continue
+					}
+					ps.Rejected++
+					if ps.Message == "" && result.Error != nil {
+						ps.Message = result.Error.Type + ": " + result.Error.Reason
+					}
+				}
+			}
+		} else if resp.ItemsReceived != nil {
+			ps.Rejected = *resp.ItemsReceived - resp.ItemsAccepted
+			var errs []struct {
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(resp.Errors, &errs) == nil && len(errs) > 0 {
+				ps.Message = errs[0].Message
+			}
+		} else {
+			ps = PartialSuccess{Rejected: resp.Rejected, Message: resp.Error}
+		}
+	}
+	return ps, ps.Rejected > 0 || ps.Message != ""
+}
+
+// otlpJSONPartialSuccess is the partial_success field of an OTLP/JSON
+// ExportTraceServiceResponse, whose int64 is encoded as a string.
+type otlpJSONPartialSuccess struct {
+	RejectedSpans json.Number `json:"rejectedSpans"`
+	ErrorMessage  string      `json:"errorMessage"`
+}
+
+// parseOTLPPartialSuccess decodes the partial_success field (1) of a
+// protobuf ExportTraceServiceResponse: rejected_spans (1) and error_message
+// (2).
+func parseOTLPPartialSuccess(b []byte) PartialSuccess {
+	var ps PartialSuccess
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return PartialSuccess{}
+		}
+		b = b[n:]
+		if num != 1 || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return PartialSuccess{}
+			}
+			b = b[n:]
+			continue
+		}
+		msg, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return PartialSuccess{}
+		}
+		b = b[n:]
+		for len(msg) > 0 {
+			num, typ, n := protowire.ConsumeTag(msg)
+			if n < 0 {
+				return PartialSuccess{}
+			}
+			msg = msg[n:]
+			switch {
+			case num == 1 && typ == protowire.VarintType:
+				v, n := protowire.ConsumeVarint(msg)
+				if n < 0 {
+					return PartialSuccess{}
+				}
+				ps.Rejected = int64(v)
+				msg = msg[n:]
+			case num == 2 && typ == protowire.BytesType:
+				v, n := protowire.ConsumeBytes(msg)
+				if n < 0 {
+					return PartialSuccess{}
+				}
+				ps.Message = string(v)
+				msg = msg[n:]
+			default:
+				if n = protowire.ConsumeFieldValue(num, typ, msg); n < 0 {
+					return PartialSuccess{}
+				}
+				msg = msg[n:]
+			}
+		}
+	}
+	return ps
+}
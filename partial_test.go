@@ -0,0 +1,91 @@
+package httpExporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// otlpPartialSuccess returns a protobuf ExportTraceServiceResponse holding a
+// partial_success field.
+func otlpPartialSuccess(rejected uint64, message string) []byte {
+	var ps []byte
+	ps = protowire.AppendTag(ps, 1, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, rejected)
+	ps = protowire.AppendTag(ps, 2, protowire.BytesType)
+	ps = protowire.AppendString(ps, message)
+	var b []byte
+	// An unknown field is skipped.
+	b = protowire.AppendTag(b, 7, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, ps)
+}
+
+func TestParsePartialSuccess(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		contentType string
+		body        string
+		want        PartialSuccess
+		ok          bool
+	}{
+		{"empty", "application/json", "", PartialSuccess{}, false},
+		{"not JSON", "text/plain", "OK", PartialSuccess{}, false},
+		{"full success", "application/json", `{}`, PartialSuccess{}, false},
+		{"OTLP protobuf", "application/x-protobuf", string(otlpPartialSuccess(3, "too old")), PartialSuccess{Rejected: 3, Message: "too old"}, true},
+		{"OTLP protobuf full success", "application/x-protobuf", "", PartialSuccess{}, false},
+		{"OTLP JSON", "application/json", `{"partialSuccess": {"rejectedSpans": "2", "errorMessage": "invalid"}}`, PartialSuccess{Rejected: 2, Message: "invalid"}, true},
+		{"OTLP JSON snake case", "application/json", `{"partial_success": {"rejectedSpans": 2}}`, PartialSuccess{Rejected: 2}, true},
+		{"OTLP JSON empty partial success", "application/json", `{"partialSuccess": {}}`, PartialSuccess{}, false},
+		{"accepted and rejected", "application/json", `{"accepted": 950, "rejected": 50, "error": "quota"}`, PartialSuccess{Rejected: 50, Message: "quota"}, true},
+		{"warning only", "application/json", `{"error": "deprecated endpoint"}`, PartialSuccess{Message: "deprecated endpoint"}, true},
+		{"event statuses", "application/json", `[{"status": 202}, {"status": 400, "error": "bad event"}, {"status": 413}]`, PartialSuccess{Rejected: 2, Message: "bad event"}, true},
+		{"event statuses accepted", "application/json", `[{"status": 202}, {"status": 202}]`, PartialSuccess{}, false},
+		{"track response", "application/json", `{"itemsReceived": 3, "itemsAccepted": 1, "errors": [{"index": 1, "statusCode": 400, "message": "invalid"}]}`, PartialSuccess{Rejected: 2, Message: "invalid"}, true},
+		{"track response accepted", "application/json", `{"itemsReceived": 3, "itemsAccepted": 3, "errors": []}`, PartialSuccess{}, false},
+		{"bulk response", "application/json", `{"took": 3, "errors": true, "items": [{"create": {"_index": "spans", "status": 201}}, {"create": {"_index": "spans", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse field [attrs.id]"}}}, {"create": {"_index": "spans", "status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "queue full"}}}]}`, PartialSuccess{Rejected: 2, Message: "mapper_parsing_exception: failed to parse field [attrs.id]"}, true},
+		{"bulk response accepted", "application/json", `{"took": 3, "errors": false, "items": [{"create": {"_index": "spans", "status": 201}}]}`, PartialSuccess{}, false},
+		{"produce offsets", "application/vnd.kafka.v2+json", `{"offsets": [{"partition": 0, "offset": 1}, {"error_code": 40403, "error": "unknown topic"}]}`, PartialSuccess{Rejected: 1, Message: "unknown topic"}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parsePartialSuccess(tt.contentType, []byte(tt.body))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parsePartialSuccess(%q) = %+v, %v, want %+v, %v", tt.body, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPartialSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"accepted": 2, "rejected": 1, "error": "span too large"}`))
+	}))
+	defer srv.Close()
+	var infos []ExportInfo
+	e, err := New(srv.URL, WithOnExportSuccess(func(info ExportInfo) { infos = append(infos, info) }))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+
+	spans := tracetest.SpanStubs{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	for i := range spans {
+		spans[i].StartTime = time.Now()
+	}
+	if err := e.ExportSpans(context.Background(), spans.Snapshots()); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if s := e.Stats(); s.SpansSent != 2 || s.SpansRejected != 1 {
+		t.Errorf("Stats() = %+v, want 2 spans sent and 1 rejected", s)
+	}
+	if len(infos) != 1 || infos[0].Spans != 3 || infos[0].Rejected != 1 {
+		t.Errorf("success callbacks got %+v, want one with 1 of 3 spans rejected", infos)
+	}
+}
@@ -0,0 +1,154 @@
+package httpExporter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// Batch span processor defaults of NewTracerProvider. Batches are exported
+// sooner and larger than with the SDK defaults, the exporter splitting them
+// as the collector requires.
+const (
+	defaultBatchTimeout       = time.Second
+	defaultMaxQueueSize       = 8192
+	defaultMaxExportBatchSize = 1024
+	minExportTimeout          = 30 * time.Second
+)
+
+// PipelineConfig configures the tracer provider of NewTracerProvider and
+// InstallNewPipeline.
+type PipelineConfig struct {
+	// Resource describes the service. Its attributes take precedence over
+	// the detected telemetry SDK, host and process runtime attributes and
+	// the service name set with WithServiceName; OTEL_RESOURCE_ATTRIBUTES
+	// and OTEL_SERVICE_NAME take precedence over them.
+	Resource *resource.Resource
+	// Sampler samples the spans. Defaults to the SDK default, sampling root
+	// spans and following the decision of the parent of others.
+	Sampler sdktrace.Sampler
+	// BatchTimeout is the longest time spans wait for a batch to fill.
+	// Defaults to 1s.
+	BatchTimeout time.Duration
+	// MaxQueueSize is the number of spans buffered before new ones are
+	// dropped. Defaults to 8192.
+	MaxQueueSize int
+	// MaxExportBatchSize is the largest number of spans exported at once.
+	// Defaults to 1024.
+	MaxExportBatchSize int
+	// ExportTimeout bounds the export of a batch. Defaults to the request
+	// timeout plus the retry MaxElapsedTime of the exporter, or 30s if
+	// longer.
+	ExportTimeout time.Duration
+	// BatchOptions are applied to the batch span processor after the
+	// settings above.
+	BatchOptions []sdktrace.BatchSpanProcessorOption
+}
+
+// WithPipeline configures the tracer provider created by NewTracerProvider
+// and InstallNewPipeline. It has no effect on New.
+func WithPipeline(pc PipelineConfig) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.pipeline = pc
+		return cfg
+	})
+}
+
+// NewTracerProvider creates an exporter for the collector at collectorURL
+// and returns a tracer provider exporting its spans through a batch span
+// processor, configured with WithPipeline. Shutting the provider down flushes
+// the spans and shuts the exporter down.
+func NewTracerProvider(collectorURL string, opts ...Option) (*sdktrace.TracerProvider, error) {
+	cfg := newConfig(opts...)
+	exp, err := newExporter(collectorURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	res, err := pipelineResource(cfg)
+	if err != nil {
+		_ = exp.Shutdown(context.Background())
+		return nil, err
+	}
+	pc := cfg.pipeline
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exp, pipelineBatchOptions(pc, exp)...)),
+	}
+	if pc.Sampler != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSampler(pc.Sampler))
+	}
+	if cfg.format == XRay {
+		tpOpts = append(tpOpts, sdktrace.WithIDGenerator(XRayIDGenerator()))
+	}
+	return sdktrace.NewTracerProvider(tpOpts...), nil
+}
+
+// InstallNewPipeline creates a tracer provider with NewTracerProvider and
+// registers it globally. Shut it down before the process exits so spans are
+// not lost.
+func InstallNewPipeline(collectorURL string, opts ...Option) (*sdktrace.TracerProvider, error) {
+	tp, err := NewTracerProvider(collectorURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(tp)
+	return tp, nil
+}
+
+// pipelineBatchOptions returns the options of the batch span processor.
+func pipelineBatchOptions(pc PipelineConfig, exp *Exporter) []sdktrace.BatchSpanProcessorOption {
+	if pc.BatchTimeout <= 0 {
+		pc.BatchTimeout = defaultBatchTimeout
+	}
+	if pc.MaxQueueSize <= 0 {
+		pc.MaxQueueSize = defaultMaxQueueSize
+	}
+	if pc.MaxExportBatchSize <= 0 {
+		pc.MaxExportBatchSize = defaultMaxExportBatchSize
+	}
+	if pc.ExportTimeout <= 0 {
+		// Leave the exporter the time to retry before the export is abandoned.
+		pc.ExportTimeout = exp.timeout
+		if exp.retry.Enabled {
+			pc.ExportTimeout += exp.retry.MaxElapsedTime
+		}
+		if pc.ExportTimeout < minExportTimeout {
+			pc.ExportTimeout = minExportTimeout
+		}
+	}
+	return append([]sdktrace.BatchSpanProcessorOption{
+		sdktrace.WithBatchTimeout(pc.BatchTimeout),
+		sdktrace.WithMaxQueueSize(pc.MaxQueueSize),
+		sdktrace.WithMaxExportBatchSize(pc.MaxExportBatchSize),
+		sdktrace.WithExportTimeout(pc.ExportTimeout),
+	}, pc.BatchOptions...)
+}
+
+// pipelineResource returns the resource of the tracer provider: the SDK
+// default resource, with the detected host and process runtime, the service
+// name of the exporter and the configured resource.
+func pipelineResource(cfg config) (*resource.Resource, error) {
+	opts := []resource.Option{
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+	}
+	if cfg.serviceName != "" {
+		opts = append(opts, resource.WithAttributes(semconv.ServiceNameKey.String(cfg.serviceName)))
+	}
+	if res := cfg.pipeline.Resource; res != nil {
+		opts = append(opts, resource.WithAttributes(res.Attributes()...))
+	}
+	opts = append(opts, resource.WithFromEnv())
+	res, err := resource.New(context.Background(), opts...)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+	return resource.Merge(resource.Default(), res)
+}
@@ -0,0 +1,89 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	c := newCollector(t)
+	tp, err := httpExporter.NewTracerProvider(c.URL(),
+		httpExporter.WithServiceName("checkout"),
+		httpExporter.WithPipeline(httpExporter.PipelineConfig{
+			Resource: resource.NewSchemaless(attribute.String("deployment.environment", "test")),
+			Sampler:  sdktrace.AlwaysSample(),
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewTracerProvider: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "a")
+	span.End()
+	// Shutting the provider down flushes the batch.
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	spans := c.spans(t)
+	if names := spanNames(spans); !equal(names, "a") {
+		t.Fatalf("collector received %v, want [a]", names)
+	}
+	res := spans[0].Resource
+	if res["service.name"] != "checkout" || res["deployment.environment"] != "test" {
+		t.Errorf("resource = %v, want the service name and configured attributes", res)
+	}
+	if res["telemetry.sdk.name"] != "opentelemetry" || res["host.name"] == nil {
+		t.Errorf("resource = %v, want the detected attributes", res)
+	}
+}
+
+func TestNewTracerProviderResourceFromEnvironment(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "cart")
+	c := newCollector(t)
+	tp, err := httpExporter.NewTracerProvider(c.URL(), httpExporter.WithServiceName("checkout"))
+	if err != nil {
+		t.Fatalf("NewTracerProvider: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "a")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if spans := c.spans(t); len(spans) != 1 || spans[0].Resource["service.name"] != "cart" {
+		t.Errorf("collector received %+v, want the service name of OTEL_SERVICE_NAME", spans)
+	}
+}
+
+func TestNewTracerProviderInvalid(t *testing.T) {
+	if _, err := httpExporter.NewTracerProvider("http://localhost:4318", httpExporter.WithFormat(httpExporter.Format(99))); err == nil {
+		t.Error("NewTracerProvider succeeded with an invalid configuration")
+	}
+}
+
+func TestInstallNewPipeline(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	c := newCollector(t)
+	tp, err := httpExporter.InstallNewPipeline(c.URL())
+	if err != nil {
+		t.Fatalf("InstallNewPipeline: %v", err)
+	}
+	if otel.GetTracerProvider() != tp {
+		t.Fatal("InstallNewPipeline did not register the tracer provider")
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "a")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
@@ -0,0 +1,146 @@
+package httpExporter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// maxPooledBufferSize bounds the capacity of pooled encoding buffers, so a
+// single huge batch does not pin its memory forever.
+const maxPooledBufferSize = 16 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty encoding buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns an encoding buffer to the pool.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// bufferEncoder is implemented by encoders able to encode spans into a
+// buffer, so request bodies can be built in pooled buffers.
+type bufferEncoder interface {
+	encodeTo(buf *bytes.Buffer, spans []sdktrace.ReadOnlySpan) error
+}
+
+// encodeBuffer encodes spans with enc into a buffer of their own, for the
+// bodies that are not released, such as those of startup checks.
+func encodeBuffer(enc bufferEncoder, spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := enc.encodeTo(&buf, spans); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pooledBody is an encoded request body, built in a pooled buffer when the
+// encoder supports it. As the transport may still read a request body after
+// the request completed, the buffer is only returned to the pool once the
+// exporter released the body and every request reading it was closed.
+type pooledBody struct {
+	body []byte
+	buf  *bytes.Buffer // nil when the body is not pooled
+	refs int32         // Accessed atomically
+}
+
+// encodeBody encodes spans with enc into a body to release once sent.
+func encodeBody(enc encoder, spans []sdktrace.ReadOnlySpan) (*pooledBody, error) {
+	be, ok := enc.(bufferEncoder)
+	if !ok {
+		body, err := enc.encode(spans)
+		if err != nil {
+			return nil, err
+		}
+		return &pooledBody{body: body, refs: 1}, nil
+	}
+	buf := getBuffer()
+	if err := be.encodeTo(buf, spans); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return &pooledBody{body: buf.Bytes(), buf: buf, refs: 1}, nil
+}
+
+func (b *pooledBody) release() {
+	if atomic.AddInt32(&b.refs, -1) == 0 && b.buf != nil {
+		putBuffer(b.buf)
+	}
+}
+
+// holds reports whether body is the body of b, rather than another one such
+// as its compressed form.
+func (b *pooledBody) holds(body []byte) bool {
+	return len(body) > 0 && len(body) == len(b.body) && &body[0] == &b.body[0]
+}
+
+// reader returns a request body reading b, which holds the buffer of b until
+// closed.
+func (b *pooledBody) reader() io.ReadCloser {
+	atomic.AddInt32(&b.refs, 1)
+	return &pooledBodyReader{Reader: bytes.NewReader(b.body), body: b}
+}
+
+type pooledBodyReader struct {
+	*bytes.Reader
+	body *pooledBody
+	once sync.Once
+}
+
+func (r *pooledBodyReader) Close() error {
+	r.once.Do(r.body.release)
+	return nil
+}
+
+type pooledBodyKey struct{}
+
+// withPooledBody binds the requests sent with ctx to b: those sending its
+// body read it from the pooled buffer.
+func withPooledBody(ctx context.Context, b *pooledBody) context.Context {
+	return context.WithValue(ctx, pooledBodyKey{}, b)
+}
+
+// setRequestBody makes req read its body from the pooled buffer of ctx, if
+// it holds the body.
+func setRequestBody(ctx context.Context, req *http.Request, body []byte) {
+	b, ok := ctx.Value(pooledBodyKey{}).(*pooledBody)
+	if !ok || b.buf == nil || !b.holds(body) {
+		return
+	}
+	req.Body = b.reader()
+	req.GetBody = func() (io.ReadCloser, error) { return b.reader(), nil }
+}
+
+var spanDataPool = sync.Pool{
+	New: func() interface{} { return new([]SpanData) },
+}
+
+// withSpanData calls fn with the spans converted to SpanData, in a slice
+// that is reused once fn returned.
+func withSpanData(c converter, spans []sdktrace.ReadOnlySpan, fn func([]SpanData) error) error {
+	p := spanDataPool.Get().(*[]SpanData)
+	converted := c.appendSpansToHttp((*p)[:0], spans)
+	err := fn(converted)
+	// Drop the references to the converted spans before pooling the slice.
+	for i := range converted {
+		converted[i] = SpanData{}
+	}
+	*p = converted[:0]
+	spanDataPool.Put(p)
+	return err
+}
@@ -0,0 +1,93 @@
+package httpExporter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestEncodeDoesNotAliasPooledBuffers(t *testing.T) {
+	enc := newEncoder(newConfig())
+	spans := testSpans()
+	first, err := enc.encode(spans[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte(nil), first...)
+	// Encoding again reuses the pooled buffer the first body was built in.
+	for i := 0; i < 10; i++ {
+		if _, err := enc.encode(spans[1:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(first, want) {
+		t.Errorf("body changed by later encodes:\n%s\nwant:\n%s", first, want)
+	}
+}
+
+func TestPooledBodyOutlivesRelease(t *testing.T) {
+	enc := newEncoder(newConfig())
+	spans := testSpans()
+	pb, err := encodeBody(enc, spans[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.buf == nil {
+		t.Fatal("JSON body not built in a pooled buffer")
+	}
+	want := append([]byte(nil), pb.body...)
+	// The transport may read a request body after the exporter is done with
+	// it: the buffer is held until the reader is closed.
+	r := pb.reader()
+	pb.release()
+	for i := 0; i < 10; i++ {
+		other, err := encodeBody(enc, spans[1:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		other.release()
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("request read %s, want %s", got, want)
+	}
+	r.Close()
+	r.Close()
+	if pb.refs != 0 {
+		t.Errorf("%d references left to the body after closing its reader twice", pb.refs)
+	}
+}
+
+func TestSetRequestBody(t *testing.T) {
+	pb, err := encodeBody(newEncoder(newConfig()), testSpans())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pb.release()
+	ctx := withPooledBody(context.Background(), pb)
+	for name, test := range map[string]struct {
+		body   []byte
+		pooled bool
+	}{
+		"body":       {pb.body, true},
+		"compressed": {append([]byte(nil), pb.body...), false},
+	} {
+		req, err := http.NewRequest(http.MethodPost, "http://localhost:4318", bytes.NewBuffer(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		setRequestBody(ctx, req, test.body)
+		if _, pooled := req.Body.(*pooledBodyReader); pooled != test.pooled {
+			t.Errorf("%s: request body pooled = %v, want %v", name, pooled, test.pooled)
+		}
+		if req.ContentLength != int64(len(test.body)) {
+			t.Errorf("%s: ContentLength = %d, want %d", name, req.ContentLength, len(test.body))
+		}
+		req.Body.Close()
+	}
+}
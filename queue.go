@@ -0,0 +1,181 @@
+package httpExporter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// QueuePolicy defines what happens to a batch exported while the queue of an
+// asynchronous exporter is full.
+type QueuePolicy int
+
+const (
+	// DropOldest discards the oldest queued batch to make room.
+	DropOldest QueuePolicy = iota
+	// DropNewest discards the exported batch.
+	DropNewest
+	// Block waits for room in the queue, or for the export context to be
+	// done.
+	Block
+)
+
+// String returns the name of the queue policy.
+func (p QueuePolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	case Block:
+		return "block"
+	}
+	return "unknown"
+}
+
+// WithQueue configures the exporter to send batches asynchronously:
+// ExportSpans adds the batch to a queue of up to capacity batches and returns,
+// and a background goroutine sends the queued batches to the collector.
+// policy defines what happens when the queue is full. Send failures are
+// logged. Shutdown sends the queued batches before returning.
+func WithQueue(capacity int, policy QueuePolicy) Option {
+	return optionFunc(func(cfg config) config {
+		if capacity < 1 {
+			capacity = 1
+		}
+		cfg.queueCapacity = capacity
+		cfg.queuePolicy = policy
+		return cfg
+	})
+}
+
+// queue holds batches for the background sender.
+type queue struct {
+	batches chan []sdktrace.ReadOnlySpan
+	policy  QueuePolicy
+	dropped uint64 // Dropped batches, accessed atomically
+
+	pending pendingGroup // Batches queued or being sent
+
+	mu     sync.RWMutex  // Held for writing to close batches
+	closed bool          // Whether batches is closed
+	stop   chan struct{} // Closed by close, to unblock enqueue
+
+	ctx    context.Context // Canceled to abort the background sender
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the background senders returned
+}
+
+func newQueue(capacity int, policy QueuePolicy) *queue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &queue{
+		batches: make(chan []sdktrace.ReadOnlySpan, capacity),
+		policy:  policy,
+		ctx:     ctx,
+		cancel:  cancel,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// start runs the given number of background senders, sending queued
+// batches until the queue is closed.
+func (q *queue) start(e *Exporter, senders int) {
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range q.batches {
+				if err := e.exportSpans(q.ctx, batch); err != nil {
+					e.warnf("failed to export queued batch: %v", err)
+				}
+				q.pending.add(-1)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(q.done)
+	}()
+}
+
+// enqueue adds a batch to the queue according to the queue policy. It fails
+// with ErrShutdown once the queue is closed.
+func (q *queue) enqueue(ctx context.Context, e *Exporter, spans []sdktrace.ReadOnlySpan) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrShutdown
+	}
+	// The caller may reuse the slice once ExportSpans returned.
+	batch := append([]sdktrace.ReadOnlySpan(nil), spans...)
+	q.pending.add(1)
+	switch q.policy {
+	case Block:
+		select {
+		case q.batches <- batch:
+			return nil
+		case <-q.stop:
+			q.pending.add(-1)
+			return ErrShutdown
+		case <-ctx.Done():
+			q.pending.add(-1)
+			return ctx.Err()
+		}
+	case DropNewest:
+		select {
+		case q.batches <- batch:
+		default:
+			q.drop(e, batch)
+		}
+		return nil
+	default:
+		for {
+			select {
+			case q.batches <- batch:
+				return nil
+			default:
+			}
+			select {
+			case dropped := <-q.batches:
+				q.drop(e, dropped)
+			default:
+			}
+		}
+	}
+}
+
+func (q *queue) drop(e *Exporter, batch []sdktrace.ReadOnlySpan) {
+	q.pending.add(-1)
+	e.recordDropped(len(batch))
+	n := atomic.AddUint64(&q.dropped, 1)
+	e.warnf("export queue full, dropped a batch (%d dropped in total)", n)
+}
+
+// flush waits until the batches queued so far have been sent.
+func (q *queue) flush(ctx context.Context) error {
+	return q.pending.wait(ctx)
+}
+
+// close stops accepting batches and waits for the queued ones to be sent. If
+// ctx is done first the background senders are aborted, failing the remaining
+// batches.
+func (q *queue) close(ctx context.Context) error {
+	// Exports still in progress may be enqueuing concurrently.
+	close(q.stop)
+	q.mu.Lock()
+	q.closed = true
+	close(q.batches)
+	q.mu.Unlock()
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,153 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestQueueSendsAsynchronously(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(200 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(10, httpExporter.Block))
+
+	start := time.Now()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := e.ExportSpans(context.Background(), newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if d := time.Since(start); d >= 200*time.Millisecond {
+		t.Errorf("ExportSpans waited %v for the collector", d)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c") {
+		t.Errorf("collector received %v after Shutdown, want the 3 queued batches in order", got)
+	}
+}
+
+func TestQueueCopiesBatch(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(100 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(10, httpExporter.Block))
+
+	ctx := context.Background()
+	spans := newSpans("a")
+	if err := e.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// The span processor reuses its batch slice once ExportSpans returned.
+	spans[0] = newSpans("overwritten")[0]
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestQueueDropNewest(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(200 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(1, httpExporter.DropNewest))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("sent")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// Wait for the sender to take the first batch, leaving the queue empty.
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	for _, name := range []string{"queued", "dropped"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "sent", "queued") {
+		t.Errorf("collector received %v, want [sent queued]", got)
+	}
+}
+
+func TestQueueDropOldest(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(200 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(1, httpExporter.DropOldest))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("sent")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	for _, name := range []string{"dropped", "queued"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "sent", "queued") {
+		t.Errorf("collector received %v, want [sent queued]", got)
+	}
+}
+
+func TestQueueBlock(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(300 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(1, httpExporter.Block))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("sent")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	if err := e.ExportSpans(ctx, newSpans("queued")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// The queue is full: the export blocks until its context is done.
+	bctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := e.ExportSpans(bctx, newSpans("blocked")); err != context.DeadlineExceeded {
+		t.Errorf("ExportSpans with a full queue = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestQueueShutdownReleasesBlockedExports(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(time.Second)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(1, httpExporter.Block))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("sent")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	if err := e.ExportSpans(ctx, newSpans("queued")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	blocked := make(chan error, 1)
+	go func() { blocked <- e.ExportSpans(ctx, newSpans("blocked")) }()
+
+	sctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := e.Shutdown(sctx); err == nil {
+		t.Error("Shutdown succeeded before the queue could be sent")
+	}
+	select {
+	case err := <-blocked:
+		if err != httpExporter.ErrShutdown {
+			t.Errorf("blocked ExportSpans = %v, want ErrShutdown", err)
+		}
+	case <-time.After(waitTimeout):
+		t.Fatal("ExportSpans still blocked after Shutdown")
+	}
+	if err := e.ExportSpans(ctx, newSpans("late")); err != httpExporter.ErrShutdown {
+		t.Errorf("ExportSpans after Shutdown = %v, want ErrShutdown", err)
+	}
+}
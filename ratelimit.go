@@ -0,0 +1,116 @@
+package httpExporter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LimitPolicy defines what happens to spans exceeding the rate limit.
+type LimitPolicy int
+
+const (
+	// LimitWait delays the export until the rate limit allows it.
+	LimitWait LimitPolicy = iota
+	// LimitDrop drops the spans exceeding the rate limit.
+	LimitDrop
+)
+
+// String returns the name of the limit policy.
+func (p LimitPolicy) String() string {
+	switch p {
+	case LimitWait:
+		return "wait"
+	case LimitDrop:
+		return "drop"
+	}
+	return "unknown"
+}
+
+// WithRateLimit limits the rate spans are sent to the collector at to
+// spansPerSecond, allowing bursts of up to burst spans. By default exports
+// wait for the rate limit, which with WithQueue makes batches queue up; see
+// WithRateLimitPolicy.
+func WithRateLimit(spansPerSecond float64, burst int) Option {
+	return optionFunc(func(cfg config) config {
+		if burst < 1 {
+			burst = 1
+		}
+		cfg.rateLimit = spansPerSecond
+		cfg.rateBurst = burst
+		return cfg
+	})
+}
+
+// WithRateLimitPolicy configures what happens to spans exceeding the rate
+// limit set with WithRateLimit.
+func WithRateLimitPolicy(policy LimitPolicy) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.ratePolicy = policy
+		return cfg
+	})
+}
+
+// limiter is a token bucket holding one token per span.
+type limiter struct {
+	rate   float64
+	burst  float64
+	policy LimitPolicy
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int, policy LimitPolicy) *limiter {
+	return &limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		policy: policy,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last call.
+func (l *limiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// admit returns how many of n spans may be sent, waiting for the rate limit
+// with the LimitWait policy.
+func (l *limiter) admit(ctx context.Context, n int) (int, error) {
+	l.mu.Lock()
+	l.refill(time.Now())
+	if l.policy == LimitDrop {
+		defer l.mu.Unlock()
+		allowed := n
+		if float64(allowed) > l.tokens {
+			allowed = int(l.tokens)
+		}
+		l.tokens -= float64(allowed)
+		return allowed, nil
+	}
+	// Reserve the tokens, going into debt, and wait until it is paid off.
+	l.tokens -= float64(n)
+	deficit := -l.tokens
+	l.mu.Unlock()
+	if deficit <= 0 {
+		return n, nil
+	}
+	t := time.NewTimer(time.Duration(deficit / l.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return n, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += float64(n)
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+}
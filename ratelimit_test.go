@@ -0,0 +1,70 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithRateLimit(20, 2))
+
+	ctx := context.Background()
+	start := time.Now()
+	if err := e.ExportSpans(ctx, newSpans("a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("burst waited %v", d)
+	}
+	// Two more spans take 100ms at 20 spans per second.
+	if err := e.ExportSpans(ctx, newSpans("c", "d")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if d := time.Since(start); d < 80*time.Millisecond {
+		t.Errorf("4 spans were sent within %v, past the rate limit", d)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c", "d") {
+		t.Errorf("collector received %v, want all spans", got)
+	}
+}
+
+func TestRateLimitWaitCanceled(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithRateLimit(1, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := e.ExportSpans(ctx, newSpans("a", "b", "c")); err != context.DeadlineExceeded {
+		t.Errorf("ExportSpans = %v, want DeadlineExceeded", err)
+	}
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests", got)
+	}
+	// The tokens of the canceled export are given back.
+	if err := e.ExportSpans(context.Background(), newSpans("d")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "d") {
+		t.Errorf("collector received %v, want [d]", got)
+	}
+}
+
+func TestRateLimitDrop(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithRateLimit(1, 3), httpExporter.WithRateLimitPolicy(httpExporter.LimitDrop))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a", "b", "c", "d", "e")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.ExportSpans(ctx, newSpans("f")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c") {
+		t.Errorf("collector received %v, want the 3 spans of the burst", got)
+	}
+}
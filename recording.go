@@ -0,0 +1,109 @@
+package httpExporter
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// RecordingExporter is an exporter recording the request bodies it would
+// send to files instead of sending them, for golden-file tests of the wire
+// format or to capture traffic to replay later with Replay.
+type RecordingExporter struct {
+	*Exporter
+	recorder *recorder
+}
+
+// NewRecordingExporter creates an exporter recording each request body to a
+// file in dir, named after its position in the recording and with an
+// extension matching its content type: 000001.json, 000002.json and so on.
+// The directory is created if it does not exist; existing recordings with
+// the same names are overwritten. opts configure the exporter as for New,
+// although no collector is contacted.
+func NewRecordingExporter(dir string, opts ...Option) (*RecordingExporter, error) {
+	r, err := newRecorder(dir)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfig(opts...)
+	cfg.sink = r
+	e, err := newExporter("", cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordingExporter{Exporter: e, recorder: r}, nil
+}
+
+// Files returns the paths of the files recorded so far, in order.
+func (r *RecordingExporter) Files() []string {
+	r.recorder.mu.Lock()
+	defer r.recorder.mu.Unlock()
+	return append([]string(nil), r.recorder.files...)
+}
+
+// recorder writes request bodies to numbered files.
+type recorder struct {
+	dir string
+
+	mu    sync.Mutex
+	files []string
+}
+
+func newRecorder(dir string) (*recorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %v", err)
+	}
+	return &recorder{dir: dir}, nil
+}
+
+func (r *recorder) write(contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name := filepath.Join(r.dir, fmt.Sprintf("%06d%s", len(r.files)+1, dumpExtension(contentType)))
+	if err := ioutil.WriteFile(name, body, 0o600); err != nil {
+		return fmt.Errorf("failed to record batch: %v", err)
+	}
+	r.files = append(r.files, name)
+	return nil
+}
+
+// Replay sends the request bodies recorded in dir by a RecordingExporter, or
+// dumped by WithDebugDump, to the collector at collectorURL in the order
+// they were recorded. The content type of each request is derived from the
+// file extension. opts configure the exporter sending them as for New, so
+// headers, retries and the like apply. Replay stops at the first request
+// that is not accepted.
+func Replay(ctx context.Context, dir, collectorURL string, opts ...Option) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read recording directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if _, ok := dumpContentType(filepath.Ext(entry.Name())); ok && entry.Mode().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	e, err := New(collectorURL, opts...)
+	if err != nil {
+		return err
+	}
+	defer e.Shutdown(context.Background())
+	for _, name := range names {
+		body, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read recorded batch: %v", err)
+		}
+		contentType, _ := dumpContentType(filepath.Ext(name))
+		if err := e.deliverPrimary(ctx, contentType, body); err != nil {
+			return fmt.Errorf("failed to replay %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package httpExporter_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recording")
+	r, err := httpExporter.NewRecordingExporter(dir)
+	if err != nil {
+		t.Fatalf("NewRecordingExporter: %v", err)
+	}
+	ctx := context.Background()
+	for _, names := range [][]string{{"a", "b"}, {"c"}} {
+		if err := r.ExportSpans(ctx, newSpans(names...)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if err := r.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	files := r.Files()
+	if len(files) != 2 || filepath.Base(files[0]) != "000001.json" || filepath.Base(files[1]) != "000002.json" {
+		t.Fatalf("recorded %v, want 000001.json and 000002.json", files)
+	}
+	fi, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("recording mode = %v, want 0600 as it holds span data", perm)
+	}
+	// Unrelated files are not replayed.
+	if err := ioutil.WriteFile(filepath.Join(dir, "README"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newCollector(t)
+	if err := httpExporter.Replay(ctx, dir, c.URL()); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c") {
+		t.Errorf("collector received %v, want [a b c]", got)
+	}
+	if reqs := c.received(); len(reqs) != 2 || reqs[0].Header.Get("Content-Type") != "application/json" {
+		t.Errorf("collector received %+v, want 2 JSON requests", reqs)
+	}
+}
+
+func TestReplayStopsOnRejection(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000001.json", "000002.json"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(`[]`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := newCollector(t)
+	c.setStatus(400)
+	if err := httpExporter.Replay(context.Background(), dir, c.URL()); err == nil {
+		t.Fatal("Replay succeeded with a rejected request")
+	}
+	if got := c.count(); got != 1 {
+		t.Errorf("collector received %d requests, want Replay to stop at the first", got)
+	}
+}
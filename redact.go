@@ -0,0 +1,127 @@
+package httpExporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// redactionMask replaces masked values.
+const redactionMask = "****"
+
+// RedactionAction defines how a redacted value is handled.
+type RedactionAction int
+
+const (
+	// RedactDrop removes the attribute.
+	RedactDrop RedactionAction = iota
+	// RedactMask replaces the value with "****".
+	RedactMask
+	// RedactHash replaces the value with its hex encoded SHA-256 hash, so
+	// equal values can still be correlated.
+	RedactHash
+)
+
+// String returns the name of the redaction action.
+func (a RedactionAction) String() string {
+	switch a {
+	case RedactDrop:
+		return "drop"
+	case RedactMask:
+		return "mask"
+	case RedactHash:
+		return "hash"
+	}
+	return "unknown"
+}
+
+// RedactionRule selects attribute values to redact.
+type RedactionRule struct {
+	// Key selects attributes by key. When nil, attributes with any key are
+	// selected by Value.
+	Key *regexp.Regexp
+	// Value selects the parts of string values of the selected attributes
+	// to redact, such as email addresses or card numbers. When nil, the
+	// whole value of the selected attributes is redacted.
+	Value *regexp.Regexp
+	// Action defines how the selected values are handled. With RedactDrop,
+	// attributes with a selected value are removed.
+	Action RedactionAction
+}
+
+// WithRedaction configures rules redacting span, event, link and resource
+// attributes before they are encoded, to keep credentials and personal data
+// from leaving the process. Rules are applied in order, and a rule with
+// neither Key nor Value is ignored. For example:
+//
+//	WithRedaction(
+//		RedactionRule{Key: regexp.MustCompile(`^http\.request\.header\.authorization$`), Action: RedactDrop},
+//		RedactionRule{Value: regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`), Action: RedactMask},
+//	)
+func WithRedaction(rules ...RedactionRule) Option {
+	return optionFunc(func(cfg config) config {
+		r := redactor(append([]RedactionRule(nil), rules...))
+		cfg.attributeTransforms = append(append([]attributeTransform{}, cfg.attributeTransforms...), r.redact)
+		return cfg
+	})
+}
+
+// redactor applies redaction rules to attributes.
+type redactor []RedactionRule
+
+func (r redactor) redact(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+	for _, rule := range r {
+		if rule.Key == nil && rule.Value == nil {
+			continue
+		}
+		if rule.Key != nil && !rule.Key.MatchString(string(kv.Key)) {
+			continue
+		}
+		if rule.Value == nil {
+			if rule.Action == RedactDrop {
+				return kv, false
+			}
+			kv = kv.Key.String(rule.replace(kv.Value.Emit()))
+			continue
+		}
+		switch kv.Value.Type() {
+		case attribute.STRING:
+			s := kv.Value.AsString()
+			if !rule.Value.MatchString(s) {
+				continue
+			}
+			if rule.Action == RedactDrop {
+				return kv, false
+			}
+			kv = kv.Key.String(rule.Value.ReplaceAllStringFunc(s, rule.replace))
+		case attribute.STRINGSLICE:
+			ss := kv.Value.AsStringSlice()
+			matched := false
+			for i, s := range ss {
+				if rule.Value.MatchString(s) {
+					matched = true
+					ss[i] = rule.Value.ReplaceAllStringFunc(s, rule.replace)
+				}
+			}
+			if !matched {
+				continue
+			}
+			if rule.Action == RedactDrop {
+				return kv, false
+			}
+			kv = kv.Key.StringSlice(ss)
+		}
+	}
+	return kv, true
+}
+
+// replace returns the replacement of a redacted value.
+func (rule RedactionRule) replace(s string) string {
+	if rule.Action == RedactHash {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	return redactionMask
+}
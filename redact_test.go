@@ -0,0 +1,94 @@
+package httpExporter_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newSpanWithAttributes returns an ended span with the given attributes and
+// resource.
+func newSpanWithAttributes(res *resource.Resource, attrs ...attribute.KeyValue) []sdktrace.ReadOnlySpan {
+	return tracetest.SpanStubs{{
+		Name:        "span",
+		SpanContext: newSpanContext(newTraceID()),
+		StartTime:   time.Now(),
+		EndTime:     time.Now(),
+		Attributes:  attrs,
+		Resource:    res,
+	}}.Snapshots()
+}
+
+// exportAttributes exports a span with attrs and the given resource, and
+// returns the attributes and resource the collector received.
+func exportAttributes(t *testing.T, res *resource.Resource, attrs []attribute.KeyValue, opts ...httpExporter.Option) (map[string]interface{}, map[string]interface{}) {
+	t.Helper()
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), opts...)
+	if err := e.ExportSpans(context.Background(), newSpanWithAttributes(res, attrs...)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	spans := c.spans(t)
+	if len(spans) != 1 {
+		t.Fatalf("collector received %d spans, want 1", len(spans))
+	}
+	return spans[0].Attrs, spans[0].Resource
+}
+
+func TestRedaction(t *testing.T) {
+	res := resource.NewSchemaless(attribute.String("host.owner", "ops@example.com"))
+	attrs, resAttrs := exportAttributes(t, res, []attribute.KeyValue{
+		attribute.String("http.request.header.authorization", "Bearer secret"),
+		attribute.String("message", "sent to alice@example.com and bob@example.com"),
+		attribute.StringSlice("recipients", []string{"carol@example.com", "team"}),
+		attribute.String("user.id", "42"),
+		attribute.String("path", "/cart"),
+	},
+		httpExporter.WithRedaction(
+			httpExporter.RedactionRule{Key: regexp.MustCompile(`authorization$`), Action: httpExporter.RedactDrop},
+			httpExporter.RedactionRule{Value: regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`), Action: httpExporter.RedactMask},
+			httpExporter.RedactionRule{Key: regexp.MustCompile(`^user\.id$`), Action: httpExporter.RedactHash},
+			httpExporter.RedactionRule{Action: httpExporter.RedactDrop}, // Ignored
+		),
+	)
+
+	if _, ok := attrs["http.request.header.authorization"]; ok {
+		t.Error("authorization header not dropped")
+	}
+	if got := attrs["message"]; got != "sent to **** and ****" {
+		t.Errorf("message = %v", got)
+	}
+	if got, _ := attrs["recipients"].([]interface{}); len(got) != 2 || got[0] != "****" || got[1] != "team" {
+		t.Errorf("recipients = %v", attrs["recipients"])
+	}
+	// The SHA-256 hash of "42".
+	if got := attrs["user.id"]; got != "73475cb40a568e8da8a045ced110137e159f890ac4da883b6b17dc651b3a8049" {
+		t.Errorf("user.id = %v", got)
+	}
+	if got := attrs["path"]; got != "/cart" {
+		t.Errorf("path = %v, want it untouched", got)
+	}
+	if got := resAttrs["host.owner"]; got != "****" {
+		t.Errorf("resource host.owner = %v, want it masked", got)
+	}
+}
+
+func TestRedactionDropsMatchingValues(t *testing.T) {
+	attrs, _ := exportAttributes(t, nil, []attribute.KeyValue{
+		attribute.String("card", "4111 1111 1111 1111"),
+		attribute.String("note", "no card"),
+	}, httpExporter.WithRedaction(httpExporter.RedactionRule{
+		Value:  regexp.MustCompile(`\d{4}( \d{4}){3}`),
+		Action: httpExporter.RedactDrop,
+	}))
+	if _, ok := attrs["card"]; ok || attrs["note"] != "no card" {
+		t.Errorf("attributes = %v, want only note", attrs)
+	}
+}
@@ -0,0 +1,71 @@
+package httpExporter
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+const (
+	requestIDHeader      = "X-Request-ID"
+	idempotencyKeyHeader = "Idempotency-Key"
+)
+
+// WithIdempotencyKey configures the exporter to send an Idempotency-Key
+// header identifying each batch. Unlike the X-Request-ID header, which is
+// unique to every request, the key is the same for all the retries of a
+// batch and its deliveries to fallback collectors, so collectors can
+// deduplicate batches retried after an ambiguous network failure. Batches
+// replayed from persistence get a new key.
+func WithIdempotencyKey() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.idempotencyKeys = true
+		return cfg
+	})
+}
+
+type (
+	requestIDKey      struct{}
+	idempotencyKeyKey struct{}
+)
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// withRequestID annotates ctx with the ID of the request being sent, for
+// logging.
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// withBatchKey annotates ctx with a new idempotency key for the batch being
+// exported, when idempotency keys are enabled.
+func (e *Exporter) withBatchKey(ctx context.Context) context.Context {
+	if !e.idempotencyKeys {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyKey{}, newRequestID())
+}
+
+// ensureBatchKey annotates ctx with a new idempotency key unless it already
+// holds one, for batches delivered outside of an export.
+func (e *Exporter) ensureBatchKey(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(idempotencyKeyKey{}).(string); ok {
+		return ctx
+	}
+	return e.withBatchKey(ctx)
+}
+
+// batchKey returns the idempotency key of ctx, if any.
+func batchKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok
+}
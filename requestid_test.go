@@ -0,0 +1,58 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// uuid matches version 4 UUIDs.
+var uuid = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestID(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), fastRetry)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) != 2 {
+		t.Fatalf("collector received %d requests, want a retry", len(reqs))
+	}
+	first, retry := reqs[0].Header.Get("X-Request-ID"), reqs[1].Header.Get("X-Request-ID")
+	if !uuid.MatchString(first) || !uuid.MatchString(retry) || first == retry {
+		t.Errorf("X-Request-ID = %q then %q, want a new UUID per request", first, retry)
+	}
+	if got := reqs[0].Header.Get("Idempotency-Key"); got != "" {
+		t.Errorf("Idempotency-Key = %q without WithIdempotencyKey", got)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), fastRetry, httpExporter.WithIdempotencyKey())
+	ctx := context.Background()
+	for _, name := range []string{"a", "b"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+
+	reqs := c.received()
+	if len(reqs) != 3 {
+		t.Fatalf("collector received %d requests, want 3", len(reqs))
+	}
+	first, retry, next := reqs[0].Header.Get("Idempotency-Key"), reqs[1].Header.Get("Idempotency-Key"), reqs[2].Header.Get("Idempotency-Key")
+	if !uuid.MatchString(first) || retry != first {
+		t.Errorf("Idempotency-Key = %q then %q, want the same key for the retry of a batch", first, retry)
+	}
+	if next == first || !uuid.MatchString(next) {
+		t.Errorf("Idempotency-Key of the next batch = %q, want a new key", next)
+	}
+}
@@ -0,0 +1,94 @@
+package httpExporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// resourceSessionHeader is the header carrying the session resource
+// references are scoped to.
+const resourceSessionHeader = "X-Resource-Session"
+
+// WithResourceReferences configures the JSON format to send each resource
+// once per session rather than with every span. Every SpanData carries the
+// resourceRef ID of its resource, a hash of its attributes, and only holds
+// the resource until a batch with it was delivered. Requests carry the
+// random ID of the session in the X-Resource-Session header, so collectors
+// can scope the resources they keep. A collector that does not know a
+// referenced resource, after a restart for instance, answers with 409
+// Conflict: the exporter then forgets the resources it sent and sends the
+// batch again with them. It cannot be combined with other formats, the
+// resource layout, mirrors, streaming or persistence.
+func WithResourceReferences() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.resourceReferences = true
+		return cfg
+	})
+}
+
+// resourceRefs tracks the resources delivered in a session.
+type resourceRefs struct {
+	session string
+
+	mu    sync.Mutex
+	ids   map[attribute.Distinct]string
+	known map[string]bool // Resources delivered to the collector
+}
+
+func newResourceRefs() *resourceRefs {
+	return &resourceRefs{
+		session: newRequestID(),
+		ids:     make(map[attribute.Distinct]string),
+		known:   make(map[string]bool),
+	}
+}
+
+// ref returns the reference ID of a resource, and whether the collector
+// knows it.
+func (r *resourceRefs) ref(res *resource.Resource) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, ok := r.ids[res.Equivalent()]
+	if !ok {
+		sum := sha256.Sum256([]byte(res.Encoded(attribute.DefaultEncoder())))
+		id = hex.EncodeToString(sum[:8])
+		r.ids[res.Equivalent()] = id
+	}
+	return id, r.known[id]
+}
+
+// confirm records that the resources of spans were delivered.
+func (r *resourceRefs) confirm(spans []sdktrace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, span := range spans {
+		if id, ok := r.ids[span.Resource().Equivalent()]; ok {
+			r.known[id] = true
+		}
+	}
+}
+
+// forget records that the collector lost the resources of the session.
+func (r *resourceRefs) forget() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.known = make(map[string]bool)
+}
+
+type resentKey struct{}
+
+// withResent marks a batch sent again with its resources.
+func withResent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resentKey{}, true)
+}
+
+func resent(ctx context.Context) bool {
+	ok, _ := ctx.Value(resentKey{}).(bool)
+	return ok
+}
@@ -0,0 +1,134 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// refSpan is a span sent with resource references.
+type refSpan struct {
+	Name        string                 `json:"name"`
+	Resource    map[string]interface{} `json:"resource"`
+	ResourceRef string                 `json:"resourceRef"`
+}
+
+// newResourceSpans returns an ended span with the given name and resource.
+func newResourceSpans(name string, res *resource.Resource) []sdktrace.ReadOnlySpan {
+	return tracetest.SpanStubs{{
+		Name:        name,
+		SpanContext: newSpanContext(newTraceID()),
+		StartTime:   time.Now(),
+		EndTime:     time.Now(),
+		Resource:    res,
+	}}.Snapshots()
+}
+
+// refSpans decodes the span of each request received by c.
+func refSpans(t *testing.T, c *collector) []refSpan {
+	t.Helper()
+	var spans []refSpan
+	for _, r := range c.received() {
+		var batch []refSpan
+		if err := json.Unmarshal(r.Body, &batch); err != nil || len(batch) != 1 {
+			t.Fatalf("invalid batch %s: %v", r.Body, err)
+		}
+		spans = append(spans, batch[0])
+	}
+	return spans
+}
+
+func TestResourceReferences(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithResourceReferences())
+	res := resource.NewSchemaless(attribute.String("service.name", "checkout"))
+
+	ctx := context.Background()
+	for _, name := range []string{"first", "referenced"} {
+		if err := e.ExportSpans(ctx, newResourceSpans(name, res)); err != nil {
+			t.Fatalf("ExportSpans(%s): %v", name, err)
+		}
+	}
+
+	spans := refSpans(t, c)
+	if spans[0].ResourceRef == "" || spans[0].Resource["service.name"] != "checkout" {
+		t.Errorf("first span %+v, want the resource and its reference", spans[0])
+	}
+	if spans[1].ResourceRef != spans[0].ResourceRef || spans[1].Resource != nil {
+		t.Errorf("second span %+v, want only the reference of the delivered resource", spans[1])
+	}
+	reqs := c.received()
+	session := reqs[0].Header.Get("X-Resource-Session")
+	if session == "" || reqs[1].Header.Get("X-Resource-Session") != session {
+		t.Errorf("requests carry sessions %q and %q, want the same session", session, reqs[1].Header.Get("X-Resource-Session"))
+	}
+}
+
+func TestResourceReferencesResent(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithResourceReferences())
+	res := resource.NewSchemaless(attribute.String("service.name", "checkout"))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newResourceSpans("first", res)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// A restarted collector answers references with 409 Conflict, upon
+	// which the exporter sends the resource again.
+	c.respond(http.StatusConflict)
+	if err := e.ExportSpans(ctx, newResourceSpans("resent", res)); err != nil {
+		t.Fatalf("ExportSpans after the collector forgot resources: %v", err)
+	}
+
+	spans := refSpans(t, c)
+	if len(spans) != 3 {
+		t.Fatalf("collector received %d requests, want 3 with the re-send", len(spans))
+	}
+	if spans[1].Name != "resent" || spans[1].Resource != nil {
+		t.Errorf("rejected span %+v, want only the reference", spans[1])
+	}
+	if spans[2].Name != "resent" || spans[2].Resource["service.name"] != "checkout" {
+		t.Errorf("resent span %+v, want the resource", spans[2])
+	}
+}
+
+func TestResourceReferencesFailedExport(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithResourceReferences())
+	res := resource.NewSchemaless(attribute.String("service.name", "checkout"))
+
+	ctx := context.Background()
+	c.respond(http.StatusServiceUnavailable)
+	if err := e.ExportSpans(ctx, newResourceSpans("failed", res)); err == nil {
+		t.Fatal("ExportSpans succeeded with a 503 response")
+	}
+	if err := e.ExportSpans(ctx, newResourceSpans("next", res)); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	// The resource was never delivered, so it is sent again.
+	if spans := refSpans(t, c); spans[1].Resource["service.name"] != "checkout" {
+		t.Errorf("span %+v after a failed export, want the resource", spans[1])
+	}
+}
+
+func TestResourceReferencesConflicts(t *testing.T) {
+	for name, opts := range map[string][]httpExporter.Option{
+		"format":    {httpExporter.WithFormat(httpExporter.Zipkin)},
+		"layout":    {httpExporter.WithPayloadLayout(httpExporter.ResourceLayout)},
+		"mirrors":   {httpExporter.WithAdditionalEndpoints("http://localhost:4319")},
+		"streaming": {httpExporter.WithStreaming()},
+	} {
+		opts := append(opts, httpExporter.WithResourceReferences())
+		if _, err := httpExporter.New("http://localhost:4318", opts...); err == nil {
+			t.Errorf("New succeeded with resource references and %s", name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package httpExporter
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig defines retry behavior for failed export requests.
+type RetryConfig struct {
+	// Enabled indicates whether to retry sending batches in case of export
+	// failure.
+	Enabled bool
+	// InitialInterval is the time to wait after the first failure before
+	// retrying.
+	InitialInterval time.Duration
+	// MaxInterval is the upper bound on backoff interval. Once this value is
+	// reached the delay between consecutive retries will always be
+	// MaxInterval.
+	MaxInterval time.Duration
+	// MaxElapsedTime is the maximum amount of time (including retries) spent
+	// trying to send a request/batch. Once this value is reached, the data
+	// is discarded.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultRetryConfig is the retry configuration used by WithRetry when
+// passed a zero RetryConfig with Enabled set.
+var DefaultRetryConfig = RetryConfig{
+	Enabled:         true,
+	InitialInterval: 5 * time.Second,
+	MaxInterval:     30 * time.Second,
+	MaxElapsedTime:  time.Minute,
+}
+
+// WithRetry configures the retry policy for transient export failures:
+// network errors and 429, 502, 503 and 504 responses. A Retry-After header
+// sent by the collector is honored. Retries are disabled by default. Each
+// collector endpoint is retried independently.
+func WithRetry(rc RetryConfig) Option {
+	return optionFunc(func(cfg config) config {
+		if rc.Enabled {
+			if rc.InitialInterval <= 0 {
+				rc.InitialInterval = DefaultRetryConfig.InitialInterval
+			}
+			if rc.MaxInterval <= 0 {
+				rc.MaxInterval = DefaultRetryConfig.MaxInterval
+			}
+			if rc.MaxElapsedTime <= 0 {
+				rc.MaxElapsedTime = DefaultRetryConfig.MaxElapsedTime
+			}
+		}
+		cfg.retry = rc
+		return cfg
+	})
+}
+
+// retryableError marks an export failure as transient. It matches
+// ErrCollectorUnavailable.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration // Delay requested by the collector, if any
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+
+func (e retryableError) Unwrap() error { return e.err }
+
+func (e retryableError) Is(target error) bool { return target == ErrCollectorUnavailable }
+
+// retryableStatus reports whether a response status is transient.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return 0
+}
+
+// do calls fn until it succeeds, returns a non retryable error, or the
+// retry budget or ctx is exhausted.
+func (rc RetryConfig) do(ctx context.Context, fn func() error) error {
+	err := fn()
+	if !rc.Enabled {
+		return err
+	}
+	deadline := time.Now().Add(rc.MaxElapsedTime)
+	// A RetryConfig not set through WithRetry may hold zero intervals: fall
+	// back to the defaults rather than retry without delay.
+	interval, maxInterval := rc.InitialInterval, rc.MaxInterval
+	if interval <= 0 {
+		interval = DefaultRetryConfig.InitialInterval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+	for err != nil {
+		if !Retryable(err) {
+			return err
+		}
+		var rerr retryableError
+		errors.As(err, &rerr)
+		// Randomize the delay by ±50% so concurrent exporters do not retry in
+		// lockstep.
+		delay := time.Duration(rand.Int63n(int64(interval))) + interval/2
+		if rerr.retryAfter > delay {
+			delay = rerr.retryAfter
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+		err = fn()
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// fastRetry retries quickly, for up to a second.
+var fastRetry = httpExporter.WithRetry(httpExporter.RetryConfig{
+	Enabled:         true,
+	InitialInterval: time.Millisecond,
+	MaxInterval:     5 * time.Millisecond,
+	MaxElapsedTime:  time.Second,
+})
+
+func TestRetryTransientFailures(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway)
+	e := newExporter(t, c.URL(), fastRetry)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.count(); got != 4 {
+		t.Errorf("collector received %d requests, want 4", got)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestRetryPermanentFailure(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusBadRequest)
+	e := newExporter(t, c.URL(), fastRetry)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 400 response")
+	}
+	if got := c.count(); got != 1 {
+		t.Errorf("collector received %d requests, want 1 as 400 is not retried", got)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithRetry(httpExporter.RetryConfig{
+		Enabled:         true,
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+		MaxElapsedTime:  100 * time.Millisecond,
+	}))
+	start := time.Now()
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded while the collector is unavailable")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("ExportSpans retried for %v, past MaxElapsedTime", d)
+	}
+	if got := c.count(); got < 2 {
+		t.Errorf("collector received %d requests, want retries", got)
+	}
+}
+
+func TestRetryAfterBeyondBudget(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	e := newExporter(t, srv.URL, fastRetry)
+
+	start := time.Now()
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded while the collector is throttling")
+	}
+	// Waiting a minute would exceed MaxElapsedTime, so it gives up at once.
+	if d := time.Since(start); d > 500*time.Millisecond || requests != 1 {
+		t.Errorf("ExportSpans sent %d requests in %v, want 1 without waiting", requests, d)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL(), httpExporter.WithRetry(httpExporter.RetryConfig{Enabled: true}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := e.ExportSpans(ctx, newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded while the collector is unavailable")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("ExportSpans waited %v after its context was done", d)
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusServiceUnavailable)
+	e := newExporter(t, c.URL())
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Error("ExportSpans succeeded with a 503 response")
+	}
+	if got := c.count(); got != 1 {
+		t.Errorf("collector received %d requests, want 1 without retries", got)
+	}
+}
@@ -0,0 +1,208 @@
+package httpExporter
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// WithRouter configures the exporter to route spans to collectors by their
+// resource and instrumentation scope, such as by tenant, environment or
+// region. route returns the collector URL spans are sent to, or an empty
+// string for the primary collector. Each batch is partitioned by route and
+// every destination is sent its own requests concurrently: a failing
+// destination does not prevent the delivery to the others, and the export
+// returns the first error. Routed collectors get the default path of the
+// format like the primary one, but not its replicas, fallbacks, circuit
+// breaker or persistence. Routed URLs resolving to the same collector share
+// its connections and accounting, and the 256 most recently used collectors
+// are kept: the accounting of others is discarded.
+func WithRouter(route func(res *resource.Resource, scope instrumentation.Scope) string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.router = route
+		return cfg
+	})
+}
+
+// maxRoutedEndpoints bounds the number of collectors a router keeps
+// endpoints for.
+const maxRoutedEndpoints = 256
+
+// router resolves the routes of spans to their endpoints.
+type router struct {
+	route       func(*resource.Resource, instrumentation.Scope) string
+	newEndpoint func(collectorURL string) (*endpoint, error)
+	size        int
+
+	mu        sync.Mutex
+	endpoints map[string]*list.Element // By resolved URL
+	aliases   map[string]string        // Resolved URLs of the routed URLs
+	order     *list.List               // Endpoints, most recently used first
+}
+
+func newRouter(route func(*resource.Resource, instrumentation.Scope) string, newEndpoint func(string) (*endpoint, error)) *router {
+	return &router{
+		route:       route,
+		newEndpoint: newEndpoint,
+		size:        maxRoutedEndpoints,
+		endpoints:   make(map[string]*list.Element),
+		aliases:     make(map[string]string),
+		order:       list.New(),
+	}
+}
+
+// endpoint returns the endpoint of a collector URL, creating it on first
+// use unless another URL resolves to the same collector. The least recently
+// used endpoint is evicted beyond the size of the router.
+func (r *router) endpoint(collectorURL string) (*endpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resolved, ok := r.aliases[collectorURL]; ok {
+		el := r.endpoints[resolved]
+		r.order.MoveToFront(el)
+		return el.Value.(*endpoint), nil
+	}
+	ep, err := r.newEndpoint(collectorURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.aliases) >= 4*r.size {
+		// Many routed URLs resolve to the same collectors: the aliases
+		// are only a cache, so start over rather than grow.
+		r.aliases = make(map[string]string)
+	}
+	r.aliases[collectorURL] = ep.url
+	if el, ok := r.endpoints[ep.url]; ok {
+		r.order.MoveToFront(el)
+		return el.Value.(*endpoint), nil
+	}
+	r.endpoints[ep.url] = r.order.PushFront(ep)
+	if r.order.Len() > r.size {
+		r.evict(r.order.Back())
+	}
+	return ep, nil
+}
+
+// evict discards an endpoint, ending its stream if one is open. r.mu must be
+// held.
+func (r *router) evict(el *list.Element) {
+	ep := r.order.Remove(el).(*endpoint)
+	delete(r.endpoints, ep.url)
+	for alias, resolved := range r.aliases {
+		if resolved == ep.url {
+			delete(r.aliases, alias)
+		}
+	}
+	if ep.stream != nil {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+			defer cancel()
+			_ = ep.stream.close(ctx)
+		}()
+	}
+}
+
+// all returns the endpoints routed to, most recently used first.
+func (r *router) all() []*endpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	eps := make([]*endpoint, 0, r.order.Len())
+	for el := r.order.Front(); el != nil; el = el.Next() {
+		eps = append(eps, el.Value.(*endpoint))
+	}
+	return eps
+}
+
+// routedSpans are spans sharing a route.
+type routedSpans struct {
+	url   string // Empty for the primary collector
+	spans []sdktrace.ReadOnlySpan
+}
+
+// partition groups spans by route, in order of first appearance. The route
+// is computed once per resource and scope.
+func (r *router) partition(spans []sdktrace.ReadOnlySpan) []routedSpans {
+	type key struct {
+		res   attribute.Distinct
+		scope instrumentation.Scope
+	}
+	urls := make(map[key]string)
+	index := make(map[string]int)
+	var routes []routedSpans
+	for _, span := range spans {
+		k := key{res: span.Resource().Equivalent(), scope: span.InstrumentationScope()}
+		u, ok := urls[k]
+		if !ok {
+			u = r.route(span.Resource(), span.InstrumentationScope())
+			urls[k] = u
+		}
+		i, ok := index[u]
+		if !ok {
+			i = len(routes)
+			index[u] = i
+			routes = append(routes, routedSpans{url: u})
+		}
+		routes[i].spans = append(routes[i].spans, span)
+	}
+	return routes
+}
+
+// exportRoutes sends spans to the collectors they are routed to.
+func (e *Exporter) exportRoutes(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	routes := e.router.partition(spans)
+	if len(routes) == 1 && routes[0].url == "" {
+		return e.exportResources(ctx, spans, e.templated)
+	}
+	errs := make([]error, len(routes))
+	var wg sync.WaitGroup
+	for i, r := range routes {
+		wg.Add(1)
+		go func(i int, r routedSpans) {
+			defer wg.Done()
+			if r.url == "" {
+				errs[i] = e.exportResources(ctx, r.spans, e.templated)
+				return
+			}
+			ep, err := e.router.endpoint(r.url)
+			if err != nil {
+				e.recordFailed(err)
+				errs[i] = e.errf("unable to route %d spans: %w", len(r.spans), err)
+				return
+			}
+			errs[i] = e.exportResources(withRoute(ctx, ep), r.spans, ep.templated)
+		}(i, r)
+	}
+	wg.Wait()
+	var first error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		if routes[i].url != "" {
+			e.warnf("failed to export %d spans routed to %s: %v", len(routes[i].spans), routes[i].url, err)
+		}
+	}
+	return first
+}
+
+type routeKey struct{}
+
+// withRoute returns a context sending batches to the routed endpoint ep
+// instead of the primary collector.
+func withRoute(ctx context.Context, ep *endpoint) context.Context {
+	return context.WithValue(ctx, routeKey{}, ep)
+}
+
+// routedEndpoint returns the endpoint a batch is routed to, or nil.
+func routedEndpoint(ctx context.Context) *endpoint {
+	ep, _ := ctx.Value(routeKey{}).(*endpoint)
+	return ep
+}
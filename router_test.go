@@ -0,0 +1,174 @@
+package httpExporter
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// routeCollector records the names of the spans it receives and the paths
+// they are posted to.
+type routeCollector struct {
+	*httptest.Server
+	status int
+
+	mu    sync.Mutex
+	names []string
+	paths []string
+}
+
+func newRouteCollector(t *testing.T, status int) *routeCollector {
+	t.Helper()
+	c := &routeCollector{status: status}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		var spans []struct{ Name string }
+		if err := json.Unmarshal(b, &spans); err != nil {
+			t.Errorf("invalid batch %s: %v", b, err)
+		}
+		c.mu.Lock()
+		c.paths = append(c.paths, r.URL.Path)
+		if c.status == http.StatusOK {
+			for _, s := range spans {
+				c.names = append(c.names, s.Name)
+			}
+		}
+		c.mu.Unlock()
+		w.WriteHeader(c.status)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *routeCollector) received() ([]string, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := append([]string(nil), c.names...)
+	sort.Strings(names)
+	return names, append([]string(nil), c.paths...)
+}
+
+// tenantSpans returns a span named after each tenant, with the tenant in
+// its resource.
+func tenantSpans(tenants ...string) []sdktrace.ReadOnlySpan {
+	stubs := make(tracetest.SpanStubs, 0, len(tenants))
+	for _, tenant := range tenants {
+		stubs = append(stubs, tracetest.SpanStub{
+			Name:      tenant,
+			StartTime: time.Now(),
+			Resource:  resource.NewSchemaless(attribute.String("tenant", tenant)),
+		})
+	}
+	return stubs.Snapshots()
+}
+
+// routeTenants returns a route sending the spans of each tenant of routes to
+// its collector, and other spans to the primary one.
+func routeTenants(routes map[string]string) func(*resource.Resource, instrumentation.Scope) string {
+	return func(res *resource.Resource, _ instrumentation.Scope) string {
+		v, _ := res.Set().Value("tenant")
+		return routes[v.AsString()]
+	}
+}
+
+func TestRouter(t *testing.T) {
+	primary := newRouteCollector(t, http.StatusOK)
+	acme := newRouteCollector(t, http.StatusOK)
+	e, err := New(primary.URL, WithRouter(routeTenants(map[string]string{"acme": acme.URL})))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+
+	if err := e.ExportSpans(context.Background(), tenantSpans("acme", "globex", "acme", "initech")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	names, paths := acme.received()
+	if len(names) != 2 || names[0] != "acme" || names[1] != "acme" {
+		t.Errorf("routed collector received %v, want the spans of acme", names)
+	}
+	if len(paths) != 1 || paths[0] != "/" {
+		t.Errorf("routed collector received requests to %v, want one to /", paths)
+	}
+	if names, _ := primary.received(); len(names) != 2 || names[0] != "globex" || names[1] != "initech" {
+		t.Errorf("primary collector received %v, want the spans of the other tenants", names)
+	}
+}
+
+func TestRouterFailingDestination(t *testing.T) {
+	primary := newRouteCollector(t, http.StatusOK)
+	acme := newRouteCollector(t, http.StatusServiceUnavailable)
+	e, err := New(primary.URL, WithRouter(routeTenants(map[string]string{"acme": acme.URL})))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+
+	if err := e.ExportSpans(context.Background(), tenantSpans("acme", "globex")); err == nil {
+		t.Error("ExportSpans succeeded with a failing routed collector")
+	}
+	if names, _ := primary.received(); len(names) != 1 || names[0] != "globex" {
+		t.Errorf("primary collector received %v, want [globex]", names)
+	}
+}
+
+func TestRouterInvalidURL(t *testing.T) {
+	primary := newRouteCollector(t, http.StatusOK)
+	e, err := New(primary.URL, WithRouter(routeTenants(map[string]string{"acme": "://invalid"})))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+
+	if err := e.ExportSpans(context.Background(), tenantSpans("acme", "globex")); err == nil {
+		t.Error("ExportSpans succeeded with an invalid routed URL")
+	}
+	if names, _ := primary.received(); len(names) != 1 || names[0] != "globex" {
+		t.Errorf("primary collector received %v, want [globex]", names)
+	}
+}
+
+func TestRouterEndpoints(t *testing.T) {
+	e, err := New("http://localhost:4318", WithFormat(Zipkin), WithRouter(routeTenants(nil)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer e.Shutdown(context.Background())
+	r := e.router
+	r.size = 2
+
+	a, err := r.endpoint("http://collector-a:4318")
+	if err != nil {
+		t.Fatalf("endpoint: %v", err)
+	}
+	// Both URLs resolve to the Zipkin path of the collector.
+	if same, _ := r.endpoint("http://collector-a:4318/"); same != a {
+		t.Error("URLs of the same collector got distinct endpoints")
+	}
+	r.endpoint("http://collector-b:4318")
+	r.endpoint("http://collector-c:4318")
+	eps := r.all()
+	if len(eps) != 2 {
+		t.Fatalf("router keeps %d endpoints, want 2", len(eps))
+	}
+	for _, ep := range eps {
+		if ep == a {
+			t.Error("router kept the least recently used endpoint")
+		}
+	}
+	if len(r.aliases) != 2 {
+		t.Errorf("router keeps %d aliases, want those of the 2 endpoints", len(r.aliases))
+	}
+}
@@ -0,0 +1,36 @@
+package httpExporter
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// WithServiceName configures the name of the service producing the spans. It
+// is sent as the serviceName field of SpanData, and set as the service.name
+// resource attribute of spans whose resource has none or the unknown_service
+// default of the SDK, for every format.
+func WithServiceName(name string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.serviceName = name
+		return cfg
+	})
+}
+
+// withServiceName sets the service.name attribute in resource attributes
+// that have none, or the default of the SDK. attrs is not modified.
+func (e *Exporter) withServiceName(attrs []attribute.KeyValue) []attribute.KeyValue {
+	for i, kv := range attrs {
+		if kv.Key != semconv.ServiceNameKey {
+			continue
+		}
+		if !strings.HasPrefix(kv.Value.AsString(), "unknown_service") {
+			return attrs
+		}
+		out := append([]attribute.KeyValue(nil), attrs...)
+		out[i] = semconv.ServiceNameKey.String(e.serviceName)
+		return out
+	}
+	return append(append(make([]attribute.KeyValue, 0, len(attrs)+1), attrs...), semconv.ServiceNameKey.String(e.serviceName))
+}
@@ -0,0 +1,61 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestServiceName(t *testing.T) {
+	for _, tt := range []struct {
+		resource *resource.Resource
+		want     string
+	}{
+		{resource.Empty(), "checkout"},
+		{resource.NewSchemaless(attribute.String("service.name", "unknown_service:app")), "checkout"},
+		{resource.NewSchemaless(attribute.String("service.name", "billing")), "billing"},
+	} {
+		c := newCollector(t)
+		e := newExporter(t, c.URL(), httpExporter.WithServiceName("checkout"))
+		if err := e.ExportSpans(context.Background(), newSpanWithAttributes(tt.resource)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+		reqs := c.received()
+		var spans []struct {
+			ServiceName string                 `json:"serviceName"`
+			Resource    map[string]interface{} `json:"resource"`
+		}
+		if err := json.Unmarshal(reqs[0].Body, &spans); err != nil || len(spans) != 1 {
+			t.Fatalf("invalid batch %s: %v", reqs[0].Body, err)
+		}
+		if spans[0].ServiceName != "checkout" {
+			t.Errorf("serviceName = %q, want checkout", spans[0].ServiceName)
+		}
+		if got := spans[0].Resource["service.name"]; got != tt.want {
+			t.Errorf("resource service.name = %v, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestServiceNameOtherFormats(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithServiceName("checkout"), httpExporter.WithFormat(httpExporter.Zipkin))
+	if err := e.ExportSpans(context.Background(), newSpanWithAttributes(resource.Empty())); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	var spans []struct {
+		LocalEndpoint struct {
+			ServiceName string `json:"serviceName"`
+		} `json:"localEndpoint"`
+	}
+	if err := json.Unmarshal(c.received()[0].Body, &spans); err != nil || len(spans) != 1 {
+		t.Fatalf("invalid Zipkin batch: %v", err)
+	}
+	if got := spans[0].LocalEndpoint.ServiceName; got != "checkout" {
+		t.Errorf("Zipkin localEndpoint.serviceName = %q, want checkout", got)
+	}
+}
@@ -0,0 +1,120 @@
+package httpExporter_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestShutdownWaitsForExports(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(200 * time.Millisecond)
+	e := newExporter(t, c.URL())
+
+	ctx := context.Background()
+	exported := make(chan error, 1)
+	go func() { exported <- e.ExportSpans(ctx, newSpans("in flight")) }()
+	waitFor(t, "the request", func() bool { return c.count() == 1 })
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	select {
+	case err := <-exported:
+		if err != nil {
+			t.Errorf("in-flight ExportSpans = %v", err)
+		}
+	default:
+		t.Error("Shutdown returned before the in-flight export")
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(500 * time.Millisecond)
+	e := newExporter(t, c.URL())
+
+	ctx := context.Background()
+	go e.ExportSpans(ctx, newSpans("in flight"))
+	waitFor(t, "the request", func() bool { return c.count() == 1 })
+	sctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := e.Shutdown(sctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want DeadlineExceeded", err)
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Errorf("second Shutdown = %v", err)
+	}
+}
+
+func TestShutdownClosesIdleConnections(t *testing.T) {
+	l, u := listenUnix(t)
+	var closed int32
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		ConnState: func(_ net.Conn, state http.ConnState) {
+			if state == http.StateClosed {
+				atomic.AddInt32(&closed, 1)
+			}
+		},
+	}
+	go srv.Serve(l)
+	defer srv.Close()
+
+	e := newExporter(t, u)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := atomic.LoadInt32(&closed); got != 0 {
+		t.Fatalf("%d connections closed before Shutdown", got)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	waitFor(t, "the idle connection to be closed", func() bool { return atomic.LoadInt32(&closed) == 1 })
+}
+
+func TestExportSpansAfterShutdown(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL())
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != httpExporter.ErrShutdown {
+		t.Errorf("ExportSpans after Shutdown = %v, want ErrShutdown", err)
+	}
+	if got := c.count(); got != 0 {
+		t.Errorf("collector received %d requests after Shutdown", got)
+	}
+}
+
+// idleCloser is a transport counting the calls to CloseIdleConnections.
+type idleCloser struct {
+	http.RoundTripper
+	closes int32
+}
+
+func (t *idleCloser) CloseIdleConnections() {
+	atomic.AddInt32(&t.closes, 1)
+}
+
+func TestShutdownKeepsClientConnections(t *testing.T) {
+	c := newCollector(t)
+	transport := &idleCloser{RoundTripper: http.DefaultTransport}
+	e := newExporter(t, c.URL(), httpExporter.WithClient(&http.Client{Transport: transport}))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	// The client may be shared with the rest of the process.
+	if got := atomic.LoadInt32(&transport.closes); got != 0 {
+		t.Errorf("Shutdown closed the idle connections of the client %d times", got)
+	}
+}
@@ -0,0 +1,69 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestMaxPayloadBytes(t *testing.T) {
+	c := newCollector(t)
+	const limit = 1000
+	e := newExporter(t, c.URL(), httpExporter.WithMaxPayloadBytes(limit))
+	if err := e.ExportSpans(context.Background(), newSpans("a", "b", "c", "d", "e")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	reqs := c.received()
+	if len(reqs) < 3 {
+		t.Errorf("collector received %d requests, want the batch split", len(reqs))
+	}
+	for _, r := range reqs {
+		if len(r.Body) > limit {
+			t.Errorf("request body of %d bytes exceeds the %d byte limit", len(r.Body), limit)
+		}
+	}
+	if got := c.names(t); !equal(got, "a", "b", "c", "d", "e") {
+		t.Errorf("collector received %v, want all spans in order", got)
+	}
+}
+
+func TestMaxPayloadBytesSingleSpan(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithMaxPayloadBytes(10))
+	// A single span cannot be split further, so it is sent as is.
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestPayloadTooLarge(t *testing.T) {
+	c := newCollector(t)
+	c.respond(http.StatusRequestEntityTooLarge)
+	e := newExporter(t, c.URL())
+	if err := e.ExportSpans(context.Background(), newSpans("a", "b", "c", "d")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	batches := c.batches(t)
+	if len(batches) != 2 || !equal(spanNames(batches[0]), "a", "b") || !equal(spanNames(batches[1]), "c", "d") {
+		t.Errorf("collector accepted %v, want the batch in halves", batches)
+	}
+}
+
+func TestPayloadTooLargeSingleSpan(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusRequestEntityTooLarge)
+	e := newExporter(t, c.URL())
+	if err := e.ExportSpans(context.Background(), newSpans("a", "b")); err == nil {
+		t.Fatal("ExportSpans succeeded with spans the collector rejects as too large")
+	}
+	// The batch and each of its halves.
+	if got := c.count(); got != 3 {
+		t.Errorf("collector received %d requests, want 3", got)
+	}
+}
@@ -0,0 +1,57 @@
+package httpExporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// WithStartupCheck configures New to send an empty batch to the primary
+// collector, within ctx, and to fail with a descriptive error if the
+// collector cannot be reached, rejects the credentials (401 and 403
+// responses) or does not serve the URL (404 responses), so that
+// misconfigurations are caught at boot rather than by silently dropped
+// spans. Other responses pass the check. It is skipped in dry run mode.
+func WithStartupCheck(ctx context.Context) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.startupCheck = ctx
+		return cfg
+	})
+}
+
+// startupCheck sends an empty batch to the primary collector.
+func (e *Exporter) startupCheck(ctx context.Context) error {
+	body, err := e.encoder.encode(nil)
+	if err != nil {
+		return fmt.Errorf("startup check: unable to serialize empty batch: %v", err)
+	}
+	ep := e.endpoints[0]
+	ctx, cancel := e.withTimeout(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.requestURL(ctx), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("startup check: failed to create request to %s: %v", ep.url, err)
+	}
+	req.Header.Set("User-Agent", e.userAgent)
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", e.encoder.contentType())
+	resp, err := ep.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("startup check: collector %s is unreachable: %w", ep.url, retryableError{err: err})
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("startup check: collector %s rejected the credentials with status %s, check the authentication headers", ep.url, resp.Status)
+	case http.StatusNotFound:
+		return fmt.Errorf("startup check: collector %s answered with status %s, check the URL path", ep.url, resp.Status)
+	}
+	e.logf("startup check: collector %s answered with status %s", ep.url, resp.Status)
+	return nil
+}
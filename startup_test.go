@@ -0,0 +1,62 @@
+package httpExporter_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestStartupCheck(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		err    string
+	}{
+		{http.StatusOK, ""},
+		{http.StatusBadRequest, ""},
+		{http.StatusUnauthorized, "rejected the credentials"},
+		{http.StatusForbidden, "rejected the credentials"},
+		{http.StatusNotFound, "check the URL path"},
+	} {
+		c := newCollector(t)
+		c.setStatus(tt.status)
+		e, err := httpExporter.New(c.URL(), httpExporter.WithStartupCheck(context.Background()),
+			httpExporter.WithHeaders(map[string]string{"Authorization": "Bearer token"}))
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("New with a %d response: %v", tt.status, err)
+				continue
+			}
+			e.Shutdown(context.Background())
+		} else if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("New with a %d response = %v, want an error that %s", tt.status, err, tt.err)
+		}
+		reqs := c.received()
+		if len(reqs) != 1 || reqs[0].Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("collector received %+v, want a request with the configured headers", reqs)
+		}
+		if body := bytes.TrimSpace(reqs[0].Body); string(body) != "[]" {
+			t.Errorf("startup check sent %s, want an empty batch", body)
+		}
+	}
+}
+
+func TestStartupCheckUnreachable(t *testing.T) {
+	c := newCollector(t)
+	url := c.URL()
+	c.server.Close()
+	_, err := httpExporter.New(url, httpExporter.WithStartupCheck(context.Background()))
+	if err == nil || !strings.Contains(err.Error(), "unreachable") || !httpExporter.Retryable(err) {
+		t.Errorf("New with an unreachable collector = %v, want a retryable error", err)
+	}
+}
+
+func TestStartupCheckDryRun(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := httpExporter.New("http://localhost:1", httpExporter.WithStartupCheck(context.Background()), httpExporter.WithDryRun(&buf)); err != nil {
+		t.Errorf("New in dry run mode: %v", err)
+	}
+}
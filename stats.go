@@ -0,0 +1,65 @@
+package httpExporter
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats is a snapshot of the export counters of an exporter.
+type Stats struct {
+	BatchesSent        uint64        // Batches accepted by the primary collector
+	BatchesFailed      uint64        // Batches the primary collector did not accept
+	SpansSent          uint64        // Spans accepted by the primary collector
+	SpansRejected      uint64        // Spans rejected by partial success responses
+	SpansDropped       uint64        // Spans dropped by the queue, rate limit or circuit breaker
+	LastError          error         // Last error of a failed batch, if any
+	LastExportDuration time.Duration // Duration of the last batch export
+}
+
+// stats accumulates the export counters.
+type stats struct {
+	mu sync.Mutex
+	Stats
+}
+
+// Stats returns a snapshot of the export counters.
+func (e *Exporter) Stats() Stats {
+	e.stats.mu.Lock()
+	defer e.stats.mu.Unlock()
+	return e.stats.Stats
+}
+
+// recordExported accounts for a batch accepted by the primary collector,
+// of which rejected spans were rejected by a partial success response.
+func (e *Exporter) recordExported(spans, rejected int) {
+	e.stats.mu.Lock()
+	e.stats.BatchesSent++
+	e.stats.SpansSent += uint64(spans - rejected)
+	e.stats.SpansRejected += uint64(rejected)
+	e.stats.mu.Unlock()
+	e.telemetry.exported(spans-rejected, rejected)
+}
+
+// recordDropped accounts for spans dropped without being sent.
+func (e *Exporter) recordDropped(spans int) {
+	e.stats.mu.Lock()
+	e.stats.SpansDropped += uint64(spans)
+	e.stats.mu.Unlock()
+	e.telemetry.dropped(spans)
+}
+
+// recordFailed accounts for a batch the primary collector did not accept.
+func (e *Exporter) recordFailed(err error) {
+	e.stats.mu.Lock()
+	e.stats.BatchesFailed++
+	e.stats.LastError = err
+	e.stats.mu.Unlock()
+	e.telemetry.failed()
+}
+
+// recordDuration accounts for the duration of a batch export.
+func (e *Exporter) recordDuration(d time.Duration) {
+	e.stats.mu.Lock()
+	e.stats.LastExportDuration = d
+	e.stats.mu.Unlock()
+}
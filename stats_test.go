@@ -0,0 +1,62 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestStats(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL())
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.ExportSpans(ctx, newSpans("c")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	c.setStatus(http.StatusBadRequest)
+	exportErr := e.ExportSpans(ctx, newSpans("d"))
+	if exportErr == nil {
+		t.Fatal("ExportSpans succeeded with a 400 response")
+	}
+
+	s := e.Stats()
+	if s.BatchesSent != 2 || s.SpansSent != 3 || s.BatchesFailed != 1 {
+		t.Errorf("Stats() = %+v, want 2 batches and 3 spans sent, 1 batch failed", s)
+	}
+	if s.LastError == nil || s.LastError.Error() != exportErr.Error() {
+		t.Errorf("LastError = %v, want %v", s.LastError, exportErr)
+	}
+	if s.LastExportDuration <= 0 || s.LastExportDuration > time.Second {
+		t.Errorf("LastExportDuration = %v", s.LastExportDuration)
+	}
+}
+
+func TestStatsDropped(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(200 * time.Millisecond)
+	e := newExporter(t, c.URL(), httpExporter.WithQueue(1, httpExporter.DropNewest))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("sent")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the first request", func() bool { return c.count() == 1 })
+	for _, spans := range [][]string{{"queued"}, {"dropped 1", "dropped 2"}} {
+		if err := e.ExportSpans(ctx, newSpans(spans...)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if s := e.Stats(); s.SpansDropped != 2 || s.SpansSent != 2 {
+		t.Errorf("Stats() = %+v, want 2 spans sent and 2 dropped", s)
+	}
+}
@@ -0,0 +1,83 @@
+package httpExporter
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// PayloadVersion selects the revision of the SpanData payload, used by the
+// JSON, CBOR and Elasticsearch formats, letting receivers opt in to groups
+// of new fields. Versions do not freeze the payload: fields may be added to
+// every version, such as traceState and flags, or serviceName and
+// resourceRef when their options are set, so receivers should ignore
+// unknown fields.
+type PayloadVersion int
+
+const (
+	// PayloadV1 is the original payload. This is the default.
+	PayloadV1 PayloadVersion = 1
+	// PayloadV2 adds the numeric status code of spans, as defined by OTLP
+	// (0 unset, 1 ok, 2 error), and the HTTP and gRPC status codes found in
+	// their semantic convention attributes.
+	PayloadV2 PayloadVersion = 2
+)
+
+// String returns the name of the payload version.
+func (v PayloadVersion) String() string {
+	switch v {
+	case PayloadV1:
+		return "v1"
+	case PayloadV2:
+		return "v2"
+	}
+	return "unknown"
+}
+
+// WithPayloadVersion configures the revision of the SpanData payload.
+func WithPayloadVersion(v PayloadVersion) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.payloadVersion = v
+		return cfg
+	})
+}
+
+// Semantic convention attributes holding the status of HTTP and gRPC calls.
+const (
+	httpStatusCodeKey         = attribute.Key("http.status_code")
+	httpResponseStatusCodeKey = attribute.Key("http.response.status_code")
+	rpcGRPCStatusCodeKey      = attribute.Key("rpc.grpc.status_code")
+)
+
+// setStatusCodes fills the status codes of the version 2 payload.
+func setStatusCodes(httpSpan *SpanData, span sdktrace.ReadOnlySpan) {
+	code := int(statusCodeToOTLP(span.Status().Code))
+	httpSpan.StatusCodeNumber = &code
+	for _, kv := range span.Attributes() {
+		switch kv.Key {
+		case httpStatusCodeKey, httpResponseStatusCodeKey:
+			httpSpan.HTTPStatusCode = intAttribute(kv.Value)
+		case rpcGRPCStatusCodeKey:
+			httpSpan.RPCStatusCode = intAttribute(kv.Value)
+		}
+	}
+}
+
+// intAttribute returns the value of an integer attribute, which may have
+// been recorded as a string.
+func intAttribute(v attribute.Value) *int {
+	var n int
+	switch v.Type() {
+	case attribute.INT64:
+		n = int(v.AsInt64())
+	case attribute.STRING:
+		var err error
+		if n, err = strconv.Atoi(v.AsString()); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return &n
+}
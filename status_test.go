@@ -0,0 +1,40 @@
+package httpExporter
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPayloadV2(t *testing.T) {
+	_, body := encodeTestSpans(t, WithPayloadVersion(PayloadV2))
+	checkGoldenJSON(t, "json_v2.json", body)
+}
+
+func TestPayloadV2StatusCodes(t *testing.T) {
+	spans := tracetest.SpanStubs{
+		{Status: sdktrace.Status{Code: codes.Error}, Attributes: []attribute.KeyValue{attribute.String("http.response.status_code", "503")}},
+		{Status: sdktrace.Status{Code: codes.Ok}, Attributes: []attribute.KeyValue{attribute.Int("rpc.grpc.status_code", 0)}},
+		{Attributes: []attribute.KeyValue{attribute.String("http.status_code", "unknown")}},
+	}.Snapshots()
+	data := newConverter(newConfig(WithPayloadVersion(PayloadV2))).convertSpansToHttp(spans)
+
+	if s := data[0]; *s.StatusCodeNumber != 2 || s.HTTPStatusCode == nil || *s.HTTPStatusCode != 503 || s.RPCStatusCode != nil {
+		t.Errorf("error span status %v, HTTP status %v, gRPC status %v", *s.StatusCodeNumber, s.HTTPStatusCode, s.RPCStatusCode)
+	}
+	if s := data[1]; *s.StatusCodeNumber != 1 || s.RPCStatusCode == nil || *s.RPCStatusCode != 0 {
+		t.Errorf("ok span status %v, gRPC status %v", *s.StatusCodeNumber, s.RPCStatusCode)
+	}
+	if s := data[2]; *s.StatusCodeNumber != 0 || s.HTTPStatusCode != nil {
+		t.Errorf("unset span status %v, HTTP status %v", *s.StatusCodeNumber, s.HTTPStatusCode)
+	}
+
+	for _, s := range newConverter(newConfig()).convertSpansToHttp(spans) {
+		if s.StatusCodeNumber != nil || s.HTTPStatusCode != nil || s.RPCStatusCode != nil {
+			t.Errorf("version 1 payload holds status codes: %+v", s)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package httpExporter
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// streamFramingHeader announces the framing of a streamed request body.
+const streamFramingHeader = "X-Stream-Framing"
+
+// WithStreaming configures the exporter to keep a single long-lived chunked
+// HTTP request open to the collector and push each encoded batch as a frame
+// of its body, avoiding connection and TLS setup costs for frequent small
+// batches. Every frame is the encoded batch prefixed with its length as a
+// 4 byte big-endian integer. A batch is considered exported once it has been
+// written to the connection. The stream is reopened when the collector ends
+// it. The HTTP client should not have a Timeout set, as it would bound the
+// lifetime of the stream. Streaming cannot be combined with gzip
+// compression, payload encryption or templated collector URLs.
+func WithStreaming() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.streaming = true
+		return cfg
+	})
+}
+
+// stream is a long-lived streaming request to the collector.
+type stream struct {
+	exporter *Exporter
+	endpoint *endpoint
+
+	mu     sync.Mutex
+	conn   *streamConn // nil when no stream is open
+	closed bool        // Whether the exporter was shut down
+}
+
+// streamConn is a single streaming request.
+type streamConn struct {
+	pw   *io.PipeWriter
+	done chan struct{}
+	err  error // Result of the request, valid once done is closed
+}
+
+func newStream(e *Exporter, ep *endpoint) *stream {
+	return &stream{exporter: e, endpoint: ep}
+}
+
+// write sends a frame, opening the stream if needed. If the current stream
+// was closed by the collector the frame is retried once on a new stream.
+func (s *stream) write(ctx context.Context, contentType string, body []byte) error {
+	frame := make([]byte, 4+len(body))
+	binary.BigEndian.PutUint32(frame, uint32(len(body)))
+	copy(frame[4:], body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrShutdown
+	}
+	for attempt := 0; ; attempt++ {
+		if s.conn == nil {
+			conn, err := s.open(contentType)
+			if err != nil {
+				return err
+			}
+			s.conn = conn
+		}
+		err := s.conn.writeFrame(ctx, frame)
+		if err == nil {
+			return nil
+		}
+		s.conn = nil
+		if attempt > 0 || ctx.Err() != nil {
+			return err
+		}
+		s.exporter.warnf("stream to %s closed, reopening: %v", s.endpoint.url, err)
+	}
+}
+
+// writeFrame writes a frame to the stream, honoring ctx.
+func (c *streamConn) writeFrame(ctx context.Context, frame []byte) error {
+	written := make(chan error, 1)
+	pw := c.pw
+	go func() {
+		_, err := pw.Write(frame)
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		return err
+	case <-c.done:
+		// The request ended, unblock the pending write.
+		pw.CloseWithError(errors.New("stream closed"))
+		<-written
+		if c.err != nil {
+			return c.err
+		}
+		return errors.New("stream closed by collector")
+	case <-ctx.Done():
+		// A partially written frame corrupts the stream, so abandon it.
+		pw.CloseWithError(ctx.Err())
+		<-written
+		return ctx.Err()
+	}
+}
+
+// open starts a new streaming request.
+func (s *stream) open(contentType string) (*streamConn, error) {
+	pr, pw := io.Pipe()
+	req, err := http.NewRequest(http.MethodPost, s.endpoint.url, pr)
+	if err != nil {
+		return nil, s.exporter.errf("failed to create request to %s: %w", s.endpoint.url, err)
+	}
+	req.Header.Set("User-Agent", s.exporter.userAgent)
+	for k, v := range s.exporter.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(streamFramingHeader, "length-prefixed")
+
+	c := &streamConn{pw: pw, done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		resp, err := s.endpoint.client.Do(req)
+		if err != nil {
+			c.err = fmt.Errorf("stream to %s failed: %v", s.endpoint.url, err)
+			pr.CloseWithError(c.err)
+			return
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			c.err = fmt.Errorf("stream to %s ended with status %d", s.endpoint.url, resp.StatusCode)
+		}
+		pr.CloseWithError(errors.New("stream closed by collector"))
+	}()
+	return c, nil
+}
+
+// close ends the current stream and waits for the collector's response.
+// Later writes fail with ErrShutdown rather than open a new stream.
+func (s *stream) close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	c := s.conn
+	if c == nil {
+		return nil
+	}
+	s.conn = nil
+	_ = c.pw.Close()
+	select {
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
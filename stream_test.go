@@ -0,0 +1,167 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// streamCollector is a collector decoding the frames of streamed requests
+// as they arrive.
+type streamCollector struct {
+	*httptest.Server
+	frames  chan []span
+	streams int32 // Number of streaming requests received
+}
+
+// newStreamCollector starts a stream collector. It drops the connection of
+// each stream after maxFrames frames, or reads it to the end if maxFrames is
+// 0.
+func newStreamCollector(t *testing.T, maxFrames int) *streamCollector {
+	t.Helper()
+	c := &streamCollector{frames: make(chan []span, 100)}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&c.streams, 1)
+		if got := r.Header.Get("X-Stream-Framing"); got != "length-prefixed" {
+			t.Errorf("X-Stream-Framing = %q, want length-prefixed", got)
+		}
+		var size [4]byte
+		for n := 0; maxFrames == 0 || n < maxFrames; n++ {
+			if _, err := io.ReadFull(r.Body, size[:]); err != nil {
+				break
+			}
+			frame := make([]byte, binary.BigEndian.Uint32(size[:]))
+			if _, err := io.ReadFull(r.Body, frame); err != nil {
+				t.Errorf("truncated frame: %v", err)
+				break
+			}
+			var batch []span
+			if err := json.Unmarshal(frame, &batch); err != nil {
+				t.Errorf("invalid frame %s: %v", frame, err)
+			}
+			c.frames <- batch
+		}
+		if maxFrames > 0 {
+			// End the stream as a restarted collector would, without
+			// draining the request body.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Hijack: %v", err)
+				return
+			}
+			conn.Close()
+		}
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// next returns the next frame received, failing the test if none is
+// received within waitTimeout.
+func (c *streamCollector) next(t *testing.T) []string {
+	t.Helper()
+	select {
+	case batch := <-c.frames:
+		return spanNames(batch)
+	case <-time.After(waitTimeout):
+		t.Fatal("timed out waiting for a frame")
+		return nil
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	c := newStreamCollector(t, 0)
+	e := newExporter(t, c.URL, httpExporter.WithStreaming())
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+		// Each batch is flushed to the collector as it is exported.
+		if got := c.next(t); !equal(got, name) {
+			t.Errorf("frame holds %v, want [%s]", got, name)
+		}
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := atomic.LoadInt32(&c.streams); got != 1 {
+		t.Errorf("collector received %d requests, want a single stream", got)
+	}
+	if err := e.ExportSpans(ctx, newSpans("late")); err != httpExporter.ErrShutdown {
+		t.Errorf("ExportSpans after Shutdown = %v, want ErrShutdown", err)
+	}
+}
+
+func TestStreamingReopens(t *testing.T) {
+	// The collector ends every stream after a frame.
+	c := newStreamCollector(t, 1)
+	e := newExporter(t, c.URL, httpExporter.WithStreaming())
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("first")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.next(t); !equal(got, "first") {
+		t.Fatalf("frame holds %v, want [first]", got)
+	}
+	// A frame written before the exporter sees the end of the stream is
+	// lost with it, so export until a frame reaches the collector again.
+	deadline := time.Now().Add(waitTimeout)
+	for received := false; !received; {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a frame on a reopened stream")
+		}
+		if err := e.ExportSpans(ctx, newSpans("second")); err != nil {
+			t.Fatalf("ExportSpans after the collector ended the stream: %v", err)
+		}
+		select {
+		case batch := <-c.frames:
+			if got := spanNames(batch); !equal(got, "second") {
+				t.Errorf("frame holds %v, want [second]", got)
+			}
+			received = true
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	if got := atomic.LoadInt32(&c.streams); got < 2 {
+		t.Errorf("collector received %d streams, want the stream reopened", got)
+	}
+}
+
+func TestStreamingCompressionFromEnvironment(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_COMPRESSION", "gzip")
+	c := newStreamCollector(t, 0)
+	e := newExporter(t, c.URL, httpExporter.WithStreaming())
+	if got := e.EffectiveConfig().Compression; got != "none" {
+		t.Errorf("effective compression %q, want none", got)
+	}
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.next(t); !equal(got, "a") {
+		t.Errorf("frame holds %v, want [a]", got)
+	}
+}
+
+func TestStreamingConflicts(t *testing.T) {
+	for name, opts := range map[string][]httpExporter.Option{
+		"gzip":       {httpExporter.WithCompression(httpExporter.GzipCompression)},
+		"templated":  {httpExporter.WithURLPath("/v1/{service.name}")},
+		"encryption": {httpExporter.WithPayloadEncryption([]byte("0123456789abcdef"))},
+	} {
+		opts := append(opts, httpExporter.WithStreaming())
+		if _, err := httpExporter.New("http://localhost:4318", opts...); err == nil {
+			t.Errorf("New succeeded with streaming and %s", name)
+		}
+	}
+}
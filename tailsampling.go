@@ -0,0 +1,263 @@
+package httpExporter
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TailSamplingPolicy decides from the spans of a trace whether it is
+// exported.
+type TailSamplingPolicy func(spans []sdktrace.ReadOnlySpan) bool
+
+// ErrorPolicy keeps traces with a span whose status is an error.
+func ErrorPolicy() TailSamplingPolicy {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		for _, span := range spans {
+			if span.Status().Code == codes.Error {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LatencyPolicy keeps traces lasting at least threshold, from the start of
+// their earliest span to the end of their latest one.
+func LatencyPolicy(threshold time.Duration) TailSamplingPolicy {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		var start, end time.Time
+		for _, span := range spans {
+			if start.IsZero() || span.StartTime().Before(start) {
+				start = span.StartTime()
+			}
+			if span.EndTime().After(end) {
+				end = span.EndTime()
+			}
+		}
+		return end.Sub(start) >= threshold
+	}
+}
+
+// AttributePolicy keeps traces with a span holding the attribute key with the
+// given value.
+func AttributePolicy(key attribute.Key, value attribute.Value) TailSamplingPolicy {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		for _, span := range spans {
+			for _, kv := range span.Attributes() {
+				if kv.Key == key && kv.Value == value {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// TailSamplingConfig defines how traces are buffered and which are exported.
+type TailSamplingConfig struct {
+	// Window is how long the spans of a trace are buffered after its first
+	// span is exported, waiting for the rest of the trace, before deciding
+	// whether it is exported. Defaults to 10 seconds.
+	Window time.Duration
+	// MaxTraces is the largest number of traces buffered. When it is
+	// reached the oldest trace is decided early. Defaults to 10000.
+	MaxTraces int
+	// MaxSpans is the largest number of spans buffered across traces, so a
+	// few huge traces cannot exhaust memory. When it is exceeded the oldest
+	// traces are decided early. Defaults to 100000.
+	MaxSpans int
+	// Policies keep the traces matching any of them. Every trace is kept if
+	// there are none.
+	Policies []TailSamplingPolicy
+}
+
+// WithTailSampling configures the exporter to buffer spans by trace and only
+// export the traces matching the sampling policies, once the window of each
+// trace elapsed. Spans of a trace arriving after its decision follow it for
+// another window. ForceFlush and Shutdown decide the traces buffered so far.
+func WithTailSampling(tc TailSamplingConfig) Option {
+	return optionFunc(func(cfg config) config {
+		if tc.Window <= 0 {
+			tc.Window = 10 * time.Second
+		}
+		if tc.MaxTraces <= 0 {
+			tc.MaxTraces = 10000
+		}
+		if tc.MaxSpans <= 0 {
+			tc.MaxSpans = 100000
+		}
+		tc.Policies = append([]TailSamplingPolicy(nil), tc.Policies...)
+		cfg.tailSampling = &tc
+		return cfg
+	})
+}
+
+// tailSampler buffers spans by trace until their trace is decided.
+type tailSampler struct {
+	TailSamplingConfig
+
+	mu      sync.Mutex
+	traces  map[trace.TraceID]*list.Element // Buffered traces
+	order   *list.List                      // Buffered traces, oldest first
+	decided map[trace.TraceID]tailDecision  // Recently decided traces
+	spans   int                             // Buffered spans
+
+	stop chan struct{}
+	done chan struct{} // Closed once the background goroutine returned
+}
+
+// tailTrace is a buffered trace.
+type tailTrace struct {
+	id      trace.TraceID
+	spans   []sdktrace.ReadOnlySpan
+	expires time.Time
+}
+
+// tailDecision is the decision of a trace, remembered for late spans.
+type tailDecision struct {
+	keep    bool
+	expires time.Time
+}
+
+func newTailSampler(tc TailSamplingConfig) *tailSampler {
+	return &tailSampler{
+		TailSamplingConfig: tc,
+		traces:             make(map[trace.TraceID]*list.Element),
+		order:              list.New(),
+		decided:            make(map[trace.TraceID]tailDecision),
+		stop:               make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+}
+
+// start runs the background goroutine deciding the traces whose window
+// elapsed.
+func (s *tailSampler) start(e *Exporter) {
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.Window / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.release(context.Background(), e, s.expired(time.Now())); err != nil {
+					e.warnf("failed to export sampled traces: %v", err)
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// add buffers spans and releases those of already decided traces, as well
+// as the traces decided early to make room.
+func (s *tailSampler) add(ctx context.Context, e *Exporter, spans []sdktrace.ReadOnlySpan) error {
+	now := time.Now()
+	var kept []sdktrace.ReadOnlySpan
+	s.mu.Lock()
+	for _, span := range spans {
+		id := span.SpanContext().TraceID()
+		if d, ok := s.decided[id]; ok && now.Before(d.expires) {
+			if d.keep {
+				kept = append(kept, span)
+			}
+			continue
+		}
+		if el, ok := s.traces[id]; ok {
+			t := el.Value.(*tailTrace)
+			t.spans = append(t.spans, span)
+		} else {
+			if s.order.Len() >= s.MaxTraces {
+				kept = append(kept, s.decide(s.order.Front(), now)...)
+			}
+			t := &tailTrace{id: id, spans: []sdktrace.ReadOnlySpan{span}, expires: now.Add(s.Window)}
+			s.traces[id] = s.order.PushBack(t)
+		}
+		s.spans++
+		for s.spans > s.MaxSpans {
+			kept = append(kept, s.decide(s.order.Front(), now)...)
+		}
+	}
+	s.mu.Unlock()
+	return s.release(ctx, e, kept)
+}
+
+// expired decides the traces whose window elapsed at now and returns the
+// spans of those kept.
+func (s *tailSampler) expired(now time.Time) []sdktrace.ReadOnlySpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var kept []sdktrace.ReadOnlySpan
+	for el := s.order.Front(); el != nil && !now.Before(el.Value.(*tailTrace).expires); el = s.order.Front() {
+		kept = append(kept, s.decide(el, now)...)
+	}
+	for id, d := range s.decided {
+		if !now.Before(d.expires) {
+			delete(s.decided, id)
+		}
+	}
+	return kept
+}
+
+// all decides every buffered trace and returns the spans of those kept.
+func (s *tailSampler) all() []sdktrace.ReadOnlySpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var kept []sdktrace.ReadOnlySpan
+	for el := s.order.Front(); el != nil; el = s.order.Front() {
+		kept = append(kept, s.decide(el, now)...)
+	}
+	return kept
+}
+
+// decide removes a buffered trace and returns its spans if a policy keeps
+// it. s.mu must be held.
+func (s *tailSampler) decide(el *list.Element, now time.Time) []sdktrace.ReadOnlySpan {
+	t := s.order.Remove(el).(*tailTrace)
+	delete(s.traces, t.id)
+	s.spans -= len(t.spans)
+	keep := len(s.Policies) == 0
+	for _, policy := range s.Policies {
+		if keep = policy(t.spans); keep {
+			break
+		}
+	}
+	s.decided[t.id] = tailDecision{keep: keep, expires: now.Add(s.Window)}
+	if !keep {
+		return nil
+	}
+	return t.spans
+}
+
+// release exports the spans of kept traces.
+func (s *tailSampler) release(ctx context.Context, e *Exporter, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	if e.queue != nil {
+		return e.queue.enqueue(ctx, e, spans)
+	}
+	return e.exportSpans(ctx, spans)
+}
+
+// flush exports the buffered traces kept by the policies.
+func (s *tailSampler) flush(ctx context.Context, e *Exporter) error {
+	return s.release(ctx, e, s.all())
+}
+
+// close stops the background goroutine and exports the buffered traces kept
+// by the policies.
+func (s *tailSampler) close(ctx context.Context, e *Exporter) error {
+	close(s.stop)
+	<-s.done
+	return s.flush(ctx, e)
+}
@@ -0,0 +1,190 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTrace returns the ended spans of a single trace, with the given names.
+// The last span has an error status if failed is set.
+func newTrace(failed bool, names ...string) []sdktrace.ReadOnlySpan {
+	traceID := newTraceID()
+	stubs := make(tracetest.SpanStubs, 0, len(names))
+	for _, name := range names {
+		stubs = append(stubs, tracetest.SpanStub{
+			Name:        name,
+			SpanContext: newSpanContext(traceID),
+			StartTime:   time.Now(),
+			EndTime:     time.Now(),
+		})
+	}
+	if failed {
+		stubs[len(stubs)-1].Status = sdktrace.Status{Code: codes.Error}
+	}
+	return stubs.Snapshots()
+}
+
+func TestTailSamplingErrorPolicy(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTailSampling(httpExporter.TailSamplingConfig{
+		Window:   time.Hour,
+		Policies: []httpExporter.TailSamplingPolicy{httpExporter.ErrorPolicy()},
+	}))
+
+	ctx := context.Background()
+	ok, failed := newTrace(false, "ok root", "ok child"), newTrace(true, "failed root", "failed child")
+	// The spans of a trace may be exported in separate batches.
+	for _, spans := range [][]sdktrace.ReadOnlySpan{ok[:1], failed[:1], ok[1:], failed[1:]} {
+		if err := e.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if got := c.count(); got != 0 {
+		t.Fatalf("collector received %d requests before the window elapsed", got)
+	}
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := c.names(t); !equal(got, "failed root", "failed child") {
+		t.Errorf("collector received %v, want the spans of the failed trace", got)
+	}
+}
+
+func TestTailSamplingWindow(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTailSampling(httpExporter.TailSamplingConfig{
+		Window: 40 * time.Millisecond,
+		Policies: []httpExporter.TailSamplingPolicy{
+			httpExporter.AttributePolicy("keep", attribute.BoolValue(true)),
+		},
+	}))
+
+	ctx := context.Background()
+	stubs := tracetest.SpanStubsFromReadOnlySpans(newTrace(false, "root", "child"))
+	stubs[1].Attributes = []attribute.KeyValue{attribute.Bool("keep", true)}
+	spans := stubs.Snapshots()
+	if err := e.ExportSpans(ctx, spans[:1]); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.ExportSpans(ctx, spans[1:]); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	waitFor(t, "the kept trace", func() bool { return len(c.received()) > 0 })
+	if got := c.names(t); !equal(got, "root", "child") {
+		t.Fatalf("collector received %v, want the trace once its window elapsed", got)
+	}
+
+	// Late spans follow the decision of their trace.
+	late := tracetest.SpanStubsFromReadOnlySpans(spans[:1])
+	late[0].Name = "late"
+	late[0].SpanContext = newSpanContext(spans[0].SpanContext().TraceID())
+	if err := e.ExportSpans(ctx, late.Snapshots()); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "root", "child", "late") {
+		t.Errorf("collector received %v, want the late span sent right away", got)
+	}
+}
+
+func TestTailSamplingMaxTraces(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTailSampling(httpExporter.TailSamplingConfig{
+		Window:    time.Hour,
+		MaxTraces: 2,
+	}))
+
+	ctx := context.Background()
+	for _, name := range []string{"first", "second", "third"} {
+		if err := e.ExportSpans(ctx, newTrace(false, name)); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	// The oldest trace is decided early to make room for the third one.
+	if got := c.names(t); !equal(got, "first") {
+		t.Errorf("collector received %v, want the oldest trace", got)
+	}
+}
+
+func TestTailSamplingMaxSpans(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTailSampling(httpExporter.TailSamplingConfig{
+		Window:   time.Hour,
+		MaxSpans: 3,
+	}))
+
+	ctx := context.Background()
+	first, second := newTrace(false, "first root", "first child"), newTrace(false, "second root", "second child")
+	for _, spans := range [][]sdktrace.ReadOnlySpan{first, second[:1]} {
+		if err := e.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if got := c.count(); got != 0 {
+		t.Fatalf("collector received %d requests within the span budget", got)
+	}
+	// The fourth span exceeds the budget: the oldest trace is decided early.
+	if err := e.ExportSpans(ctx, second[1:]); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "first root", "first child") {
+		t.Errorf("collector received %v, want the oldest trace", got)
+	}
+
+	// A trace larger than the budget is decided as soon as it exceeds it,
+	// and its later spans follow the decision.
+	huge := newTrace(false, "a", "b", "c", "d", "e")
+	if err := e.ExportSpans(ctx, huge); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.names(t); !equal(got, "first root", "first child", "second root", "second child", "a", "b", "c", "d", "e") {
+		t.Errorf("collector received %v, want the huge trace decided early", got)
+	}
+}
+
+func TestTailSamplingLatencyPolicy(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTailSampling(httpExporter.TailSamplingConfig{
+		Window:   time.Hour,
+		Policies: []httpExporter.TailSamplingPolicy{httpExporter.LatencyPolicy(time.Second)},
+	}))
+
+	slow := tracetest.SpanStubsFromReadOnlySpans(newTrace(false, "slow root", "slow child"))
+	slow[1].EndTime = slow[0].StartTime.Add(2 * time.Second)
+	ctx := context.Background()
+	for _, spans := range [][]sdktrace.ReadOnlySpan{newTrace(false, "fast"), slow.Snapshots()} {
+		if err := e.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+	}
+	if err := e.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := c.names(t); !equal(got, "slow root", "slow child") {
+		t.Errorf("collector received %v, want the slow trace", got)
+	}
+}
+
+func TestTailSamplingShutdown(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTailSampling(httpExporter.TailSamplingConfig{
+		Window: time.Hour,
+	}))
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newTrace(false, "root", "child")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := c.names(t); !equal(got, "root", "child") {
+		t.Errorf("collector received %v after Shutdown, want the buffered trace", got)
+	}
+}
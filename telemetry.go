@@ -0,0 +1,108 @@
+package httpExporter
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// instrumentationName is the name of the meter recording the exporter's own
+// metrics.
+const instrumentationName = "github.com/Syn3rman/httpExporter"
+
+// WithMeterProvider configures the exporter to record metrics about itself
+// with a meter from mp:
+//
+//	httpexporter.spans.exported   spans accepted by the primary collector
+//	httpexporter.spans.rejected   spans rejected by partial success responses
+//	httpexporter.spans.dropped    spans dropped by the queue, rate limit or circuit breaker
+//	httpexporter.batches.failed   batches the primary collector did not accept
+//	httpexporter.request.duration duration of requests to collectors, in seconds
+//	httpexporter.request.size     size of request bodies, in bytes
+//
+// Request metrics carry the collector URL as the endpoint attribute.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.meterProvider = mp
+		return cfg
+	})
+}
+
+// telemetry holds the instruments of the exporter's own metrics. A nil
+// telemetry records nothing.
+type telemetry struct {
+	spansExported   metric.Int64Counter
+	spansRejected   metric.Int64Counter
+	spansDropped    metric.Int64Counter
+	batchesFailed   metric.Int64Counter
+	requestDuration metric.Float64Histogram
+	requestSize     metric.Int64Histogram
+}
+
+func newTelemetry(mp metric.MeterProvider) (*telemetry, error) {
+	m := mp.Meter(instrumentationName)
+	var t telemetry
+	var err error
+	if t.spansExported, err = m.Int64Counter("httpexporter.spans.exported",
+		metric.WithDescription("Spans accepted by the primary collector"),
+		metric.WithUnit("{span}")); err != nil {
+		return nil, err
+	}
+	if t.spansRejected, err = m.Int64Counter("httpexporter.spans.rejected",
+		metric.WithDescription("Spans rejected by partial success responses"),
+		metric.WithUnit("{span}")); err != nil {
+		return nil, err
+	}
+	if t.spansDropped, err = m.Int64Counter("httpexporter.spans.dropped",
+		metric.WithDescription("Spans dropped by the queue, rate limit or circuit breaker"),
+		metric.WithUnit("{span}")); err != nil {
+		return nil, err
+	}
+	if t.batchesFailed, err = m.Int64Counter("httpexporter.batches.failed",
+		metric.WithDescription("Batches the primary collector did not accept"),
+		metric.WithUnit("{batch}")); err != nil {
+		return nil, err
+	}
+	if t.requestDuration, err = m.Float64Histogram("httpexporter.request.duration",
+		metric.WithDescription("Duration of requests to collectors"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if t.requestSize, err = m.Int64Histogram("httpexporter.request.size",
+		metric.WithDescription("Size of request bodies sent to collectors"),
+		metric.WithUnit("By")); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (t *telemetry) exported(spans, rejected int) {
+	if t != nil {
+		t.spansExported.Add(context.Background(), int64(spans))
+		if rejected > 0 {
+			t.spansRejected.Add(context.Background(), int64(rejected))
+		}
+	}
+}
+
+func (t *telemetry) dropped(spans int) {
+	if t != nil {
+		t.spansDropped.Add(context.Background(), int64(spans))
+	}
+}
+
+func (t *telemetry) failed() {
+	if t != nil {
+		t.batchesFailed.Add(context.Background(), 1)
+	}
+}
+
+func (t *telemetry) request(url string, size int, d time.Duration) {
+	if t != nil {
+		attrs := metric.WithAttributes(attribute.String("endpoint", url))
+		t.requestDuration.Record(context.Background(), d.Seconds(), attrs)
+		t.requestSize.Record(context.Background(), int64(size), attrs)
+	}
+}
@@ -0,0 +1,93 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectMetrics returns the metrics recorded by the exporter with the meter
+// provider of reader, by name.
+func collectMetrics(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != "github.com/Syn3rman/httpExporter" {
+			t.Errorf("metrics recorded by meter %q", sm.Scope.Name)
+		}
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// counterValue returns the value of a counter without attributes.
+func counterValue(t *testing.T, metrics map[string]metricdata.Aggregation, name string) int64 {
+	t.Helper()
+	sum, ok := metrics[name].(metricdata.Sum[int64])
+	if !ok {
+		t.Errorf("no %s counter in %v", name, metrics)
+		return 0
+	}
+	var v int64
+	for _, dp := range sum.DataPoints {
+		v += dp.Value
+	}
+	return v
+}
+
+func TestMeterProvider(t *testing.T) {
+	c := newCollector(t)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	e := newExporter(t, c.URL(),
+		httpExporter.WithMeterProvider(mp),
+		httpExporter.WithRateLimit(1, 3),
+		httpExporter.WithRateLimitPolicy(httpExporter.LimitDrop),
+	)
+
+	ctx := context.Background()
+	if err := e.ExportSpans(ctx, newSpans("a", "b")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	c.setStatus(http.StatusBadRequest)
+	// One span is rejected with its batch and one dropped by the rate limit.
+	if err := e.ExportSpans(ctx, newSpans("c", "d")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 400 response")
+	}
+
+	metrics := collectMetrics(t, reader)
+	if got := counterValue(t, metrics, "httpexporter.spans.exported"); got != 2 {
+		t.Errorf("spans.exported = %d, want 2", got)
+	}
+	if got := counterValue(t, metrics, "httpexporter.spans.dropped"); got != 1 {
+		t.Errorf("spans.dropped = %d, want 1", got)
+	}
+	if got := counterValue(t, metrics, "httpexporter.batches.failed"); got != 1 {
+		t.Errorf("batches.failed = %d, want 1", got)
+	}
+
+	size, ok := metrics["httpexporter.request.size"].(metricdata.Histogram[int64])
+	if !ok || len(size.DataPoints) != 1 {
+		t.Fatalf("request.size = %v, want a histogram of the collector endpoint", metrics["httpexporter.request.size"])
+	}
+	dp := size.DataPoints[0]
+	if dp.Count != 2 {
+		t.Errorf("request.size recorded %d requests, want 2", dp.Count)
+	}
+	if endpoint, _ := dp.Attributes.Value("endpoint"); endpoint.AsString() != c.URL() {
+		t.Errorf("request.size endpoint = %q, want %q", endpoint.AsString(), c.URL())
+	}
+	if duration, ok := metrics["httpexporter.request.duration"].(metricdata.Histogram[float64]); !ok || duration.DataPoints[0].Count != 2 {
+		t.Errorf("request.duration = %v, want 2 requests", metrics["httpexporter.request.duration"])
+	}
+}
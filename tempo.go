@@ -0,0 +1,48 @@
+package httpExporter
+
+const (
+	// defaultTempoURL is the OTLP/HTTP receiver of a local Tempo.
+	defaultTempoURL = "http://localhost:4318"
+	// tempoTenantHeader is the header carrying the tenant of a multi-tenant
+	// Tempo.
+	tempoTenantHeader = "X-Scope-OrgID"
+)
+
+// WithTempo configures the exporter to push spans directly to the OTLP/HTTP
+// receiver of Grafana Tempo: spans are sent in the OTLPProtobuf format to
+// /v1/traces, of http://localhost:4318 when no collector URL is set. The
+// tenant of a multi-tenant Tempo is sent in the X-Scope-OrgID header; it is
+// read from the OTEL_EXPORTER_HTTP_TEMPO_TENANT environment variable unless
+// WithTempoTenant is used.
+func WithTempo() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.tempo = true
+		cfg.format = OTLPProtobuf
+		return cfg
+	})
+}
+
+// WithTempoTenant configures the tenant spans are pushed to with WithTempo.
+func WithTempoTenant(tenant string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.tempoTenant = tenant
+		return cfg
+	})
+}
+
+// tempoHeaders returns the configured headers with the X-Scope-OrgID header
+// of the Tempo tenant, unless it is set with WithHeaders.
+func tempoHeaders(cfg config) map[string]string {
+	if cfg.tempoTenant == "" {
+		return cfg.headers
+	}
+	if _, ok := cfg.headers[tempoTenantHeader]; ok {
+		return cfg.headers
+	}
+	headers := make(map[string]string, len(cfg.headers)+1)
+	for k, v := range cfg.headers {
+		headers[k] = v
+	}
+	headers[tempoTenantHeader] = cfg.tempoTenant
+	return headers
+}
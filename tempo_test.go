@@ -0,0 +1,55 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestTempo(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_TEMPO_TENANT", "shop")
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTempo())
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	r := c.received()[0]
+	if r.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+		t.Errorf("request to %s of %s, want OTLP protobuf to /v1/traces", r.Path, r.Header.Get("Content-Type"))
+	}
+	if got := r.Header.Get("X-Scope-OrgID"); got != "shop" {
+		t.Errorf("X-Scope-OrgID = %q, want the tenant of the environment", got)
+	}
+}
+
+func TestTempoTenant(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_TEMPO_TENANT", "shop")
+	for _, tt := range []struct {
+		opts []httpExporter.Option
+		want string
+	}{
+		{[]httpExporter.Option{httpExporter.WithTempoTenant("billing")}, "billing"},
+		{[]httpExporter.Option{httpExporter.WithTempoTenant("billing"), httpExporter.WithHeaders(map[string]string{"X-Scope-OrgID": "explicit"})}, "explicit"},
+	} {
+		c := newCollector(t)
+		e := newExporter(t, c.URL(), append(tt.opts, httpExporter.WithTempo())...)
+		if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+			t.Fatalf("ExportSpans: %v", err)
+		}
+		if got := c.received()[0].Header.Get("X-Scope-OrgID"); got != tt.want {
+			t.Errorf("X-Scope-OrgID = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestTempoWithoutTenant(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithTempo())
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if _, ok := c.received()[0].Header["X-Scope-Orgid"]; ok {
+		t.Error("X-Scope-OrgID sent without a tenant")
+	}
+}
@@ -0,0 +1,49 @@
+package httpExporter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestTimeout(t *testing.T) {
+	c := newCollector(t)
+	c.setLatency(time.Second)
+	e := newExporter(t, c.URL(), httpExporter.WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded past the timeout")
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("ExportSpans returned after %v, want the 50ms timeout", d)
+	}
+}
+
+func TestTimeoutFromEnvironment(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_TIMEOUT", "50")
+	c := newCollector(t)
+	c.setLatency(time.Second)
+	e := newExporter(t, c.URL())
+
+	start := time.Now()
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err == nil {
+		t.Fatal("ExportSpans succeeded past the timeout")
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("ExportSpans returned after %v, want the 50ms timeout", d)
+	}
+}
+
+func TestTimeoutDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_HTTP_TIMEOUT", "50")
+	c := newCollector(t)
+	c.setLatency(150 * time.Millisecond)
+	// The option takes precedence over the environment.
+	e := newExporter(t, c.URL(), httpExporter.WithTimeout(0))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans without a timeout: %v", err)
+	}
+}
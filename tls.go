@@ -0,0 +1,58 @@
+package httpExporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// WithTLSClientConfig configures the TLS settings of the connections to the
+// collectors, such as trusted certificate authorities or client
+// certificates. It takes precedence over the OTEL_EXPORTER_HTTP_CERTIFICATE
+// environment variable. A client configured with WithClient must use an
+// *http.Transport, or a nil Transport, for it to apply.
+func WithTLSClientConfig(tlsConfig *tls.Config) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.tlsConfig = tlsConfig.Clone()
+		return cfg
+	})
+}
+
+// WithInsecure configures the exporter to contact collectors whose URL has
+// no scheme, such as collector:4318, over plain http rather than https. The
+// scheme of other collector URLs is never changed.
+func WithInsecure() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.insecure = true
+		cfg.envInsecure = false
+		return cfg
+	})
+}
+
+// resolveTLSConfig returns the TLS settings of the exporter: the ones set by
+// WithTLSClientConfig, or ones trusting the certificate authorities of the
+// certificate file set by the environment, or nil.
+func resolveTLSConfig(cfg config) (*tls.Config, error) {
+	if cfg.tlsConfig != nil || cfg.certificateFile == "" {
+		return cfg.tlsConfig, nil
+	}
+	pool, err := loadCertPool(cfg.certificateFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// loadCertPool returns a pool of the certificates of a PEM file.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate file: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,137 @@
+package httpExporter
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// attributeTransform rewrites an attribute before it is exported, or reports
+// false to discard it.
+type attributeTransform func(attribute.KeyValue) (attribute.KeyValue, bool)
+
+// transformedSpan is a span whose span, event, link and resource attributes
+// were rewritten by the attribute transforms of the exporter, truncated to
+// its limits and sorted for deterministic encoding.
+type transformedSpan struct {
+	sdktrace.ReadOnlySpan
+	attributes []attribute.KeyValue
+	events     []sdktrace.Event
+	links      []sdktrace.Link
+	resource   *resource.Resource
+
+	droppedAttributes int
+	droppedEvents     int
+	droppedLinks      int
+}
+
+func (s *transformedSpan) Attributes() []attribute.KeyValue { return s.attributes }
+func (s *transformedSpan) Events() []sdktrace.Event         { return s.events }
+func (s *transformedSpan) Links() []sdktrace.Link           { return s.links }
+func (s *transformedSpan) Resource() *resource.Resource     { return s.resource }
+func (s *transformedSpan) DroppedAttributes() int           { return s.droppedAttributes }
+func (s *transformedSpan) DroppedEvents() int               { return s.droppedEvents }
+func (s *transformedSpan) DroppedLinks() int                { return s.droppedLinks }
+
+// transformSpans applies the attribute transforms, limits, service name and
+// deterministic attribute order to a batch of spans. The spans slice is not
+// modified, as it belongs to the caller.
+func (e *Exporter) transformSpans(spans []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	if len(e.attributeTransforms) == 0 && e.limits == (Limits{}) && e.serviceName == "" && !e.deterministic {
+		return spans
+	}
+	// Spans of a batch usually share a handful of resources.
+	resources := make(map[*resource.Resource]*resource.Resource)
+	transformed := make([]sdktrace.ReadOnlySpan, len(spans))
+	for i, span := range spans {
+		transformed[i] = e.transformSpan(span, resources)
+	}
+	return transformed
+}
+
+func (e *Exporter) transformSpan(span sdktrace.ReadOnlySpan, resources map[*resource.Resource]*resource.Resource) sdktrace.ReadOnlySpan {
+	ts := &transformedSpan{
+		ReadOnlySpan:  span,
+		droppedEvents: span.DroppedEvents(),
+		droppedLinks:  span.DroppedLinks(),
+	}
+	var dropped int
+	ts.attributes, dropped = e.limitAttributes(e.transformAttributes(span.Attributes()))
+	ts.attributes = e.sortAttributes(ts.attributes)
+	ts.droppedAttributes = span.DroppedAttributes() + dropped
+
+	events := span.Events()
+	if n := e.limits.EventCount; n > 0 && len(events) > n {
+		// Like the SDK, keep the most recent events.
+		ts.droppedEvents += len(events) - n
+		events = events[len(events)-n:]
+	}
+	if len(events) > 0 {
+		ts.events = make([]sdktrace.Event, len(events))
+		for i, ev := range events {
+			ev.Attributes, dropped = e.limitAttributes(e.transformAttributes(ev.Attributes))
+			ev.Attributes = e.sortAttributes(ev.Attributes)
+			ev.DroppedAttributeCount += dropped
+			ts.events[i] = ev
+		}
+	}
+	links := span.Links()
+	if n := e.limits.LinkCount; n > 0 && len(links) > n {
+		// Like the SDK, keep the first links.
+		ts.droppedLinks += len(links) - n
+		links = links[:n]
+	}
+	if len(links) > 0 {
+		ts.links = make([]sdktrace.Link, len(links))
+		for i, l := range links {
+			l.Attributes, dropped = e.limitAttributes(e.transformAttributes(l.Attributes))
+			l.Attributes = e.sortAttributes(l.Attributes)
+			l.DroppedAttributeCount += dropped
+			ts.links[i] = l
+		}
+	}
+	res := span.Resource()
+	r, ok := resources[res]
+	if !ok {
+		r = e.transformResource(res)
+		resources[res] = r
+	}
+	ts.resource = r
+	return ts
+}
+
+// transformResource applies the attribute transforms, value length limit and
+// service name to a resource, which may be nil.
+func (e *Exporter) transformResource(res *resource.Resource) *resource.Resource {
+	if res == nil && e.serviceName == "" {
+		return nil
+	}
+	// Resources have no dropped attributes count, so only their values are
+	// truncated.
+	attrs := e.truncateValues(e.transformAttributes(res.Attributes()))
+	if e.serviceName != "" {
+		attrs = e.withServiceName(attrs)
+	}
+	return resource.NewWithAttributes(res.SchemaURL(), attrs...)
+}
+
+// transformAttributes applies the attribute transforms to attrs, in the
+// order they were configured.
+func (e *Exporter) transformAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if len(attrs) == 0 || len(e.attributeTransforms) == 0 {
+		return attrs
+	}
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		keep := true
+		for _, transform := range e.attributeTransforms {
+			if kv, keep = transform(kv); !keep {
+				break
+			}
+		}
+		if keep {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
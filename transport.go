@@ -0,0 +1,165 @@
+package httpExporter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportConfig tunes the transport of the default HTTP client. Zero
+// fields keep the defaults of http.DefaultTransport.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept per
+	// collector. The default of 2 causes connection churn when batches are
+	// sent concurrently.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long idle connections are kept.
+	IdleConnTimeout time.Duration
+	// ForceHTTP2 attempts HTTP/2 even with a customized TLS configuration
+	// or dialer.
+	ForceHTTP2 bool
+	// KeepAlive is the period of TCP keep-alive probes. Negative values
+	// disable them.
+	KeepAlive time.Duration
+}
+
+// WithTransportConfig tunes the transport of the default HTTP client, so
+// connections are reused at high batch rates. It cannot be combined with
+// WithClient.
+func WithTransportConfig(tc TransportConfig) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.transport = tc
+		return cfg
+	})
+}
+
+// WithProxy configures the exporter to send requests through the HTTP proxy
+// at proxyURL, such as http://proxy.example.com:3128. Without it, the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are honored
+// unless WithClient sets a client that does not. A client configured with
+// WithClient must use an *http.Transport, or a nil Transport, for it to
+// apply.
+func WithProxy(proxyURL string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.proxyURL = proxyURL
+		return cfg
+	})
+}
+
+// WithMiddleware configures a wrapper of the transport of the HTTP client,
+// to plug in request logging, tracing of the exporter or custom
+// authentication without replacing the client and losing the TLS, proxy and
+// transport settings of the exporter. It may be used multiple times; the
+// first middleware is the outermost one.
+func WithMiddleware(mw func(http.RoundTripper) http.RoundTripper) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.middlewares = append(append([]func(http.RoundTripper) http.RoundTripper{}, cfg.middlewares...), mw)
+		return cfg
+	})
+}
+
+// withMiddlewares returns a copy of client whose transport is wrapped by the
+// middlewares, or client itself if there are none.
+func withMiddlewares(client *http.Client, middlewares []func(http.RoundTripper) http.RoundTripper) *http.Client {
+	if len(middlewares) == 0 {
+		return client
+	}
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	wrapped := rt
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		wrapped = middlewares[i](wrapped)
+	}
+	c := *client
+	c.Transport = middlewareTransport{RoundTripper: wrapped, base: rt}
+	return &c
+}
+
+// middlewareTransport is a transport wrapped by middlewares, which still
+// closes the idle connections of the wrapped transport on Shutdown.
+type middlewareTransport struct {
+	http.RoundTripper
+	base http.RoundTripper
+}
+
+func (t middlewareTransport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// newClient returns the HTTP client of the exporter: the configured one, or
+// the default one, with the transport settings of cfg applied to a copy.
+func newClient(cfg config) (*http.Client, error) {
+	tlsConfig, err := resolveTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var proxy *url.URL
+	if cfg.proxyURL != "" {
+		if proxy, err = url.Parse(cfg.proxyURL); err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.proxyURL)
+		}
+	}
+	tune := cfg.client == nil && cfg.transport != (TransportConfig{})
+	if tlsConfig == nil && proxy == nil && !tune {
+		return cfg.client, nil
+	}
+	return customizeTransport(cfg.client, func(t *http.Transport) {
+		if tune {
+			cfg.transport.apply(t)
+		}
+		if tlsConfig != nil {
+			t.TLSClientConfig = tlsConfig
+		}
+		if proxy != nil {
+			t.Proxy = http.ProxyURL(proxy)
+		}
+	})
+}
+
+// customizeTransport returns a copy of client, or of the default client if
+// nil, whose transport is a copy modified by fn.
+func customizeTransport(client *http.Client, fn func(*http.Transport)) (*http.Client, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("cannot configure transport of type %T", t)
+	}
+	fn(transport)
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
+
+// apply tunes a transport.
+func (tc TransportConfig) apply(t *http.Transport) {
+	if tc.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = tc.MaxIdleConnsPerHost
+		if t.MaxIdleConns > 0 && t.MaxIdleConns < tc.MaxIdleConnsPerHost {
+			t.MaxIdleConns = tc.MaxIdleConnsPerHost
+		}
+	}
+	if tc.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = tc.IdleConnTimeout
+	}
+	if tc.ForceHTTP2 {
+		t.ForceAttemptHTTP2 = true
+	}
+	if tc.KeepAlive != 0 {
+		// The settings of http.DefaultTransport, with the keep-alive period.
+		d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: tc.KeepAlive}
+		t.DialContext = d.DialContext
+	}
+}
@@ -0,0 +1,137 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestProxy(t *testing.T) {
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	e := newExporter(t, "http://collector.invalid:4318/v1/traces", httpExporter.WithProxy(proxy.URL))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !equal(proxied, "http://collector.invalid:4318/v1/traces") {
+		t.Errorf("proxy received %v, want the request to the collector", proxied)
+	}
+}
+
+func TestProxyInvalid(t *testing.T) {
+	if _, err := httpExporter.New("http://localhost:4318", httpExporter.WithProxy("localhost")); err == nil {
+		t.Error("New succeeded with a proxy URL without a host")
+	}
+}
+
+func TestProxyCustomTransport(t *testing.T) {
+	client := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+	_, err := httpExporter.New("http://localhost:4318", httpExporter.WithClient(client), httpExporter.WithProxy("http://proxy:3128"))
+	if err == nil {
+		t.Error("New succeeded with a proxy and a client transport it cannot configure")
+	}
+}
+
+// exportConcurrently exports n batches concurrently.
+func exportConcurrently(t *testing.T, e *httpExporter.Exporter, n int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+				t.Errorf("ExportSpans: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTransportConfig(t *testing.T) {
+	c := &collector{status: http.StatusOK, latency: 50 * time.Millisecond}
+	var conns int32
+	// The connection hook is set before the collector serves connections.
+	c.server = httptest.NewUnstartedServer(http.HandlerFunc(c.handle))
+	c.server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	c.server.Start()
+	t.Cleanup(c.server.Close)
+	e := newExporter(t, c.URL(), httpExporter.WithTransportConfig(httpExporter.TransportConfig{
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     time.Minute,
+	}))
+
+	// Without tuning, only 2 of the connections of the first round would be
+	// kept for the second one.
+	exportConcurrently(t, e, 10)
+	exportConcurrently(t, e, 10)
+	if got := atomic.LoadInt32(&conns); got > 10 {
+		t.Errorf("collector accepted %d connections, want the idle ones reused", got)
+	}
+}
+
+// addHeader returns a middleware adding value to the X-Chain header of
+// requests.
+func addHeader(value string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r = r.Clone(r.Context())
+			r.Header.Add("X-Chain", value)
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithMiddleware(addHeader("outer")), httpExporter.WithMiddleware(addHeader("inner")))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.received()[0].Header.Values("X-Chain"); !equal(got, "outer", "inner") {
+		t.Errorf("X-Chain = %v, want the first middleware to be the outermost", got)
+	}
+}
+
+func TestMiddlewareKeepsProxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+	}))
+	defer proxy.Close()
+
+	e := newExporter(t, "http://collector.invalid:4318", httpExporter.WithProxy(proxy.URL), httpExporter.WithMiddleware(addHeader("a")))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if atomic.LoadInt32(&proxied) != 1 {
+		t.Error("request not sent through the proxy with a middleware")
+	}
+}
@@ -0,0 +1,48 @@
+package httpExporter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// unixSocketHost is the placeholder host of requests sent over a unix domain
+// socket.
+const unixSocketHost = "localhost"
+
+// unixSocketClient returns a copy of client whose transport dials the unix
+// domain socket at path instead of the request host. Custom RoundTrippers
+// cannot be made to dial it.
+func unixSocketClient(client *http.Client, path string) (*http.Client, error) {
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("unix domain socket %s cannot be dialed by a client transport of type %T", path, t)
+	}
+	var d net.Dialer
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return d.DialContext(ctx, "unix", path)
+	}
+	c := *client
+	c.Transport = transport
+	return &c, nil
+}
+
+// isUnixSocketURL returns whether rawURL is a unix domain socket collector
+// URL.
+func isUnixSocketURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme == "unix"
+}
+
+// unixSocketURL returns the HTTP URL requests are sent to for a
+// unix:///path/to/socket collector URL, and the socket path.
+func unixSocketURL(u *url.URL) (*url.URL, string) {
+	return &url.URL{Scheme: "http", Host: unixSocketHost, Path: "/"}, u.Path
+}
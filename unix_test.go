@@ -0,0 +1,83 @@
+package httpExporter_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// listenUnix listens on a unix domain socket closed at the end of the test,
+// and returns its unix:// URL.
+func listenUnix(t *testing.T) (net.Listener, string) {
+	t.Helper()
+	// Socket paths are limited to about 100 bytes, too few for t.TempDir.
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "collector.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix domain sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l, "unix://" + path
+}
+
+// newUnixCollector starts a collector listening on a unix domain socket and
+// returns its unix:// URL.
+func newUnixCollector(t *testing.T) (*collector, string) {
+	t.Helper()
+	l, u := listenUnix(t)
+	c := newCollector(t)
+	srv := &http.Server{Handler: http.HandlerFunc(c.handle)}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return c, u
+}
+
+func TestUnixSocket(t *testing.T) {
+	c, u := newUnixCollector(t)
+	e := newExporter(t, u)
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	reqs := c.received()
+	if len(reqs) != 1 || reqs[0].Path != "/" {
+		t.Fatalf("collector received %+v, want a request to /", reqs)
+	}
+	if got := c.names(t); !equal(got, "a") {
+		t.Errorf("collector received %v, want [a]", got)
+	}
+}
+
+func TestUnixSocketFormatPath(t *testing.T) {
+	c, u := newUnixCollector(t)
+	e := newExporter(t, u, httpExporter.WithFormat(httpExporter.Zipkin))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if reqs := c.received(); len(reqs) != 1 || reqs[0].Path != "/api/v2/spans" {
+		t.Errorf("collector received %+v, want a request to the Zipkin path", reqs)
+	}
+}
+
+func TestUnixSocketNoPath(t *testing.T) {
+	if _, err := httpExporter.New("unix://"); err == nil {
+		t.Error("New succeeded with a unix URL without a socket path")
+	}
+}
+
+func TestUnixSocketCustomTransport(t *testing.T) {
+	_, u := newUnixCollector(t)
+	client := &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}
+	if _, err := httpExporter.New(u, httpExporter.WithClient(client)); err == nil {
+		t.Error("New succeeded with a client transport that cannot dial the socket")
+	}
+}
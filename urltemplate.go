@@ -0,0 +1,67 @@
+package httpExporter
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// urlPlaceholder matches the placeholders of templated collector URLs.
+var urlPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// WithURLPath configures the path of the collector URLs, overriding their
+// own and the default path of the format.
+//
+// Collector URLs and paths may hold placeholders filled from the resource of
+// the spans sent, so multi-tenant collectors can route by URL: {service} is
+// replaced with the service.name resource attribute and {key} with the
+// resource attribute key, for instance
+// https://collector/{service}/{deployment.environment}/v1/traces. Batches
+// are split by resource, and placeholders without a matching attribute are
+// replaced with "unknown", as are those of batches replayed from
+// persistence. Templated URLs cannot be combined with WithStreaming.
+func WithURLPath(path string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.urlPath = path
+		return cfg
+	})
+}
+
+// resourceKey is the context key of the resource of the spans being sent.
+type resourceKey struct{}
+
+// withResource returns a context carrying the resource of a batch, to fill
+// the placeholders of templated collector URLs.
+func withResource(ctx context.Context, res *resource.Resource) context.Context {
+	return context.WithValue(ctx, resourceKey{}, res)
+}
+
+// templated reports whether a collector URL holds placeholders.
+func templated(rawURL string) bool {
+	return urlPlaceholder.MatchString(rawURL)
+}
+
+// requestURL returns the URL of a request to the endpoint, with the
+// placeholders of a templated URL filled from the resource of the batch.
+// Missing resource attributes are replaced with "unknown".
+func (ep *endpoint) requestURL(ctx context.Context) string {
+	if !ep.templated {
+		return ep.url
+	}
+	res, _ := ctx.Value(resourceKey{}).(*resource.Resource)
+	return urlPlaceholder.ReplaceAllStringFunc(ep.url, func(placeholder string) string {
+		key := attribute.Key(strings.Trim(placeholder, "{}"))
+		if key == "service" {
+			key = semconv.ServiceNameKey
+		}
+		if v, ok := res.Set().Value(key); ok && v.Emit() != "" {
+			return url.PathEscape(v.Emit())
+		}
+		return "unknown"
+	})
+}
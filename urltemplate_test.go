@@ -0,0 +1,62 @@
+package httpExporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// paths returns the sorted paths of the requests received by c.
+func (c *collector) paths() []string {
+	var paths []string
+	for _, r := range c.received() {
+		paths = append(paths, r.Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestURLPath(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL()+"/ignored", httpExporter.WithURLPath("/custom/traces"), httpExporter.WithFormat(httpExporter.Zipkin))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.paths(); !equal(got, "/custom/traces") {
+		t.Errorf("collector received requests to %v, want /custom/traces", got)
+	}
+}
+
+func TestTemplatedURLPath(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithURLPath("/{service}/{deployment.environment}/v1/traces"))
+
+	checkout := resource.NewSchemaless(attribute.String("service.name", "checkout"), attribute.String("deployment.environment", "prod"))
+	billing := resource.NewSchemaless(attribute.String("service.name", "billing service"))
+	var spans []sdktrace.ReadOnlySpan
+	spans = append(spans, newSpanWithAttributes(checkout)...)
+	spans = append(spans, newSpanWithAttributes(billing)...)
+	spans = append(spans, newSpanWithAttributes(checkout)...)
+	if err := e.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	if got := c.paths(); !equal(got, "/billing service/unknown/v1/traces", "/checkout/prod/v1/traces") {
+		t.Errorf("collector received requests to %q, want one per resource", got)
+	}
+	for _, r := range c.received() {
+		var batch []span
+		if err := json.Unmarshal(r.Body, &batch); err != nil {
+			t.Fatalf("invalid batch %s: %v", r.Body, err)
+		}
+		if want := map[bool]int{true: 2, false: 1}[r.Path == "/checkout/prod/v1/traces"]; len(batch) != want {
+			t.Errorf("request to %s holds %d spans, want %d", r.Path, len(batch), want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package httpExporter
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the import path of this module.
+const modulePath = "github.com/Syn3rman/httpExporter"
+
+// WithUserAgent configures the User-Agent header of requests, which
+// defaults to httpExporter/<version> Go/<go version>.
+func WithUserAgent(userAgent string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.userAgent = userAgent
+		return cfg
+	})
+}
+
+var (
+	defaultUserAgentOnce sync.Once
+	defaultUserAgentStr  string
+)
+
+// defaultUserAgent returns the default User-Agent header, holding the
+// version of the module the binary was built with.
+func defaultUserAgent() string {
+	defaultUserAgentOnce.Do(func() {
+		version := "devel"
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, dep := range append([]*debug.Module{&info.Main}, info.Deps...) {
+				if dep.Path == modulePath && dep.Version != "" && dep.Version != "(devel)" {
+					version = dep.Version
+					break
+				}
+			}
+		}
+		defaultUserAgentStr = "httpExporter/" + version + " " + "Go/" + runtime.Version()
+	})
+	return defaultUserAgentStr
+}
@@ -0,0 +1,43 @@
+package httpExporter_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+func TestUserAgent(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL())
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	ua := c.received()[0].Header.Get("User-Agent")
+	if !strings.HasPrefix(ua, "httpExporter/") || !strings.Contains(ua, " Go/go") {
+		t.Errorf("User-Agent = %q, want httpExporter/<version> Go/<go version>", ua)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	c := newCollector(t)
+	e := newExporter(t, c.URL(), httpExporter.WithUserAgent("checkout/1.0"))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.received()[0].Header.Get("User-Agent"); got != "checkout/1.0" {
+		t.Errorf("User-Agent = %q, want checkout/1.0", got)
+	}
+}
+
+func TestUserAgentFromConfig(t *testing.T) {
+	c := newCollector(t)
+	e := newExporterFromConfig(t, writeConfig(t, "exporter.yaml", "endpoint: "+c.URL()+"\nuserAgent: checkout/1.0\n"))
+	if err := e.ExportSpans(context.Background(), newSpans("a")); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	if got := c.received()[0].Header.Get("User-Agent"); got != "checkout/1.0" {
+		t.Errorf("User-Agent = %q, want checkout/1.0", got)
+	}
+}
@@ -0,0 +1,174 @@
+package httpExporter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// validate checks a resolved configuration for invalid and conflicting
+// settings. It returns an *ErrInvalidConfig listing all of them, or nil.
+func (cfg config) validate() error {
+	problems := append([]string(nil), cfg.envProblems...)
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for _, setting := range []struct {
+		name  string
+		value fmt.Stringer
+	}{
+		{"format", cfg.format},
+		{"compression", cfg.compression},
+		{"queue policy", cfg.queuePolicy},
+		{"backpressure policy", cfg.backpressure},
+		{"balancing policy", cfg.balancingPolicy},
+		{"rate limit policy", cfg.ratePolicy},
+		{"payload layout", cfg.payloadLayout},
+		{"ID encoding", cfg.idEncoding},
+		{"event log format", cfg.eventLogFormat},
+	} {
+		if setting.value.String() == "unknown" {
+			addf("unknown %s %d", setting.name, setting.value)
+		}
+	}
+	if cfg.payloadVersion != 0 && cfg.payloadVersion.String() == "unknown" {
+		addf("unknown payload version %d", cfg.payloadVersion)
+	}
+
+	if cfg.timeout < 0 {
+		addf("negative timeout %v", cfg.timeout)
+	}
+	if cfg.retry.Enabled {
+		if cfg.retry.InitialInterval < 0 || cfg.retry.MaxInterval < 0 || cfg.retry.MaxElapsedTime < 0 {
+			addf("negative retry interval")
+		}
+		if cfg.retry.MaxInterval > 0 && cfg.retry.MaxInterval < cfg.retry.InitialInterval {
+			addf("retry MaxInterval %v is shorter than InitialInterval %v", cfg.retry.MaxInterval, cfg.retry.InitialInterval)
+		}
+	}
+	if cfg.breakerCooldown < 0 {
+		addf("negative circuit breaker cooldown %v", cfg.breakerCooldown)
+	}
+	if cfg.failoverThreshold < 0 {
+		addf("negative failover threshold %d", cfg.failoverThreshold)
+	}
+	if cfg.failoverProbeInterval < 0 {
+		addf("negative failover probe interval %v", cfg.failoverProbeInterval)
+	}
+	if cfg.rateLimit < 0 {
+		addf("negative rate limit %v", cfg.rateLimit)
+	}
+	if cfg.maxPayloadBytes < 0 {
+		addf("negative maximum payload size %d", cfg.maxPayloadBytes)
+	}
+	if cfg.maxConcurrentExports < 0 {
+		addf("negative maximum of concurrent exports %d", cfg.maxConcurrentExports)
+	}
+	if cfg.dedupSize < 0 {
+		addf("negative deduplication cache size %d", cfg.dedupSize)
+	}
+
+	if cfg.proxyURL != "" {
+		if u, err := url.Parse(cfg.proxyURL); err != nil || u.Host == "" {
+			addf("invalid proxy URL %q", cfg.proxyURL)
+		}
+	}
+	// The environment only sets a default, which WithTLSClientConfig
+	// overrides.
+	if cfg.insecure && !cfg.envInsecure && cfg.tlsConfig != nil {
+		addf("WithInsecure conflicts with WithTLSClientConfig")
+	}
+	if cfg.client != nil {
+		if cfg.transport != (TransportConfig{}) {
+			addf("WithTransportConfig has no effect on a client configured with WithClient")
+		}
+		if cfg.tlsConfig != nil || cfg.proxyURL != "" {
+			switch t := cfg.client.Transport.(type) {
+			case nil:
+			case *http.Transport:
+				if cfg.tlsConfig != nil && t.TLSClientConfig != nil {
+					addf("WithTLSClientConfig conflicts with the TLS settings of the client configured with WithClient")
+				}
+			default:
+				addf("TLS and proxy settings cannot be applied to a client transport of type %T", t)
+			}
+		}
+		if _, ok := cfg.client.Transport.(*http.Transport); !ok && cfg.client.Transport != nil {
+			for _, rawURL := range cfg.collectorURLs() {
+				if isUnixSocketURL(rawURL) {
+					addf("unix domain socket collector %q cannot be dialed by a client transport of type %T", rawURL, cfg.client.Transport)
+				}
+			}
+		}
+	}
+	if cfg.encryptionKey != nil {
+		if _, err := newEncrypter(cfg.encryptionKey); err != nil {
+			addf("%v", err)
+		}
+	}
+
+	if cfg.streaming {
+		if cfg.compression == GzipCompression && !cfg.envCompressed {
+			addf("WithStreaming conflicts with gzip compression")
+		}
+		if cfg.encryptionKey != nil {
+			addf("WithStreaming conflicts with WithPayloadEncryption")
+		}
+		for _, rawURL := range append(cfg.collectorURLs(), cfg.urlPath) {
+			if templated(rawURL) {
+				addf("WithStreaming conflicts with the templated collector URL %q", rawURL)
+			}
+		}
+	}
+
+	if cfg.signal != "" && cfg.format != JSON {
+		addf("%s are only exported in the JSON format, not %s", cfg.signal, cfg.format)
+	}
+
+	if cfg.resourceReferences {
+		switch {
+		case cfg.format != JSON:
+			addf("WithResourceReferences requires the JSON format")
+		case cfg.payloadLayout == ResourceLayout:
+			addf("WithResourceReferences conflicts with the resource layout")
+		}
+		if len(cfg.additionalEndpoints) > 0 {
+			addf("WithResourceReferences conflicts with WithAdditionalEndpoints")
+		}
+		if cfg.streaming {
+			addf("WithResourceReferences conflicts with WithStreaming")
+		}
+		if cfg.persistence.Dir != "" {
+			addf("WithResourceReferences conflicts with WithPersistence")
+		}
+	}
+
+	switch enc := newEncoder(cfg).(type) {
+	case cloudTraceEncoder:
+		if enc.project == "" {
+			addf("no Google Cloud project configured for the CloudTrace format")
+		}
+	case appInsightsEncoder:
+		if enc.iKey == "" {
+			addf("no instrumentation key configured for the ApplicationInsights format")
+		}
+	case kafkaRESTEncoder:
+		if enc.topic == "" {
+			addf("no topic configured for the KafkaREST format")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ErrInvalidConfig{Problems: problems}
+}
+
+// collectorURLs returns the configured collector URLs: the primary one and
+// its replicas, fallbacks and mirrors.
+func (cfg config) collectorURLs() []string {
+	urls := append([]string{cfg.collectorURL}, cfg.poolEndpoints...)
+	urls = append(urls, cfg.fallbackEndpoints...)
+	return append(urls, cfg.additionalEndpoints...)
+}
@@ -0,0 +1,233 @@
+package httpExporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	walSuffix = ".batch"
+
+	walReplayInitialInterval = 5 * time.Second
+	walReplayMaxInterval     = 5 * time.Minute
+)
+
+// PersistenceConfig defines the write-ahead log of batches that could not be
+// delivered.
+type PersistenceConfig struct {
+	// Dir is the directory batches are persisted to. It is created if it
+	// does not exist.
+	Dir string
+	// MaxBytes bounds the total size of persisted batches. The oldest
+	// batches are discarded once it is exceeded. Defaults to 100 MiB.
+	MaxBytes int64
+	// MaxAge is how long a persisted batch is kept before it is discarded.
+	// Defaults to 24 hours.
+	MaxAge time.Duration
+}
+
+// WithPersistence configures the exporter to persist batches the collector is
+// unavailable for to disk, and to replay them with exponential backoff until
+// the collector accepts them. Batches persisted by a previous process are
+// replayed too. ExportSpans still reports the delivery failure.
+func WithPersistence(pc PersistenceConfig) Option {
+	return optionFunc(func(cfg config) config {
+		if pc.MaxBytes <= 0 {
+			pc.MaxBytes = 100 << 20
+		}
+		if pc.MaxAge <= 0 {
+			pc.MaxAge = 24 * time.Hour
+		}
+		cfg.persistence = pc
+		return cfg
+	})
+}
+
+// wal persists undelivered batches, one file per batch holding the content
+// type on its first line followed by the request body. With deduplication,
+// the first line also holds the keys of the spans of the batch, after a tab.
+type wal struct {
+	PersistenceConfig
+
+	mu       sync.Mutex // Serializes directory listings and removals
+	replayMu sync.Mutex // Serializes replays
+	seq      uint64     // Disambiguates files persisted at the same instant
+
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the replayer returned
+}
+
+func newWAL(pc PersistenceConfig) (*wal, error) {
+	if err := os.MkdirAll(pc.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create persistence directory: %v", err)
+	}
+	return &wal{PersistenceConfig: pc, done: make(chan struct{})}, nil
+}
+
+// persist writes a batch to the log and enforces the retention limits.
+func (w *wal) persist(contentType string, keys []spanKey, body []byte) error {
+	name := fmt.Sprintf("%020d-%010d", time.Now().UnixNano(), atomic.AddUint64(&w.seq, 1))
+	tmp := filepath.Join(w.Dir, name+".tmp")
+	header := contentType
+	if len(keys) > 0 {
+		strs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			strs = append(strs, key.String())
+		}
+		header += "\t" + strings.Join(strs, ",")
+	}
+	data := make([]byte, 0, len(header)+1+len(body))
+	data = append(append(append(data, header...), '\n'), body...)
+	if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to persist batch: %v", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(w.Dir, name+walSuffix)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to persist batch: %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	files := w.files()
+	var total int64
+	for _, f := range files {
+		total += f.Size()
+	}
+	for _, f := range files {
+		if total <= w.MaxBytes {
+			break
+		}
+		os.Remove(filepath.Join(w.Dir, f.Name()))
+		total -= f.Size()
+	}
+	return nil
+}
+
+// files lists the persisted batches, oldest first, removing expired ones.
+func (w *wal) files() []os.FileInfo {
+	infos, err := ioutil.ReadDir(w.Dir)
+	if err != nil {
+		return nil
+	}
+	files := infos[:0]
+	for _, f := range infos {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), walSuffix) {
+			continue
+		}
+		if time.Since(f.ModTime()) > w.MaxAge {
+			os.Remove(filepath.Join(w.Dir, f.Name()))
+			continue
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return files
+}
+
+// start replays persisted batches in the background until close is called.
+func (w *wal) start(e *Exporter) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	go func() {
+		defer close(w.done)
+		interval := walReplayInitialInterval
+		for {
+			t := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+			if w.replay(ctx, e) {
+				interval = walReplayInitialInterval
+			} else if interval *= 2; interval > walReplayMaxInterval {
+				interval = walReplayMaxInterval
+			}
+		}
+	}()
+}
+
+// replay sends persisted batches to the collector, oldest first, removing
+// the delivered ones. It reports whether the log was emptied.
+func (w *wal) replay(ctx context.Context, e *Exporter) bool {
+	w.replayMu.Lock()
+	defer w.replayMu.Unlock()
+	w.mu.Lock()
+	files := w.files()
+	w.mu.Unlock()
+	for _, f := range files {
+		path := filepath.Join(w.Dir, f.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			// Discarded by the retention limits meanwhile.
+			continue
+		}
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			e.warnf("discarding corrupt persisted batch %s", path)
+			os.Remove(path)
+			continue
+		}
+		contentType, keys := parseWALHeader(string(data[:i]))
+		if e.dedup != nil && e.dedup.acknowledged(keys) {
+			e.logf("discarding persisted batch %s acknowledged meanwhile", path)
+			w.mu.Lock()
+			os.Remove(path)
+			w.mu.Unlock()
+			continue
+		}
+		err = e.deliverPrimary(ctx, contentType, data[i+1:])
+		if err == nil && e.dedup != nil {
+			e.dedup.add(keys)
+		}
+		if err != nil && (unavailable(err) || ctx.Err() != nil) {
+			e.warnf("failed to replay persisted batches: %v", err)
+			return false
+		}
+		if err != nil {
+			e.warnf("discarding persisted batch %s rejected by the collector: %v", path, err)
+		}
+		w.mu.Lock()
+		os.Remove(path)
+		w.mu.Unlock()
+	}
+	return true
+}
+
+// parseWALHeader parses the first line of a persisted batch. Its span keys
+// are ignored if any is invalid.
+func parseWALHeader(header string) (contentType string, keys []spanKey) {
+	contentType, list, found := strings.Cut(header, "\t")
+	if !found {
+		return contentType, nil
+	}
+	for _, s := range strings.Split(list, ",") {
+		key, ok := parseSpanKey(s)
+		if !ok {
+			return contentType, nil
+		}
+		keys = append(keys, key)
+	}
+	return contentType, keys
+}
+
+// close stops the replayer.
+func (w *wal) close(ctx context.Context) error {
+	w.cancel()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
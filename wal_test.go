@@ -0,0 +1,102 @@
+package httpExporter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	httpExporter "github.com/Syn3rman/httpExporter"
+)
+
+// persistedBatches returns the batches persisted to dir, oldest first.
+func persistedBatches(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.batch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// persistedNames returns the names of the spans of a persisted batch.
+func persistedNames(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 || string(data[:i]) != "application/json" {
+		t.Fatalf("persisted batch %q does not start with its content type", data)
+	}
+	var batch []span
+	if err := json.Unmarshal(data[i+1:], &batch); err != nil {
+		t.Fatalf("invalid persisted batch %s: %v", data[i+1:], err)
+	}
+	return spanNames(batch)
+}
+
+func TestPersistence(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	dir := filepath.Join(t.TempDir(), "wal")
+	e := newExporter(t, c.URL(), httpExporter.WithPersistence(httpExporter.PersistenceConfig{Dir: dir}))
+
+	ctx := context.Background()
+	for _, name := range []string{"first", "second"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err == nil {
+			t.Fatal("ExportSpans succeeded while the collector is unavailable")
+		}
+	}
+	files := persistedBatches(t, dir)
+	if len(files) != 2 {
+		t.Fatalf("persisted %d batches, want 2", len(files))
+	}
+	if got := persistedNames(t, files[0]); !equal(got, "first") {
+		t.Errorf("oldest persisted batch holds %v, want [first]", got)
+	}
+	if got := persistedNames(t, files[1]); !equal(got, "second") {
+		t.Errorf("newest persisted batch holds %v, want [second]", got)
+	}
+}
+
+func TestPersistenceRejectedBatch(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusBadRequest)
+	dir := t.TempDir()
+	e := newExporter(t, c.URL(), httpExporter.WithPersistence(httpExporter.PersistenceConfig{Dir: dir}))
+
+	if err := e.ExportSpans(context.Background(), newSpans("rejected")); err == nil {
+		t.Fatal("ExportSpans succeeded with a 400 response")
+	}
+	// Replaying a batch the collector rejected would not succeed either.
+	if got := persistedBatches(t, dir); len(got) != 0 {
+		t.Errorf("persisted %d rejected batches, want none", len(got))
+	}
+}
+
+func TestPersistenceMaxBytes(t *testing.T) {
+	c := newCollector(t)
+	c.setStatus(http.StatusServiceUnavailable)
+	dir := t.TempDir()
+	// Room for about two batches of a span.
+	e := newExporter(t, c.URL(), httpExporter.WithPersistence(httpExporter.PersistenceConfig{Dir: dir, MaxBytes: 1000}))
+
+	ctx := context.Background()
+	for _, name := range []string{"discarded", "kept 1", "kept 2"} {
+		if err := e.ExportSpans(ctx, newSpans(name)); err == nil {
+			t.Fatal("ExportSpans succeeded while the collector is unavailable")
+		}
+	}
+	files := persistedBatches(t, dir)
+	if len(files) != 2 {
+		t.Fatalf("persisted %d batches, want the 2 newest", len(files))
+	}
+	if got := persistedNames(t, files[0]); !equal(got, "kept 1") {
+		t.Errorf("oldest persisted batch holds %v, want [kept 1]", got)
+	}
+}
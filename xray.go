@@ -0,0 +1,216 @@
+package httpExporter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// xrayTraceSegmentsPath is the X-Ray PutTraceSegments API path, also served
+// by the X-Ray daemon's local proxy.
+const xrayTraceSegmentsPath = "/TraceSegments"
+
+// xrayEncoder encodes spans as an X-Ray PutTraceSegments request. Server and
+// root spans become segments, every other span a subsegment.
+type xrayEncoder struct{}
+
+func (xrayEncoder) contentType() string { return "application/json" }
+
+func (xrayEncoder) defaultPath() string { return xrayTraceSegmentsPath }
+
+func (xrayEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	req := xrayPutTraceSegments{
+		TraceSegmentDocuments: make([]string, 0, len(spans)),
+	}
+	for _, span := range spans {
+		doc, err := json.Marshal(spanToXRay(span))
+		if err != nil {
+			return nil, err
+		}
+		req.TraceSegmentDocuments = append(req.TraceSegmentDocuments, string(doc))
+	}
+	return json.Marshal(req)
+}
+
+type xrayPutTraceSegments struct {
+	TraceSegmentDocuments []string `json:"TraceSegmentDocuments"`
+}
+
+type xraySegment struct {
+	Name        string                            `json:"name"`
+	ID          string                            `json:"id"`
+	TraceID     string                            `json:"trace_id"`
+	ParentID    string                            `json:"parent_id,omitempty"`
+	Type        string                            `json:"type,omitempty"`
+	Namespace   string                            `json:"namespace,omitempty"`
+	StartTime   float64                           `json:"start_time"` // Epoch seconds
+	EndTime     float64                           `json:"end_time"`   // Epoch seconds
+	Fault       bool                              `json:"fault,omitempty"`
+	Cause       *xrayCause                        `json:"cause,omitempty"`
+	Annotations map[string]interface{}            `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type xrayCause struct {
+	Exceptions []xrayException `json:"exceptions"`
+}
+
+type xrayException struct {
+	ID      string `json:"id"`
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// spanToXRay converts a span to an X-Ray segment or subsegment document.
+func spanToXRay(span sdktrace.ReadOnlySpan) xraySegment {
+	sc := span.SpanContext()
+	seg := xraySegment{
+		Name:      span.Name(),
+		ID:        sc.SpanID().String(),
+		TraceID:   xrayTraceID(sc.TraceID()),
+		StartTime: xrayTime(span.StartTime()),
+		EndTime:   xrayTime(span.EndTime()),
+	}
+	psid := span.Parent().SpanID()
+	if psid.IsValid() {
+		seg.ParentID = psid.String()
+	}
+	if span.SpanKind() == trace.SpanKindServer || !psid.IsValid() {
+		if name := serviceName(span.Resource()); name != "" {
+			seg.Name = name
+		}
+	} else {
+		seg.Type = "subsegment"
+	}
+	if kind := span.SpanKind(); kind == trace.SpanKindClient || kind == trace.SpanKindProducer {
+		seg.Namespace = "remote"
+	}
+	seg.Name = xraySegmentName(seg.Name)
+
+	if status := span.Status(); status.Code == codes.Error {
+		seg.Fault = true
+	}
+	for i, ev := range span.Events() {
+		if ev.Name != semconv.ExceptionEventName {
+			continue
+		}
+		ex := xrayException{ID: xrayExceptionID(sc.SpanID(), i)}
+		for _, kv := range ev.Attributes {
+			switch kv.Key {
+			case semconv.ExceptionTypeKey:
+				ex.Type = kv.Value.Emit()
+			case semconv.ExceptionMessageKey:
+				ex.Message = kv.Value.Emit()
+			}
+		}
+		if seg.Cause == nil {
+			seg.Cause = &xrayCause{}
+		}
+		seg.Cause.Exceptions = append(seg.Cause.Exceptions, ex)
+	}
+
+	for _, kv := range span.Attributes() {
+		switch kv.Value.Type() {
+		case attribute.BOOL, attribute.INT64, attribute.FLOAT64, attribute.STRING:
+			if seg.Annotations == nil {
+				seg.Annotations = make(map[string]interface{})
+			}
+			seg.Annotations[xrayAnnotationKey(kv.Key)] = kv.Value.AsInterface()
+		default:
+			// Annotations only support scalar values.
+			if seg.Metadata == nil {
+				seg.Metadata = map[string]map[string]interface{}{"default": {}}
+			}
+			seg.Metadata["default"][string(kv.Key)] = kv.Value.AsInterface()
+		}
+	}
+	return seg
+}
+
+// xrayTraceID converts a trace ID to the X-Ray format
+// 1-{8 hex digit epoch}-{24 hex digit identifier}. The first 4 bytes of the
+// trace ID are used as the epoch, as done by XRayIDGenerator. The mapping is
+// kept reversible, rather than taking the epoch from the span start, so that
+// all the spans of a trace keep the same X-Ray trace ID and it still matches
+// the trace ID propagated to other services.
+func xrayTraceID(tid trace.TraceID) string {
+	s := tid.String()
+	return "1-" + s[:8] + "-" + s[8:]
+}
+
+// XRayIDGenerator returns an ID generator whose trace IDs start with the
+// current time in epoch seconds, as X-Ray expects of the first 8 hex digits
+// of its trace IDs: it rejects segments whose trace ID epoch is more than 30
+// days old or in the future, which random trace IDs almost always are. Set
+// it on the tracer provider with sdktrace.WithIDGenerator when exporting with
+// the XRay format; NewTracerProvider does.
+func XRayIDGenerator() sdktrace.IDGenerator {
+	return xrayIDGenerator{}
+}
+
+type xrayIDGenerator struct{}
+
+func (g xrayIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var tid trace.TraceID
+	binary.BigEndian.PutUint32(tid[:4], uint32(time.Now().Unix()))
+	_, _ = rand.Read(tid[4:])
+	return tid, g.NewSpanID(ctx, tid)
+}
+
+func (xrayIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var sid trace.SpanID
+	for !sid.IsValid() {
+		_, _ = rand.Read(sid[:])
+	}
+	return sid
+}
+
+// xrayTime converts a time to fractional epoch seconds.
+func xrayTime(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// xrayExceptionID derives a 16 hex digit exception ID from the span ID and
+// the event index.
+func xrayExceptionID(sid trace.SpanID, i int) string {
+	sid[len(sid)-1] ^= byte(i)
+	return sid.String()
+}
+
+// xraySegmentName replaces characters X-Ray does not accept in segment names
+// and truncates the name to 200 characters.
+func xraySegmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune(" _.:/%&#=+\\-@", r):
+			return r
+		}
+		return '_'
+	}, name)
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return name
+}
+
+// xrayAnnotationKey replaces characters that are not allowed in annotation
+// keys, which are limited to alphanumerics and underscores.
+func xrayAnnotationKey(key attribute.Key) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, string(key))
+}
@@ -0,0 +1,68 @@
+package httpExporter
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXRay(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(XRay))
+	checkEncoder(t, enc, "application/json", "/TraceSegments")
+	checkGoldenJSON(t, "xray.json", body)
+}
+
+func TestXRaySegmentName(t *testing.T) {
+	for name, want := range map[string]string{
+		"GET /cart":      "GET /cart",
+		"emoji 🛒 (cart)": "emoji _ _cart_",
+	} {
+		if got := xraySegmentName(name); got != want {
+			t.Errorf("xraySegmentName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// xrayEpoch returns the epoch of an X-Ray trace ID.
+func xrayEpoch(t *testing.T, id string) time.Time {
+	t.Helper()
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || parts[0] != "1" || len(parts[1]) != 8 || len(parts[2]) != 24 {
+		t.Fatalf("invalid X-Ray trace ID %q", id)
+	}
+	epoch, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		t.Fatalf("invalid X-Ray trace ID %q: %v", id, err)
+	}
+	return time.Unix(int64(epoch), 0)
+}
+
+func TestXRayTraceID(t *testing.T) {
+	if got, want := xrayTraceID(testTraceID), "1-66322d80-5a1b2c3d4e5f60718293a4b5"; got != want {
+		t.Errorf("xrayTraceID = %s, want %s", got, want)
+	}
+	before := time.Now().Truncate(time.Second)
+	tid, sid := XRayIDGenerator().NewIDs(context.Background())
+	if !tid.IsValid() || !sid.IsValid() {
+		t.Fatalf("NewIDs returned invalid IDs %s, %s", tid, sid)
+	}
+	if epoch := xrayEpoch(t, xrayTraceID(tid)); epoch.Before(before) || epoch.After(time.Now()) {
+		t.Errorf("trace ID epoch %v, want the current time", epoch)
+	}
+}
+
+func TestXRayTracerProvider(t *testing.T) {
+	tp, err := NewTracerProvider("http://localhost:4318", WithFormat(XRay))
+	if err != nil {
+		t.Fatalf("NewTracerProvider: %v", err)
+	}
+	defer tp.Shutdown(context.Background())
+	before := time.Now().Truncate(time.Second)
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	id := xrayTraceID(span.SpanContext().TraceID())
+	if epoch := xrayEpoch(t, id); epoch.Before(before) || epoch.After(time.Now()) {
+		t.Errorf("trace ID %s has epoch %v, want the current time", id, epoch)
+	}
+}
@@ -0,0 +1,127 @@
+package httpExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// zipkinSpansPath is the Zipkin v2 span ingestion path.
+const zipkinSpansPath = "/api/v2/spans"
+
+// zipkinEncoder encodes spans as a Zipkin v2 JSON span list.
+type zipkinEncoder struct{}
+
+func (zipkinEncoder) contentType() string { return "application/json" }
+
+func (zipkinEncoder) defaultPath() string { return zipkinSpansPath }
+
+func (zipkinEncoder) encode(spans []sdktrace.ReadOnlySpan) ([]byte, error) {
+	zspans := make([]zipkinSpan, 0, len(spans))
+	for _, span := range spans {
+		zspans = append(zspans, spanToZipkin(span))
+	}
+	return json.Marshal(zspans)
+}
+
+type zipkinSpan struct {
+	TraceID       string             `json:"traceId"`
+	ID            string             `json:"id"`
+	ParentID      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name"`
+	Kind          string             `json:"kind,omitempty"`
+	Timestamp     int64              `json:"timestamp"` // Epoch microseconds
+	Duration      int64              `json:"duration"`  // Microseconds
+	LocalEndpoint *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"` // Epoch microseconds
+	Value     string `json:"value"`
+}
+
+// spanToZipkin converts a span to a Zipkin v2 span.
+func spanToZipkin(span sdktrace.ReadOnlySpan) zipkinSpan {
+	zs := zipkinSpan{
+		TraceID:   span.SpanContext().TraceID().String(),
+		ID:        span.SpanContext().SpanID().String(),
+		Name:      span.Name(),
+		Kind:      zipkinKind(span.SpanKind()),
+		Timestamp: span.StartTime().UnixNano() / int64(time.Microsecond),
+		Duration:  span.EndTime().Sub(span.StartTime()).Microseconds(),
+	}
+	if psid := span.Parent().SpanID(); psid.IsValid() {
+		zs.ParentID = psid.String()
+	}
+	if name := serviceName(span.Resource()); name != "" {
+		zs.LocalEndpoint = &zipkinEndpoint{ServiceName: name}
+	}
+	for _, ev := range span.Events() {
+		zs.Annotations = append(zs.Annotations, zipkinAnnotation{
+			Timestamp: ev.Time.UnixNano() / int64(time.Microsecond),
+			Value:     zipkinAnnotationValue(ev),
+		})
+	}
+
+	tags := make(map[string]string, len(span.Attributes())+4)
+	for _, kv := range span.Attributes() {
+		tags[string(kv.Key)] = attributeValueString(kv.Value)
+	}
+	if lib := span.InstrumentationLibrary(); lib.Name != "" {
+		tags["otel.library.name"] = lib.Name
+		if lib.Version != "" {
+			tags["otel.library.version"] = lib.Version
+		}
+	}
+	switch status := span.Status(); status.Code {
+	case codes.Ok:
+		tags["otel.status_code"] = "OK"
+	case codes.Error:
+		tags["otel.status_code"] = "ERROR"
+		// Zipkin marks a span as failed by the presence of the error tag.
+		tags["error"] = status.Description
+	}
+	if len(tags) > 0 {
+		zs.Tags = tags
+	}
+	return zs
+}
+
+// zipkinKind maps a span kind to a Zipkin kind. Internal and unspecified
+// spans have no Zipkin kind.
+func zipkinKind(kind trace.SpanKind) string {
+	switch kind {
+	case trace.SpanKindServer:
+		return "SERVER"
+	case trace.SpanKindClient:
+		return "CLIENT"
+	case trace.SpanKindProducer:
+		return "PRODUCER"
+	case trace.SpanKindConsumer:
+		return "CONSUMER"
+	}
+	return ""
+}
+
+// zipkinAnnotationValue renders an event as an annotation value. Zipkin
+// annotations carry no attributes, so they are appended as a JSON object.
+func zipkinAnnotationValue(ev sdktrace.Event) string {
+	if len(ev.Attributes) == 0 {
+		return ev.Name
+	}
+	b, err := json.Marshal(Attributes(ev.Attributes))
+	if err != nil {
+		return ev.Name
+	}
+	return fmt.Sprintf("%s: %s", ev.Name, b)
+}
@@ -0,0 +1,9 @@
+package httpExporter
+
+import "testing"
+
+func TestZipkin(t *testing.T) {
+	enc, body := encodeTestSpans(t, WithFormat(Zipkin))
+	checkEncoder(t, enc, "application/json", "/api/v2/spans")
+	checkGoldenJSON(t, "zipkin.json", body)
+}